@@ -51,6 +51,8 @@ type adminAdvertiserGetResponse struct {
 
 // Get gets advertiser.
 func (s *AdminAdvertiserService) Get(ctx context.Context, id string) (*Advertiser, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAdvertiser.Get")
+
 	path := fmt.Sprintf("/3.0/admin/advertiser/%s", id)
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, true)
@@ -83,6 +85,8 @@ type adminAdvertiserListResponse struct {
 
 // List gets a list of advertisers.
 func (s *AdminAdvertiserService) List(ctx context.Context, opts *AdminAdvertiserListOpts) ([]*Advertiser, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAdvertiser.List")
+
 	path := "/3.0/admin/advertisers"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, true)
@@ -99,6 +103,20 @@ func (s *AdminAdvertiserService) List(ctx context.Context, opts *AdminAdvertiser
 	return body.Advertisers, resp, nil
 }
 
+// ListIter returns an iterator over all pages of List starting from opts.Page.
+func (s *AdminAdvertiserService) ListIter(ctx context.Context, opts *AdminAdvertiserListOpts) *AdvertiserIter {
+	o := AdminAdvertiserListOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newAdvertiserIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Advertiser, *Response, error) {
+		o.Page = page
+
+		return s.List(ctx, &o)
+	})
+}
+
 // AdminAdvertiserCreateOpts specifies options for Create.
 type AdminAdvertiserCreateOpts struct {
 	Title                         string   `schema:"title"`                                      // REQUIRED Company name
@@ -129,6 +147,8 @@ type adminAdvertiserCreateResponse struct {
 
 // Create adds new advertiser.
 func (s *AdminAdvertiserService) Create(ctx context.Context, opts *AdminAdvertiserCreateOpts) (*Advertiser, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAdvertiser.Create")
+
 	path := "/3.0/admin/advertiser"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -176,6 +196,8 @@ type adminAdvertiserUpdateResponse struct {
 
 // Update changes an advertiser’s data.
 func (s *AdminAdvertiserService) Update(ctx context.Context, id string, opts *AdminAdvertiserUpdateOpts) (*Advertiser, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAdvertiser.Update")
+
 	path := fmt.Sprintf("/3.0/admin/advertiser/%s", id)
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -194,6 +216,8 @@ func (s *AdminAdvertiserService) Update(ctx context.Context, id string, opts *Ad
 
 // SendPassword changes an advertiser password and send it by email.
 func (s *AdminAdvertiserService) SendPassword(ctx context.Context, id string) (*Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAdvertiser.SendPassword")
+
 	path := fmt.Sprintf("/3.0/admin/advertiser/%s/sendpass", id)
 
 	req, err := s.client.NewRequest(ctx, http.MethodPost, path, nil, true)
@@ -217,6 +241,8 @@ type AdminAdvertiserEnableAffiliateOpts struct {
 
 // EnableAffiliate un-puts affiliate from blacklist for specified advertisers.
 func (s *AdminAdvertiserService) EnableAffiliate(ctx context.Context, opts *AdminAdvertiserEnableAffiliateOpts) (*Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAdvertiser.EnableAffiliate")
+
 	path := "/3.0/admin/advertiser/enable-affiliate"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -240,6 +266,8 @@ type AdminAdvertiserDisableAffiliateOpts struct {
 
 // DisableAffiliate puts affiliate to blacklist for specified advertisers.
 func (s *AdminAdvertiserService) DisableAffiliate(ctx context.Context, opts *AdminAdvertiserDisableAffiliateOpts) (*Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAdvertiser.DisableAffiliate")
+
 	path := "/3.0/admin/advertiser/disable-affiliate"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
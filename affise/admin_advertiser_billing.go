@@ -56,6 +56,8 @@ type adminAdvertiserBillingListResponse struct {
 
 // List gets list of invoices.
 func (s *AdminAdvertiserBillingService) List(ctx context.Context, opts *AdminAdvertiserBillingListOpts) ([]*Message, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAdvertiserBilling.List")
+
 	path := "/3.0/admin/advertiser-invoices"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, true)
@@ -72,6 +74,20 @@ func (s *AdminAdvertiserBillingService) List(ctx context.Context, opts *AdminAdv
 	return body.Message, resp, nil
 }
 
+// ListIter returns an iterator over all pages of List starting from opts.Page.
+func (s *AdminAdvertiserBillingService) ListIter(ctx context.Context, opts *AdminAdvertiserBillingListOpts) *MessageIter {
+	o := AdminAdvertiserBillingListOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newMessageIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Message, *Response, error) {
+		o.Page = page
+
+		return s.List(ctx, &o)
+	})
+}
+
 // adminAdvertiserBillingGetResponse specifies response for Get.
 type adminAdvertiserBillingGetResponse struct {
 	Message *Message `json:"message"`
@@ -79,6 +95,8 @@ type adminAdvertiserBillingGetResponse struct {
 
 // Get gets a invoice.
 func (s *AdminAdvertiserBillingService) Get(ctx context.Context, number int) (*Message, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAdvertiserBilling.Get")
+
 	path := fmt.Sprintf("/3.0/admin/advertiser-invoice/%d", number)
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, true)
@@ -121,6 +139,8 @@ func (opts *AdminAdvertiserBillingCreateOpts) values() (url.Values, error) {
 
 // Create adds new invoice.
 func (s *AdminAdvertiserBillingService) Create(ctx context.Context, opts *AdminAdvertiserBillingCreateOpts) (*Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAdvertiserBilling.Create")
+
 	path := "/3.0/admin/advertiser-invoice"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -161,6 +181,8 @@ func (opts *AdminAdvertiserBillingUpdateOpts) values() (url.Values, error) {
 
 // Update changes an invoice’s data.
 func (s *AdminAdvertiserBillingService) Update(ctx context.Context, number int, opts *AdminAdvertiserBillingUpdateOpts) (*Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAdvertiserBilling.Update")
+
 	path := fmt.Sprintf("/3.0/admin/advertiser-invoice/%d", number)
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
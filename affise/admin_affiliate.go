@@ -96,6 +96,8 @@ type adminAffiliateGetResponse struct {
 
 // Get gets affiliate.
 func (s *AdminAffiliateService) Get(ctx context.Context, id uint64) (*Affiliate, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAffiliate.Get")
+
 	path := fmt.Sprintf("/3.0/admin/partner/%d", id)
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, true)
@@ -129,6 +131,8 @@ type adminAffiliateListPartnersResponse struct {
 
 // ListPartners gets list of a partners.
 func (s *AdminAffiliateService) ListPartners(ctx context.Context, opts *AdminAffiliateListPartnersOpts) ([]*Affiliate, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAffiliate.ListPartners")
+
 	path := "/3.0/admin/partners"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, true)
@@ -145,6 +149,20 @@ func (s *AdminAffiliateService) ListPartners(ctx context.Context, opts *AdminAff
 	return body.Partners, resp, nil
 }
 
+// ListPartnersIter returns an iterator over all pages of ListPartners starting from opts.Page.
+func (s *AdminAffiliateService) ListPartnersIter(ctx context.Context, opts *AdminAffiliateListPartnersOpts) *AffiliateIter {
+	o := AdminAffiliateListPartnersOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newAffiliateIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Affiliate, *Response, error) {
+		o.Page = page
+
+		return s.ListPartners(ctx, &o)
+	})
+}
+
 type PaymentSystemOpts struct {
 	SystemID int               `schema:"system_id"` // Integer ID of partners systems
 	Currency string            `schema:"currency"`  // String the currency code.
@@ -199,6 +217,8 @@ type adminAffiliateCreateResponse struct {
 
 // Create adds new partner.
 func (s *AdminAffiliateService) Create(ctx context.Context, opts *AdminAffiliateCreateOpts) (*Affiliate, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAffiliate.Create")
+
 	path := "/3.0/admin/partner"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -261,6 +281,8 @@ type adminAffiliateUpdateResponse struct {
 
 // Update edits a partner.
 func (s *AdminAffiliateService) Update(ctx context.Context, id uint64, opts *AdminAffiliateUpdateOpts) (*Affiliate, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAffiliate.Update")
+
 	path := fmt.Sprintf("/3.0/admin/partner/%d", id)
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -286,6 +308,8 @@ type AdminAffiliateMassUpdateOpts struct {
 
 // MassUpdate updates status and manager.
 func (s *AdminAffiliateService) MassUpdate(ctx context.Context, opts *AdminAffiliateMassUpdateOpts) (*Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAffiliate.MassUpdate")
+
 	path := "/3.0/admin/partners/mass-update"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -308,6 +332,8 @@ type adminAffiliateChangePasswordResponse struct {
 
 // ChangePassword changes a partner’s password.
 func (s *AdminAffiliateService) ChangePassword(ctx context.Context, id uint64) (*Affiliate, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAffiliate.ChangePassword")
+
 	path := fmt.Sprintf("/3.0/admin/partner/password/%d", id)
 
 	req, err := s.client.NewRequest(ctx, http.MethodPost, path, nil, true)
@@ -333,6 +359,11 @@ type AdminAffiliateAddPostbackOpts struct {
 	AffiliateID uint64 `schema:"pid"`                // REQUIRED
 }
 
+// Validate checks the macros of the postback URL.
+func (opts *AdminAffiliateAddPostbackOpts) Validate() error {
+	return validatePostbackURL(opts.URL)
+}
+
 // adminAffiliateAddPostbackResponse specifies response for AddPostback.
 type adminAffiliateAddPostbackResponse struct {
 	Postback *Postback `json:"postback"`
@@ -340,6 +371,14 @@ type adminAffiliateAddPostbackResponse struct {
 
 // AddPostback adds a partner’s postback.
 func (s *AdminAffiliateService) AddPostback(ctx context.Context, opts *AdminAffiliateAddPostbackOpts) (*Postback, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAffiliate.AddPostback")
+
+	if opts != nil {
+		if err := opts.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	path := "/3.0/partner/postback"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -363,6 +402,11 @@ type AdminAffiliateEditPostbackOpts struct {
 	Goal   string `schema:"goal,omitempty"`   // Postback goal (value)
 }
 
+// Validate checks the macros of the postback URL.
+func (opts *AdminAffiliateEditPostbackOpts) Validate() error {
+	return validatePostbackURL(opts.URL)
+}
+
 // adminAffiliateEditPostbackResponse specifies response for EditPostback.
 type adminAffiliateEditPostbackResponse struct {
 	Postback *Postback `json:"postback"`
@@ -370,6 +414,14 @@ type adminAffiliateEditPostbackResponse struct {
 
 // EditPostback edits a partner’s postback.
 func (s *AdminAffiliateService) EditPostback(ctx context.Context, id int, opts *AdminAffiliateEditPostbackOpts) (*Postback, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAffiliate.EditPostback")
+
+	if opts != nil {
+		if err := opts.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	path := fmt.Sprintf("/3.0/partner/postback/%d", id)
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -393,6 +445,8 @@ type adminAffiliateDeletePostbackResponse struct {
 
 // DeletePostback deletes a partner’s postback.
 func (s *AdminAffiliateService) DeletePostback(ctx context.Context, id int) (*Postback, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAffiliate.DeletePostback")
+
 	path := fmt.Sprintf("/3.0/partner/postback/%d/remove", id)
 
 	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil, true)
@@ -427,6 +481,8 @@ func (o *AdminAffiliateDeletePostbacksByAffiliatesOpts) values() (url.Values, er
 
 // DeletePostbacksByAffiliates Deletes partners postbacks by affiliates ids.
 func (s *AdminAffiliateService) DeletePostbacksByAffiliates(ctx context.Context, opts *AdminAffiliateDeletePostbacksByAffiliatesOpts) (*Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAffiliate.DeletePostbacksByAffiliates")
+
 	path := "/3.0/partner/postbacks/by-affiliates"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodDelete, path, opts, nil, true)
@@ -460,6 +516,8 @@ func (o *AdminAffiliateDeletePostbacksByOffersOpts) values() (url.Values, error)
 
 // DeletePostbacksByOffers delete partners postbacks by offers ids.
 func (s *AdminAffiliateService) DeletePostbacksByOffers(ctx context.Context, opts *AdminAffiliateDeletePostbacksByOffersOpts) (*Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAffiliate.DeletePostbacksByOffers")
+
 	path := "/3.0/partner/postbacks/by-offers"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodDelete, path, opts, nil, true)
@@ -489,6 +547,8 @@ type adminAffiliateListPostbacksResponse struct {
 
 // ListPostbacks gets a list of partner postbacks.
 func (s *AdminAffiliateService) ListPostbacks(ctx context.Context, opts *AdminAffiliateListPostbacksOpts) ([]*Postback, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAffiliate.ListPostbacks")
+
 	path := "/3.0/admin/postbacks"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, true)
@@ -505,6 +565,20 @@ func (s *AdminAffiliateService) ListPostbacks(ctx context.Context, opts *AdminAf
 	return body.Postbacks, resp, nil
 }
 
+// ListPostbacksIter returns an iterator over all pages of ListPostbacks starting from opts.Page.
+func (s *AdminAffiliateService) ListPostbacksIter(ctx context.Context, opts *AdminAffiliateListPostbacksOpts) *PostbackIter {
+	o := AdminAffiliateListPostbacksOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newPostbackIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Postback, *Response, error) {
+		o.Page = page
+
+		return s.ListPostbacks(ctx, &o)
+	})
+}
+
 // adminAffiliateChangeAPIKeyResponse specifies response for ChangeAPIKey.
 type adminAffiliateChangeAPIKeyResponse struct {
 	User *User `json:"user"`
@@ -512,6 +586,8 @@ type adminAffiliateChangeAPIKeyResponse struct {
 
 // ChangeAPIKey changes partner api key.
 func (s *AdminAffiliateService) ChangeAPIKey(ctx context.Context) (*User, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAffiliate.ChangeAPIKey")
+
 	path := "/3.1/partner/api_key"
 
 	req, err := s.client.NewRequest(ctx, http.MethodPost, path, nil, true)
@@ -536,6 +612,8 @@ type AdminAffiliateUpdateLocaleOpts struct {
 
 // UpdateLocale updates a partner’s locale.
 func (s *AdminAffiliateService) UpdateLocale(ctx context.Context, affiliateID uint64, opts *AdminAffiliateUpdateLocaleOpts) (*Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAffiliate.UpdateLocale")
+
 	path := fmt.Sprintf("/3.0/admin/partner/%d/locale", affiliateID)
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -567,6 +645,8 @@ func (a *adminAffiliateGetReferralsResponse) slice() []*Affiliate {
 
 // GetReferrals gets referrals by partner ID.
 func (s *AdminAffiliateService) GetReferrals(ctx context.Context, affiliateID uint64) ([]*Affiliate, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminAffiliate.GetReferrals")
+
 	path := fmt.Sprintf("/3.0/admin/partner/%d/referrals", affiliateID)
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, true)
@@ -192,6 +192,7 @@ func TestAdminAffiliateService_Mock(t *testing.T) {
 		env.mockHandle(t, fixture, method, path, status)
 
 		opts := &affise.AdminAffiliateAddPostbackOpts{
+			URL:         "http://affise.com",
 			Status:      "by_creating",
 			AffiliateID: 610,
 			OfferID:     960,
@@ -217,6 +218,7 @@ func TestAdminAffiliateService_Mock(t *testing.T) {
 		env.mockHandle(t, fixture, method, path, status)
 
 		opts := &affise.AdminAffiliateEditPostbackOpts{
+			URL:    "http://affise.com",
 			Status: "confirmed",
 		}
 		v, resp, err := env.Client.AdminAffiliate.EditPostback(env.Ctx, id, opts)
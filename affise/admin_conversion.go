@@ -2,8 +2,16 @@ package affise
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 type AdminConversionService struct {
@@ -30,6 +38,8 @@ type ConversionEdit struct {
 // Edit a conversion.
 func (s *AdminConversionService) Edit(ctx context.Context,
 	opts *AdminConversionEditOpts) (*ConversionEdit, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminConversion.Edit")
+
 	path := "/3.0/admin/conversion/edit"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -79,6 +89,8 @@ type ConversionImport struct {
 // Import a single conversion.
 func (s *AdminConversionService) Import(ctx context.Context,
 	opts *AdminConversionImportOpts) (*ConversionImport, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminConversion.Import")
+
 	path := "/3.0/admin/conversion/import"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -114,6 +126,8 @@ type adminConversionImportListResponse struct {
 // ImportList imports multiple conversions.
 func (s *AdminConversionService) ImportList(ctx context.Context,
 	opts *AdminConversionImportListOpts) ([]*ConversionImport, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminConversion.ImportList")
+
 	path := "/3.0/admin/conversions/import"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -136,3 +150,285 @@ func (s *AdminConversionService) ImportList(ctx context.Context,
 
 	return ret, resp, err
 }
+
+// Statuses of rows imported by BulkImport.
+const (
+	ImportStatusImported = "imported" // Returned by the API as imported
+	ImportStatusRejected = "rejected" // Rejected by the API validation, see Reason
+	ImportStatusFailed   = "failed"   // Not sent, failed or not confirmed by the API, see Err
+)
+
+// ErrImportNotConfirmed is the error of rows sent by BulkImport in a
+// successful request but missing from the list of imported rows in the response.
+var ErrImportNotConfirmed = errors.New("import not confirmed by the response")
+
+// AdminConversionBulkImportOpts specifies options for BulkImport.
+type AdminConversionBulkImportOpts struct {
+	ChunkSize   int           // Rows sent in a request (Default: 100)
+	Concurrency int           // Requests sent at the same time (Default: 4)
+	MaxAttempts int           // Attempts of a chunk not received by the API: connection failed or 429 (Default: 3)
+	RetryDelay  time.Duration // Delay before the second attempt, doubled for the next ones (Default: 1s)
+}
+
+// ConversionImportResult is the result of a row imported by BulkImport.
+type ConversionImportResult struct {
+	Row    int // Index of the row in the stream
+	Opts   AdminConversionImportOpts
+	Status string // imported, rejected or failed
+	Reason string // Validation errors of a rejected row
+	Err    error  // Error of a failed row
+}
+
+// BulkImport imports the conversions read from rows until it is closed or the
+// context is done. Rows are sent in chunks as POST bodies by concurrent
+// requests, which share the rate limit of the client. Rows are imported when
+// the API returns them in the list of imported rows. Rows rejected by the
+// validation are reported and the other rows of their chunk are sent again.
+//
+// Chunks are retried only when the request did not reach the API: the
+// connection failed or the API responded 429. Rows of chunks failed
+// otherwise, e.g. with a timeout or 5xx, may have been imported; set ActionID
+// to find them before importing them again.
+//
+// Results are returned in the order of the rows. The error is returned only
+// if the context is done; rows not imported then have status failed and the
+// rows left in the channel are drained and discarded.
+func (s *AdminConversionService) BulkImport(ctx context.Context, rows <-chan AdminConversionImportOpts,
+	opts *AdminConversionBulkImportOpts) ([]*ConversionImportResult, error) {
+	o := AdminConversionBulkImportOpts{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.ChunkSize <= 0 {
+		o.ChunkSize = 100
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 4
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = 3
+	}
+	if o.RetryDelay <= 0 {
+		o.RetryDelay = time.Second
+	}
+	backoff := &ExponentialBackoff{MinDelay: o.RetryDelay, MaxDelay: 64 * o.RetryDelay}
+
+	var (
+		results []*ConversionImportResult
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, o.Concurrency)
+	)
+	send := func(chunk []*ConversionImportResult) {
+		if ctx.Err() == nil {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			failRows(chunk, err)
+
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s.importChunk(ctx, chunk, o.MaxAttempts, backoff)
+		}()
+	}
+
+	chunk := make([]*ConversionImportResult, 0, o.ChunkSize)
+read:
+	for {
+		select {
+		case row, ok := <-rows:
+			if !ok {
+				break read
+			}
+			res := &ConversionImportResult{Row: len(results), Opts: row}
+			results = append(results, res)
+			chunk = append(chunk, res)
+			if len(chunk) == o.ChunkSize {
+				send(chunk)
+				chunk = make([]*ConversionImportResult, 0, o.ChunkSize)
+			}
+		case <-ctx.Done():
+			go drain(rows)
+
+			break read
+		}
+	}
+	if len(chunk) != 0 {
+		send(chunk)
+	}
+	wg.Wait()
+
+	return results, ctx.Err()
+}
+
+// importChunk imports the rows and sets their results.
+func (s *AdminConversionService) importChunk(ctx context.Context, chunk []*ConversionImportResult,
+	maxAttempts int, backoff *ExponentialBackoff) {
+	for attempt := 1; len(chunk) != 0; {
+		imported, err := s.importRows(ctx, chunk)
+		if err == nil {
+			confirmRows(chunk, imported)
+
+			return
+		}
+
+		var verr *ValidationError
+		if errors.As(err, &verr) {
+			rejected := rejectRows(chunk, verr.FieldErrors)
+			if len(rejected) == 0 {
+				// the errors do not name rows, so all rows are rejected
+				for _, res := range chunk {
+					res.Status, res.Reason = ImportStatusRejected, verr.MetaMessage
+				}
+
+				return
+			}
+			kept := chunk[:0]
+			for _, res := range chunk {
+				if res.Status != ImportStatusRejected {
+					kept = append(kept, res)
+				}
+			}
+			chunk = kept
+
+			continue
+		}
+
+		if !notReceived(err) || attempt >= maxAttempts || ctx.Err() != nil {
+			failRows(chunk, err)
+
+			return
+		}
+
+		timer := time.NewTimer(backoff.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			failRows(chunk, ctx.Err())
+
+			return
+		case <-timer.C:
+		}
+		attempt++
+	}
+}
+
+// importRows sends the rows in the body of an ImportList request and returns
+// the rows imported by the API.
+func (s *AdminConversionService) importRows(ctx context.Context,
+	chunk []*ConversionImportResult) ([]AdminConversionImportOpts, error) {
+	ctx = withServiceMethod(ctx, "AdminConversion.importRows")
+
+	path := "/3.0/admin/conversions/import"
+
+	list := make([]AdminConversionImportOpts, 0, len(chunk))
+	for _, res := range chunk {
+		list = append(list, res.Opts)
+	}
+	val, err := defaultEncoder.encodeSlice("list", list)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, strings.NewReader(val.Encode()), true)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	body := new(adminConversionImportListResponse)
+	if _, err := s.client.Do(req, body); err != nil {
+		return nil, err
+	}
+
+	return body.Data.List, nil
+}
+
+// confirmRows marks the rows returned by the API as imported and the other
+// rows as failed. Rows are matched by offer, partner, action and click IDs.
+func confirmRows(chunk []*ConversionImportResult, imported []AdminConversionImportOpts) {
+	left := make(map[importKey]int, len(imported))
+	for _, opts := range imported {
+		left[newImportKey(&opts)]++
+	}
+
+	for _, res := range chunk {
+		key := newImportKey(&res.Opts)
+		if left[key] == 0 {
+			res.Status, res.Err = ImportStatusFailed, ErrImportNotConfirmed
+
+			continue
+		}
+		left[key]--
+		res.Status = ImportStatusImported
+	}
+}
+
+type importKey struct {
+	offer       int
+	affiliateID uint64
+	actionID    string
+	clickID     string
+}
+
+func newImportKey(opts *AdminConversionImportOpts) importKey {
+	return importKey{offer: opts.Offer, affiliateID: opts.AffiliateID, actionID: opts.ActionID, clickID: opts.ClickID}
+}
+
+// notReceived reports whether the request failed before the API received it:
+// the connection was not established or the API responded 429.
+func notReceived(err error) bool {
+	var rerr *ResponseErr
+	if errors.As(err, &rerr) {
+		return rerr.StatusCode == http.StatusTooManyRequests
+	}
+
+	var operr *net.OpError
+
+	return errors.As(err, &operr) && operr.Op == "dial"
+}
+
+func drain(rows <-chan AdminConversionImportOpts) {
+	for range rows {
+	}
+}
+
+// rejectRows marks the rows named by field errors like "list.3.offer" as
+// rejected and returns them.
+func rejectRows(chunk []*ConversionImportResult, fields map[string][]string) []*ConversionImportResult {
+	reasons := make(map[int][]string)
+	for field, messages := range fields {
+		parts := strings.SplitN(field, ".", 3)
+		if len(parts) != 3 || parts[0] != "list" {
+			continue
+		}
+		i, err := strconv.Atoi(parts[1])
+		if err != nil || i < 0 || i >= len(chunk) {
+			continue
+		}
+		reasons[i] = append(reasons[i], fmt.Sprintf("%s: %s", parts[2], strings.Join(messages, ", ")))
+	}
+
+	var rejected []*ConversionImportResult
+	for i, res := range chunk {
+		if r, ok := reasons[i]; ok {
+			sort.Strings(r)
+			res.Status, res.Reason = ImportStatusRejected, strings.Join(r, "; ")
+			rejected = append(rejected, res)
+		}
+	}
+
+	return rejected
+}
+
+func failRows(chunk []*ConversionImportResult, err error) {
+	for _, res := range chunk {
+		res.Status, res.Err = ImportStatusFailed, err
+	}
+}
@@ -1,7 +1,14 @@
 package affise_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -75,3 +82,131 @@ func TestAdminConversionService_Mock(t *testing.T) {
 		require.Equal(t, opts.List[0].AffiliateID, v[0].AffiliateID)
 	})
 }
+
+func TestAdminConversionService_BulkImport(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+	defer env.teardown()
+
+	var (
+		mu       sync.Mutex
+		requests int
+		invalid  []string
+		flaky    = true
+	)
+	env.Mux.HandleFunc("/3.0/admin/conversions/import", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if r.Method != http.MethodPost || r.URL.RawQuery != "" || r.ParseForm() != nil {
+			invalid = append(invalid, r.Method+" "+r.URL.String())
+			http.Error(w, "unexpected request", http.StatusTeapot)
+
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		rejected := map[string]interface{}{}
+		var imported []map[string]interface{}
+		for i := 0; r.PostForm.Get(fmt.Sprintf("list[%d][offer]", i)) != ""; i++ {
+			actionID := r.PostForm.Get(fmt.Sprintf("list[%d][action_id]", i))
+			switch actionID {
+			case "bad":
+				rejected[fmt.Sprint(i)] = map[string]interface{}{"offer": []string{"Offer not found"}}
+			case "flaky":
+				if flaky {
+					flaky = false
+					w.WriteHeader(http.StatusTooManyRequests)
+					_, _ = w.Write([]byte(`{"status":2,"message":"Too many requests"}`))
+
+					return
+				}
+			case "down":
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`{"status":2,"message":"Internal error"}`))
+
+				return
+			}
+			if actionID != "lost" {
+				imported = append(imported, map[string]interface{}{"offer": 1, "pid": 7, "action_id": actionID})
+			}
+		}
+		if len(rejected) != 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"status": 2, "message": map[string]interface{}{"list": rejected}})
+
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"status": 1, "data": map[string]interface{}{"list": imported}})
+	})
+
+	rows := make(chan affise.AdminConversionImportOpts)
+	go func() {
+		defer close(rows)
+		for i := 0; i < 25; i++ {
+			row := affise.AdminConversionImportOpts{Offer: 1, AffiliateID: 7, ActionID: fmt.Sprintf("row-%d", i)}
+			switch i {
+			case 3:
+				row.ActionID = "bad"
+			case 8:
+				row.ActionID = "lost"
+			case 15:
+				row.ActionID = "flaky"
+			case 22:
+				row.ActionID = "down"
+			}
+			rows <- row
+		}
+	}()
+
+	results, err := env.Client.AdminConversion.BulkImport(env.Ctx, rows, &affise.AdminConversionBulkImportOpts{
+		ChunkSize:   10,
+		Concurrency: 3,
+		MaxAttempts: 2,
+		RetryDelay:  time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 25)
+	for i, res := range results {
+		require.Equal(t, i, res.Row)
+		switch {
+		case i == 3:
+			require.Equal(t, affise.ImportStatusRejected, res.Status)
+			require.Equal(t, "offer: Offer not found", res.Reason)
+		case i == 8:
+			require.Equal(t, affise.ImportStatusFailed, res.Status)
+			require.True(t, errors.Is(res.Err, affise.ErrImportNotConfirmed))
+		case i >= 20:
+			require.Equal(t, affise.ImportStatusFailed, res.Status, i)
+			var rerr *affise.ResponseErr
+			require.True(t, errors.As(res.Err, &rerr))
+			require.Equal(t, http.StatusInternalServerError, rerr.StatusCode)
+		default:
+			require.Equal(t, affise.ImportStatusImported, res.Status, i)
+			require.NoError(t, res.Err)
+		}
+	}
+
+	mu.Lock()
+	require.Empty(t, invalid)
+	require.Equal(t, 5, requests, "5xx is not retried")
+	mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rows = make(chan affise.AdminConversionImportOpts)
+	produced := make(chan struct{})
+	go func() {
+		defer close(produced)
+		defer close(rows)
+		for i := 0; i < 3; i++ {
+			rows <- affise.AdminConversionImportOpts{Offer: 1, AffiliateID: 7}
+		}
+	}()
+	results, err = env.Client.AdminConversion.BulkImport(ctx, rows, nil)
+	require.True(t, errors.Is(err, context.Canceled))
+	for _, res := range results {
+		require.Equal(t, affise.ImportStatusFailed, res.Status)
+	}
+	<-produced
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 )
 
 type Source struct {
@@ -33,18 +34,20 @@ type TargetingGroup struct {
 	CityAllow     map[string][]int             `schema:"city[allow],omitempty"`      // list of allowed cities for chosen country(ISO). Example: city[allow][US]=57 (city codes)
 	CityDeny      map[string][]int             `schema:"city[deny],omitempty"`       // list of denied cities
 	ISPAllow      map[string][]string          `schema:"isp[allow],omitempty"`       // list of allowed ISP for chosen country(ISO). Example: isp[allow][US]=Att (ISP list)
+	ISPDeny       map[string][]string          `schema:"isp[deny],omitempty"`        // list of denied ISP
 	SubAllow      map[string][]string          `schema:"sub[allow],omitempty"`       // list of allowed subs for chosen sub parameter. Example: sub[allow][2][]=“subValue”
 	SubDeny       map[string][]string          `schema:"sub[deny],omitempty"`        // list of denied subs for chosen sub parameter.
 	SubDenyGroups map[string]map[string]string `schema:"sub[deny_groups],omitempty"` // list of denied sub restricted groups (when is needed to block sub pairs(and more): sub1=“A” + sub2=“B”). Example: sub[deny_groups][0][1]=“A” + sub[deny_groups][0][2]=“B”. To implement “Block traffic if empty sub” option, put empty string in the group : sub[deny_groups][1][8]=“”
-	BlockProxy    int                          `schema:"block_proxy,omitempty"`      // enable/disable “Click-level Anti-fraud” feature (0, 1)
+	BlockProxy    int                          `schema:"block_proxy"`                // enable/disable “Click-level Anti-fraud” feature (0, 1). Sent even when 0, so that an update can turn it off
 	URL           string                       `schema:"url,omitempty"`              // Additional Tracking URL
 	OSAllow       []OS                         `schema:"os[allow],omitempty"`        // list of allowed OSes and them versions. To deny specific version should use according comparison operation. (See OS Structure)
+	OSDeny        []OS                         `schema:"os[deny],omitempty"`         // list of denied OSes and them versions
 	Urls          []URLWeight                  `schema:"urls,omitempty"`             // URLs with weights for traffic redistribution between several track-links
 }
 
 type URLWeight struct {
-	URL    string `schema:"url,omitempty"`    // Tracking URL
-	Weight int    `schema:"weight,omitempty"` // track-link weight (0-100)
+	URL    string `schema:"url,omitempty" json:"url"`       // Tracking URL
+	Weight int    `schema:"weight,omitempty" json:"weight"` // track-link weight (0-100)
 }
 
 type OS struct {
@@ -53,6 +56,167 @@ type OS struct {
 	Version    string `json:"version,omitempty"`    // OS version for comparison.
 }
 
+// targetingGroupJSON is the API representation of TargetingGroup.
+type targetingGroupJSON struct {
+	Country     targetingStrings `json:"country"`
+	Region      targetingInts    `json:"region"`
+	City        targetingInts    `json:"city"`
+	OS          targetingOS      `json:"os"`
+	ISP         targetingISP     `json:"isp"`
+	IP          targetingStrings `json:"ip"`
+	Browser     targetingStrings `json:"browser"`
+	Brand       targetingStrings `json:"brand"`
+	DeviceType  []string         `json:"device_type"`
+	Connection  []string         `json:"connection"`
+	AffiliateID []uint64         `json:"affiliate_id"`
+	Sub         targetingSub     `json:"sub"`
+	URL         string           `json:"url,omitempty"`
+	BlockProxy  CustomBool       `json:"block_proxy"`
+	Urls        []URLWeight      `json:"urls,omitempty"`
+}
+
+type targetingStrings struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+}
+
+type targetingInts struct {
+	Allow looseIntsMap `json:"allow"`
+	Deny  looseIntsMap `json:"deny"`
+}
+
+type targetingISP struct {
+	Allow looseStringsMap `json:"allow"`
+	Deny  looseStringsMap `json:"deny"`
+}
+
+type targetingOS struct {
+	Allow []OS `json:"allow"`
+	Deny  []OS `json:"deny"`
+}
+
+type targetingSub struct {
+	Allow      looseStringsMap `json:"allow"`
+	Deny       looseStringsMap `json:"deny"`
+	DenyGroups looseGroups     `json:"deny_groups"`
+}
+
+// MarshalJSON encodes the group as it is returned by the API.
+func (g TargetingGroup) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&targetingGroupJSON{
+		Country:     targetingStrings{Allow: g.CountryAllow, Deny: g.CountryDeny},
+		Region:      targetingInts{Allow: g.RegionAllow, Deny: g.RegionDeny},
+		City:        targetingInts{Allow: g.CityAllow, Deny: g.CityDeny},
+		OS:          targetingOS{Allow: g.OSAllow, Deny: g.OSDeny},
+		ISP:         targetingISP{Allow: g.ISPAllow, Deny: g.ISPDeny},
+		IP:          targetingStrings{Allow: g.IPAllow, Deny: g.IPDeny},
+		Browser:     targetingStrings{Allow: g.BrowserAllow, Deny: g.BrowserDeny},
+		Brand:       targetingStrings{Allow: g.BrandAllow, Deny: g.BrandDeny},
+		DeviceType:  g.DeviceType,
+		Connection:  g.Connection,
+		AffiliateID: g.AffiliateID,
+		Sub:         targetingSub{Allow: g.SubAllow, Deny: g.SubDeny, DenyGroups: g.SubDenyGroups},
+		URL:         g.URL,
+		BlockProxy:  g.BlockProxy != 0,
+		Urls:        g.Urls,
+	})
+}
+
+// UnmarshalJSON decodes the group returned by the API. Empty maps sent as
+// JSON arrays are accepted.
+func (g *TargetingGroup) UnmarshalJSON(data []byte) error {
+	var v targetingGroupJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*g = TargetingGroup{
+		CountryAllow:  v.Country.Allow,
+		CountryDeny:   v.Country.Deny,
+		IPAllow:       v.IP.Allow,
+		IPDeny:        v.IP.Deny,
+		BrowserAllow:  v.Browser.Allow,
+		BrowserDeny:   v.Browser.Deny,
+		BrandAllow:    v.Brand.Allow,
+		BrandDeny:     v.Brand.Deny,
+		DeviceType:    v.DeviceType,
+		Connection:    v.Connection,
+		AffiliateID:   v.AffiliateID,
+		RegionAllow:   v.Region.Allow,
+		RegionDeny:    v.Region.Deny,
+		CityAllow:     v.City.Allow,
+		CityDeny:      v.City.Deny,
+		ISPAllow:      v.ISP.Allow,
+		ISPDeny:       v.ISP.Deny,
+		SubAllow:      v.Sub.Allow,
+		SubDeny:       v.Sub.Deny,
+		SubDenyGroups: v.Sub.DenyGroups,
+		URL:           v.URL,
+		OSAllow:       v.OS.Allow,
+		OSDeny:        v.OS.Deny,
+		Urls:          v.Urls,
+	}
+	if v.BlockProxy {
+		g.BlockProxy = 1
+	}
+
+	return nil
+}
+
+type looseIntsMap map[string][]int
+
+func (m *looseIntsMap) UnmarshalJSON(data []byte) error {
+	if isEmptyJSONList(data) {
+		*m = nil
+
+		return nil
+	}
+
+	return json.Unmarshal(data, (*map[string][]int)(m))
+}
+
+type looseStringsMap map[string][]string
+
+func (m *looseStringsMap) UnmarshalJSON(data []byte) error {
+	if isEmptyJSONList(data) {
+		*m = nil
+
+		return nil
+	}
+
+	return json.Unmarshal(data, (*map[string][]string)(m))
+}
+
+// looseGroups are sub groups sent either as an object or as a list.
+type looseGroups map[string]map[string]string
+
+func (m *looseGroups) UnmarshalJSON(data []byte) error {
+	if isEmptyJSONList(data) {
+		*m = nil
+
+		return nil
+	}
+
+	var list []map[string]string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*m = make(looseGroups, len(list))
+		for i, group := range list {
+			(*m)[strconv.Itoa(i)] = group
+		}
+
+		return nil
+	}
+
+	return json.Unmarshal(data, (*map[string]map[string]string)(m))
+}
+
+// isEmptyJSONList reports whether data is null or an empty JSON array.
+func isEmptyJSONList(data []byte) bool {
+	s := strings.Join(strings.Fields(string(data)), "")
+
+	return s == "null" || s == "[]"
+}
+
 // Payment item structure.
 type Payment struct {
 	Partners       []int    `json:"partners,omitempty"` // Array of partner ID, which include payments (It’s available only for personal payments)
@@ -62,8 +226,8 @@ type Payment struct {
 	Devices        []string `json:"devices"`            // The array of devices. Possible values: mediahub, mobile, ereader, console, tv, tablet, desktop, smartwatch (or put empty string to clear existing items)
 	OS             []string `json:"os"`                 // The array of OSes
 	Goal           string   `json:"goal"`               // Value targets
-	Total          int      `json:"total"`              // The amount of payment
-	Revenue        int      `json:"revenue"`            // Payment webmaster
+	Total          float64  `json:"total"`              // The amount of payment, in percents of the conversion sum for the percent type
+	Revenue        float64  `json:"revenue"`            // Payment webmaster, in percents of the conversion sum for the percent and mixed types
 	Currency       string   `json:"currency"`           // Currency (Code in ECB format)
 	Type           string   `json:"type"`               // Type of payment. Possible values: fixed, percent, mixed
 	Title          string   `json:"title"`
@@ -73,10 +237,11 @@ type Payment struct {
 
 // Landing structure.
 type Landing struct {
-	Title      string `json:"title"`       // Title
-	URL        string `json:"url"`         // Tracking URL
-	URLPreview string `json:"url_preview"` // View URL
-	Type       string `json:"type"`        // Type (Possible values: landing, transit; By default: landing)
+	ID         int    `json:"id" schema:"-"` // Landing ID, set by Affise
+	Title      string `json:"title"`         // Title
+	URL        string `json:"url"`           // Tracking URL
+	URLPreview string `json:"url_preview"`   // View URL
+	Type       string `json:"type"`          // Type (Possible values: landing, transit; By default: landing)
 }
 
 // Strictly identify the operating system.
@@ -184,6 +349,7 @@ type Offer struct {
 	SubAccounts                  map[string]SubAccount `json:"sub_accounts"`
 	RedirectType                 string                `json:"redirect_type"`
 	Caps                         []Cap                 `json:"caps"`
+	Targeting                    []TargetingGroup      `json:"targeting"`
 	CommissionTiers              []CommissionTier      `json:"commission_tiers"`
 	CapsTimezone                 string                `json:"caps_timezone"`
 	StrictlyISP                  []string              `json:"strictly_isp"`
@@ -219,6 +385,8 @@ type adminOfferGetCountOffersResponse struct {
 
 // GetCountOffers gets count of offers in status "active".
 func (s *AdminOfferService) GetCountOffers(ctx context.Context) (int, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOffer.GetCountOffers")
+
 	path := "/3.0/offers/count"
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, true)
@@ -237,82 +405,100 @@ func (s *AdminOfferService) GetCountOffers(ctx context.Context) (int, *Response,
 
 // AdminOfferCreateOfferOpts specifies options for CreateOffer.
 type AdminOfferCreateOfferOpts struct {
-	Title                         string            `schema:"title"`                                      // REQUIRED Title
-	Advertiser                    string            `schema:"advertiser"`                                 // REQUIRED Advertiser ID
-	URL                           string            `schema:"url"`                                        // REQUIRED Tracking URL
-	CrossPostbackURL              string            `schema:"cross_postback_url,omitempty"`               // Cross-postback URL
-	MacroURL                      string            `schema:"macro_url,omitempty"`                        // Additional macro
-	URLPreview                    string            `schema:"url_preview,omitempty"`                      // View URL
-	TrafficbackURL                string            `schema:"trafficback_url,omitempty"`                  // Trafficback URL
-	DomainURL                     int               `schema:"domain_url,omitempty"`                       // The domain Id for the tracking URL
-	DescriptionLang               []string          `schema:"description_lang,omitempty"`                 // Offer description on specified language. Example: description_lang[en] = ‘English description’
-	StopDate                      string            `schema:"stopDate,omitempty"`                         // Stop date (Available: YYYY-MM-DD)
-	CreativeFiles                 []string          `schema:"creativeFiles,omitempty"`                    // An array of creative FILES to upload (Available: image/jpeg, image/png, image/gif, application/zip)
-	CreativeUrls                  []string          `schema:"creativeUrls,omitempty"`                     // An array of URLs to external creative resources
-	CreativeDownloads             []string          `schema:"creativeDownloads,omitempty"`                // An array of URLs to external creative resources for download
-	Sources                       []string          `schema:"sources,omitempty"`                          // An array of traffic sources The list of available sources of traffic in the section
-	Logo                          string            `schema:"logo,omitempty"`                             // logo File (Available: image/jpeg, image/pjpeg, image/png, image/gif)
-	Status                        string            `schema:"status,omitempty"`                           // Offer status (Default: stopped  Available: stopped, active, suspended)
-	Tags                          []string          `schema:"tags,omitempty"`                             // Offer tags
-	Privacy                       string            `schema:"privacy,omitempty"`                          // Privacy level (Available: public, protected, private)
-	IsTop                         int               `schema:"is_top,omitempty"`                           // The top offer (Available: 0, 1)
-	IsCpi                         int               `schema:"is_cpi,omitempty"`                           // CPI (Available: 0, 1)
-	Payments                      []string          `schema:"payments,omitempty"`                         // Payments array (See Structure)
-	PartnerPayments               []string          `schema:"partner_payments,omitempty"`                 // An array of personal paymentsy (See Structure)
-	NoticePercentOvercap          int               `schema:"notice_percent_overcap,omitempty"`           // The percentage conversions to achieve the daily limit at which the messages will be sent
-	Landings                      []string          `schema:"landings,omitempty"`                         // An array of landings(See Structure)
-	StrictlyCountry               int               `schema:"strictly_country,omitempty"`                 // Strictly identify the country (Available: 0, 1)
-	StrictlyConnectionType        string            `schema:"strictly_connection_type,omitempty"`         // Strictly identify the connection type. Set a value to empty for choosing the all strictly connection type. (Available: “”, wi-fi, cellular)
-	StrictlyOs                    []string          `schema:"strictly_os,omitempty"`                      // Deprecated : use restriction_os
-	RestrictionOs                 []string          `schema:"restriction_os,omitempty"`                   // Strictly identify the operating system (See Structure)
-	StrictlyDevices               []string          `schema:"strictly_devices,omitempty"`                 // Strictly identify the device (See Possible values)
-	StrictlyBrands                []string          `schema:"strictly_brands,omitempty"`                  // Vendors (See Vendors)
-	CapsStatus                    []string          `schema:"caps_status,omitempty"`                      // Array of conversion statuses for caps calculation. Available values: “confirmed”, “pending”, “hold”, “not_found”, “declined”
-	CapsTimezone                  string            `schema:"caps_timezone,omitempty"`                    // Select timezone of conversions calculating for caps with periods day/month
-	EnabledCommissionTiers        int               `schema:"enabled_commission_tiers,omitempty"`         // Enable commission tiers (Available: 0, 1 Default: 0)
-	HoldPeriod                    int               `schema:"hold_period,omitempty"`                      // Hold time (Available: between 0 and 60)
-	Categories                    []string          `schema:"categories,omitempty"`                       // An array of categories
-	Notes                         string            `schema:"notes,omitempty"`                            // Offer notes
-	AllowedIP                     string            `schema:"allowed_ip,omitempty"`                       // Allowed IP. Example: 127.0.0.1\n127.0.1.1-127.0.2.1
-	AllowDeeplink                 int               `schema:"allow_deeplink,omitempty"`                   // Allow diplinks (Available: 0, 1)
-	HideReferer                   int               `schema:"hide_referer,omitempty"`                     // Hide referrer (Available: 0, 1)
-	RedirectType                  string            `schema:"redirect_type,omitempty"`                    // Redirect types: http302 - usual http redirect with code 302. Without referrer passing: http302hidden, meta (meta-tag redirect), js (javascript redirect) (http302, http302hidden, js, meta)
-	StartAt                       string            `schema:"start_at,omitempty"`                         // Date time of launch (Available: YYYY-MM-DD HH:MM:SS)
-	SendEmails                    int               `schema:"send_emails,omitempty"`                      // Send emails to affiliates by offer changing. (Default: 0  Available: 0, 1)
-	IsRedirectOvercap             int               `schema:"is_redirect_overcap,omitempty"`              // Send traffic to trafficback by daily overcaps. (Default: 0  Available: 0, 1)
-	HidePayments                  int               `schema:"hide_payments,omitempty"`                    // Hide the percentage of contributions to offer for partners if it is the type of Percent payment. (Default: 0  Available: 0, 1)
-	ClickSession                  string            `schema:"click_session,omitempty"`                    // Click Session Lifespan  Example: 1y2m3w4d5h6i7s  Scales must be one from: y(year), m(month), w(week), d(day), h(hour), i(minute), s(second) (Default: 1y)
-	MinimalClickSession           string            `schema:"minimal_click_session,omitempty"`            // Minimal click session lifespan  Example: 1y2m3w4d5h6i7s  Scales must be one from: y(year), m(month), w(week), d(day), h(hour), i(minute), s(second) (Default: 0s)
-	SubAccount1                   string            `schema:"sub_account_1,omitempty"`                    // Allowed sub1 values (Available only letters(a-z), numbers(0-9) and these symbols: ,._-{}+=/:~)
-	SubAccount2                   string            `schema:"sub_account_2,omitempty"`                    // Allowed sub2 values (Available only letters(a-z), numbers(0-9) and these symbols: ,._-{}+=/:~)
-	SubAccount1Except             int               `schema:"sub_account_1_except,omitempty"`             // Block sub1 values, set only with sub_account_1 (Default: 0  Available: 0, 1)
-	SubAccount2Except             int               `schema:"sub_account_2_except,omitempty"`             // Block sub2 values, set only with sub_account_2 (Default: 0  Available: 0, 1)
-	SmartlinkCategories           []string          `schema:"smartlink_categories,omitempty"`             // Smartlink category ID. Use /3.0/admin/smartlink/categories to get an ID. Use empty value to remove a Smartlink category from an offer.
-	Kpi                           []string          `schema:"kpi,omitempty"`                              // KPI description on specified language. Example: kpi[en] = ‘English text’
-	UniqIPOnly                    int               `schema:"uniqIpOnly,omitempty"`                       // Unique IP only flag (Default:  0  Available: 0, 1)
-	RejectNotUniqIP               int               `schema:"rejectNotUniqIp,omitempty"`                  // Reject not unique Ip flag (Default:  0  Available: 0, 1)
-	StrictlyIsp                   []string          `schema:"strictly_isp,omitempty"`                     // Deprecated : use restriction_isp
-	RestrictionIsp                []string          `schema:"restriction_isp,omitempty"`                  // Stricly ISP (See Structure)
-	ExternalOfferID               string            `schema:"external_offer_id,omitempty"`                // External offer id
-	BundleID                      string            `schema:"bundle_id,omitempty"`                        // Bundle id
-	NoteAff                       string            `schema:"note_aff,omitempty"`                         // Note for affiliate
-	NoteSales                     string            `schema:"note_sales,omitempty"`                       // Note for sales
-	DisallowedIP                  string            `schema:"disallowed_ip,omitempty"`                    // disallowed ip
-	HideCaps                      int               `schema:"hide_caps,omitempty"`                        // Hide caps in partner interface (Available: 0, 1)
-	SearchEmptySub                int               `schema:"search_empty_sub,omitempty"`                 // Search for an empty sub with this number (Available: 1..8)
-	CapsGoalOvercap               string            `schema:"caps_goal_overcap,omitempty"`                // Enabled - When cap for chosen default goal is reached, clicks would be redirected to Trafficback url
-	AllowImpressions              int               `schema:"allow_impressions,omitempty"`                // Allow impressions for offer (Available: 0, 1)
-	ImpressionsURL                string            `schema:"impressions_url,omitempty"`                  // Impressions destination URL
-	ConsiderPersonalTargetingOnly string            `schema:"consider_personal_targeting_only,omitempty"` // (Available: true/false)
-	Caps                          []Cap             `schema:"-"`                                          // Caps (See CapStructure)
-	CommissionTiers               []CommissionTier  `schema:"-"`                                          // Commission tiers (See CommissionTierStructure). Commission tier list replaces existing list. To prevent a counter reset do not change fields in new list except value and modifier_value. To delete commission tiers set empty field.
-	Targeting                     []TargetingGroup  `schema:"-"`                                          // Array of targeting groups (See Structure)
-	SubRestrictions               map[string]string `schema:"-"`                                          // Sub restriction pair. Example or structure: sub_restrictions[0][sub1] = ‘sub1_val’, sub_restrictions[0][sub2] = ‘sub2_val’, sub_restrictions[1][sub1] = ‘sub2_val’, etc..
-}
-
-func (opts *AdminOfferCreateOfferOpts) values() (_ url.Values, err error) {
-	// todo encode Caps, CommissionTiers, Targeting, SubRestrictions
-	return defaultEncoder.encode(opts)
+	Title                         string              `schema:"title"`                                      // REQUIRED Title
+	Advertiser                    string              `schema:"advertiser"`                                 // REQUIRED Advertiser ID
+	URL                           string              `schema:"url"`                                        // REQUIRED Tracking URL
+	CrossPostbackURL              string              `schema:"cross_postback_url,omitempty"`               // Cross-postback URL
+	MacroURL                      string              `schema:"macro_url,omitempty"`                        // Additional macro
+	URLPreview                    string              `schema:"url_preview,omitempty"`                      // View URL
+	TrafficbackURL                string              `schema:"trafficback_url,omitempty"`                  // Trafficback URL
+	DomainURL                     int                 `schema:"domain_url,omitempty"`                       // The domain Id for the tracking URL
+	DescriptionLang               []string            `schema:"description_lang,omitempty"`                 // Offer description on specified language. Example: description_lang[en] = ‘English description’
+	StopDate                      string              `schema:"stopDate,omitempty"`                         // Stop date (Available: YYYY-MM-DD)
+	CreativeFiles                 []*File             `schema:"-"`                                          // Creative files to upload (Available: image/jpeg, image/png, image/gif, application/zip)
+	CreativeUrls                  []string            `schema:"creativeUrls,omitempty"`                     // An array of URLs to external creative resources
+	CreativeDownloads             []string            `schema:"creativeDownloads,omitempty"`                // An array of URLs to external creative resources for download
+	Sources                       []string            `schema:"sources,omitempty"`                          // An array of traffic sources The list of available sources of traffic in the section
+	Logo                          *File               `schema:"-"`                                          // Logo file to upload (Available: image/jpeg, image/pjpeg, image/png, image/gif)
+	Status                        string              `schema:"status,omitempty"`                           // Offer status (Default: stopped  Available: stopped, active, suspended)
+	Tags                          []string            `schema:"tags,omitempty"`                             // Offer tags
+	Privacy                       string              `schema:"privacy,omitempty"`                          // Privacy level (Available: public, protected, private)
+	IsTop                         int                 `schema:"is_top,omitempty"`                           // The top offer (Available: 0, 1)
+	IsCpi                         int                 `schema:"is_cpi,omitempty"`                           // CPI (Available: 0, 1)
+	Payments                      []Payment           `schema:"-"`                                          // Payments array (See Structure)
+	PartnerPayments               []Payment           `schema:"-"`                                          // An array of personal paymentsy (See Structure)
+	NoticePercentOvercap          int                 `schema:"notice_percent_overcap,omitempty"`           // The percentage conversions to achieve the daily limit at which the messages will be sent
+	Landings                      []Landing           `schema:"-"`                                          // An array of landings(See Structure)
+	StrictlyCountry               int                 `schema:"strictly_country,omitempty"`                 // Strictly identify the country (Available: 0, 1)
+	StrictlyConnectionType        string              `schema:"strictly_connection_type,omitempty"`         // Strictly identify the connection type. Set a value to empty for choosing the all strictly connection type. (Available: “”, wi-fi, cellular)
+	StrictlyOs                    []string            `schema:"strictly_os,omitempty"`                      // Deprecated : use restriction_os
+	RestrictionOs                 []OSStrictly        `schema:"-"`                                          // Strictly identify the operating system (See Structure)
+	StrictlyDevices               []string            `schema:"strictly_devices,omitempty"`                 // Strictly identify the device (See Possible values)
+	StrictlyBrands                []string            `schema:"strictly_brands,omitempty"`                  // Vendors (See Vendors)
+	CapsStatus                    []string            `schema:"caps_status,omitempty"`                      // Array of conversion statuses for caps calculation. Available values: “confirmed”, “pending”, “hold”, “not_found”, “declined”
+	CapsTimezone                  string              `schema:"caps_timezone,omitempty"`                    // Select timezone of conversions calculating for caps with periods day/month
+	EnabledCommissionTiers        int                 `schema:"enabled_commission_tiers,omitempty"`         // Enable commission tiers (Available: 0, 1 Default: 0)
+	HoldPeriod                    int                 `schema:"hold_period,omitempty"`                      // Hold time (Available: between 0 and 60)
+	Categories                    []string            `schema:"categories,omitempty"`                       // An array of categories
+	Notes                         string              `schema:"notes,omitempty"`                            // Offer notes
+	AllowedIP                     string              `schema:"allowed_ip,omitempty"`                       // Allowed IP. Example: 127.0.0.1\n127.0.1.1-127.0.2.1
+	AllowDeeplink                 int                 `schema:"allow_deeplink,omitempty"`                   // Allow diplinks (Available: 0, 1)
+	HideReferer                   int                 `schema:"hide_referer,omitempty"`                     // Hide referrer (Available: 0, 1)
+	RedirectType                  string              `schema:"redirect_type,omitempty"`                    // Redirect types: http302 - usual http redirect with code 302. Without referrer passing: http302hidden, meta (meta-tag redirect), js (javascript redirect) (http302, http302hidden, js, meta)
+	StartAt                       string              `schema:"start_at,omitempty"`                         // Date time of launch (Available: YYYY-MM-DD HH:MM:SS)
+	SendEmails                    int                 `schema:"send_emails,omitempty"`                      // Send emails to affiliates by offer changing. (Default: 0  Available: 0, 1)
+	IsRedirectOvercap             int                 `schema:"is_redirect_overcap,omitempty"`              // Send traffic to trafficback by daily overcaps. (Default: 0  Available: 0, 1)
+	HidePayments                  int                 `schema:"hide_payments,omitempty"`                    // Hide the percentage of contributions to offer for partners if it is the type of Percent payment. (Default: 0  Available: 0, 1)
+	ClickSession                  string              `schema:"click_session,omitempty"`                    // Click Session Lifespan  Example: 1y2m3w4d5h6i7s  Scales must be one from: y(year), m(month), w(week), d(day), h(hour), i(minute), s(second) (Default: 1y)
+	MinimalClickSession           string              `schema:"minimal_click_session,omitempty"`            // Minimal click session lifespan  Example: 1y2m3w4d5h6i7s  Scales must be one from: y(year), m(month), w(week), d(day), h(hour), i(minute), s(second) (Default: 0s)
+	SubAccount1                   string              `schema:"sub_account_1,omitempty"`                    // Allowed sub1 values (Available only letters(a-z), numbers(0-9) and these symbols: ,._-{}+=/:~)
+	SubAccount2                   string              `schema:"sub_account_2,omitempty"`                    // Allowed sub2 values (Available only letters(a-z), numbers(0-9) and these symbols: ,._-{}+=/:~)
+	SubAccount1Except             int                 `schema:"sub_account_1_except,omitempty"`             // Block sub1 values, set only with sub_account_1 (Default: 0  Available: 0, 1)
+	SubAccount2Except             int                 `schema:"sub_account_2_except,omitempty"`             // Block sub2 values, set only with sub_account_2 (Default: 0  Available: 0, 1)
+	SmartlinkCategories           []string            `schema:"smartlink_categories,omitempty"`             // Smartlink category ID. Use /3.0/admin/smartlink/categories to get an ID. Use empty value to remove a Smartlink category from an offer.
+	Kpi                           []string            `schema:"kpi,omitempty"`                              // KPI description on specified language. Example: kpi[en] = ‘English text’
+	UniqIPOnly                    int                 `schema:"uniqIpOnly,omitempty"`                       // Unique IP only flag (Default:  0  Available: 0, 1)
+	RejectNotUniqIP               int                 `schema:"rejectNotUniqIp,omitempty"`                  // Reject not unique Ip flag (Default:  0  Available: 0, 1)
+	StrictlyIsp                   []string            `schema:"strictly_isp,omitempty"`                     // Deprecated : use restriction_isp
+	RestrictionIsp                []ISP               `schema:"-"`                                          // Stricly ISP (See Structure)
+	ExternalOfferID               string              `schema:"external_offer_id,omitempty"`                // External offer id
+	BundleID                      string              `schema:"bundle_id,omitempty"`                        // Bundle id
+	NoteAff                       string              `schema:"note_aff,omitempty"`                         // Note for affiliate
+	NoteSales                     string              `schema:"note_sales,omitempty"`                       // Note for sales
+	DisallowedIP                  string              `schema:"disallowed_ip,omitempty"`                    // disallowed ip
+	HideCaps                      int                 `schema:"hide_caps,omitempty"`                        // Hide caps in partner interface (Available: 0, 1)
+	SearchEmptySub                int                 `schema:"search_empty_sub,omitempty"`                 // Search for an empty sub with this number (Available: 1..8)
+	CapsGoalOvercap               string              `schema:"caps_goal_overcap,omitempty"`                // Enabled - When cap for chosen default goal is reached, clicks would be redirected to Trafficback url
+	AllowImpressions              int                 `schema:"allow_impressions,omitempty"`                // Allow impressions for offer (Available: 0, 1)
+	ImpressionsURL                string              `schema:"impressions_url,omitempty"`                  // Impressions destination URL
+	ConsiderPersonalTargetingOnly string              `schema:"consider_personal_targeting_only,omitempty"` // (Available: true/false)
+	Caps                          []Cap               `schema:"-"`                                          // Caps (See CapStructure)
+	CommissionTiers               []CommissionTier    `schema:"-"`                                          // Commission tiers (See CommissionTierStructure). Commission tier list replaces existing list. To prevent a counter reset do not change fields in new list except value and modifier_value. To delete commission tiers set empty field.
+	Targeting                     []TargetingGroup    `schema:"-"`                                          // Array of targeting groups (See Structure)
+	SubRestrictions               []map[string]string `schema:"-"`                                          // Sub restriction groups. Example or structure: sub_restrictions[0][sub1] = ‘sub1_val’, sub_restrictions[0][sub2] = ‘sub2_val’, sub_restrictions[1][sub1] = ‘sub2_val’, etc..
+}
+
+func (opts *AdminOfferCreateOfferOpts) values() (url.Values, error) {
+	values, err := defaultEncoder.encode(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeValues(values, encodeOfferRules(&offerRules{
+		Payments:        opts.Payments,
+		PartnerPayments: opts.PartnerPayments,
+		Landings:        opts.Landings,
+		RestrictionOs:   opts.RestrictionOs,
+		RestrictionIsp:  opts.RestrictionIsp,
+		Caps:            opts.Caps,
+		CommissionTiers: opts.CommissionTiers,
+		Targeting:       opts.Targeting,
+		SubRestrictions: opts.SubRestrictions,
+	})), nil
+}
+
+func (opts *AdminOfferCreateOfferOpts) files() []formFile {
+	return offerFiles(opts.Logo, opts.CreativeFiles)
 }
 
 // adminOfferCreateOfferResponse specifies response for CreateOffer.
@@ -322,6 +508,8 @@ type adminOfferCreateOfferResponse struct {
 
 // CreateOffer creates a new offer.
 func (s *AdminOfferService) CreateOffer(ctx context.Context, opts *AdminOfferCreateOfferOpts) (*Offer, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOffer.CreateOffer")
+
 	path := "/3.0/admin/offer"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -340,78 +528,146 @@ func (s *AdminOfferService) CreateOffer(ctx context.Context, opts *AdminOfferCre
 
 // AdminOfferUpdateOfferOpts specifies options for UpdateOffer.
 type AdminOfferUpdateOfferOpts struct {
-	Title                         string            `schema:"title,omitempty"`                            // Title
-	Advertiser                    string            `schema:"advertiser,omitempty"`                       // Advertiser ID
-	URL                           string            `schema:"url,omitempty"`                              // Tracking URL
-	CrossPostbackURL              string            `schema:"cross_postback_url,omitempty"`               // Cross-postback URL
-	MacroURL                      string            `schema:"macro_url,omitempty"`                        // Additional macro
-	URLPreview                    string            `schema:"url_preview,omitempty"`                      // View URL
-	TrafficbackURL                string            `schema:"trafficback_url,omitempty"`                  // Trafficback URL
-	DomainURL                     int               `schema:"domain_url,omitempty"`                       // The domain Id for the tracking URL
-	DescriptionLang               []string          `schema:"description_lang,omitempty"`                 // Offer description on specified language. Example: description_lang[en] = ‘English description’
-	Kpi                           []string          `schema:"kpi,omitempty"`                              // KPI description on specified language. Example: kpi[en] = ‘English text’
-	StopDate                      string            `schema:"stopDate,omitempty"`                         // Stop date (Available: YYYY-MM-DD)
-	CreativeFiles                 []string          `schema:"creativeFiles,omitempty"`                    // An array of creative FILES to upload (Available: image/jpeg, image/png, image/gif, application/zip)
-	CreativeUrls                  []string          `schema:"creativeUrls,omitempty"`                     // An array of URLs to external creative resources
-	CreativeDownloads             []string          `schema:"creativeDownloads,omitempty"`                // An array of URLs to external creative resources for download
-	Sources                       []string          `schema:"sources,omitempty"`                          // An array of traffic sources The list of available sources of traffic in the section
-	Logo                          string            `schema:"logo,omitempty"`                             // logo File (Available: image/jpeg, image/pjpeg, image/png, image/gif)
-	Status                        string            `schema:"status,omitempty"`                           // Offer status (Default: stopped  Available: stopped, active, suspended)
-	Tags                          []string          `schema:"tags,omitempty"`                             // Offer tags
-	Privacy                       string            `schema:"privacy,omitempty"`                          // Privacy level (Available: public, protected, private)
-	IsTop                         int               `schema:"is_top,omitempty"`                           // The top offer (Available: 0, 1)
-	IsCpi                         int               `schema:"is_cpi,omitempty"`                           // CPI (Available: 0, 1)
-	Payments                      []string          `schema:"payments,omitempty"`                         // Payments array (See Structure)
-	PartnerPayments               []string          `schema:"partner_payments,omitempty"`                 // An array of personal paymentsy (See add offer)
-	NoticePercentOvercap          int               `schema:"notice_percent_overcap,omitempty"`           // The percentage conversions to achieve the daily limit at which the messages will be sent
-	Landings                      []string          `schema:"landings,omitempty"`                         // An array of landings(See Structure)
-	StrictlyCountry               int               `schema:"strictly_country,omitempty"`                 // Strictly identify the country (Available: 0, 1)
-	StrictlyConnectionType        string            `schema:"strictly_connection_type,omitempty"`         // Strictly identify the connection type. Set a value to empty for choosing the all strictly connection type. (Available: “”, wi-fi, cellular)
-	StrictlyOs                    []string          `schema:"strictly_os,omitempty"`                      // Deprecated : use restriction_os
-	RestrictionOs                 []string          `schema:"restriction_os,omitempty"`                   // Strictly identify the operating system (See add offer)
-	StrictlyDevices               []string          `schema:"strictly_devices,omitempty"`                 // Strictly identify the device (See Possible values)
-	CapsStatus                    []string          `schema:"caps_status,omitempty"`                      // Array of conversion statuses for caps calculation. Available values: “confirmed”, “pending”, “hold”, “not_found”, “declined”
-	CapsTimezone                  string            `schema:"caps_timezone,omitempty"`                    // Select timezone of conversions calculating for caps with periods day/month
-	EnabledCommissionTiers        int               `schema:"enabled_commission_tiers,omitempty"`         // Enable commission tiers (Available: 0, 1 Default: 0)
-	HoldPeriod                    int               `schema:"hold_period,omitempty"`                      // Hold time (Available: between 0 and 60)
-	Categories                    []string          `schema:"categories,omitempty"`                       // An array of categories
-	Notes                         string            `schema:"notes,omitempty"`                            // Offer notes
-	AllowedIP                     string            `schema:"allowed_ip,omitempty"`                       // Allowed IP. Example: 127.0.0.1\n127.0.1.1-127.0.2.1
-	AllowDeeplink                 int               `schema:"allow_deeplink,omitempty"`                   // Allow diplinks (Available: 0, 1)
-	HideReferer                   int               `schema:"hide_referer,omitempty"`                     // Hide referrer. Deprecated: use redirect_type (Available: 0, 1)
-	RedirectType                  string            `schema:"redirect_type,omitempty"`                    // Redirect types: http302 - usual http redirect with code 302. Without referrer passing: http302hidden, meta (meta-tag redirect), js (javascript redirect) (http302, http302hidden, js, meta)
-	StartAt                       string            `schema:"start_at,omitempty"`                         // Date time of launch (Available: YYYY-MM-DD HH:MM:SS)
-	SendEmails                    int               `schema:"send_emails,omitempty"`                      // Send emails to affiliates by offer changing. (Default: 0  Available: 0, 1)
-	IsRedirectOvercap             int               `schema:"is_redirect_overcap,omitempty"`              // Send traffic to trafficback by daily overcaps. (Default: 0  Available: 0, 1)
-	HidePayments                  int               `schema:"hide_payments,omitempty"`                    // Hide the percentage of contributions to offer for partners if it is the type of Percent payment. (Default: 0  Available: 0, 1)
-	ClickSession                  string            `schema:"click_session,omitempty"`                    // Click Session Lifespan  Example: 1y2m3w4d5h6i7s  Scales must be one from: y(year), m(month), w(week), d(day), h(hour), i(minute), s(second) (Default: 1y)
-	MinimalClickSession           string            `schema:"minimal_click_session,omitempty"`            // Minimal click session lifespan  Example: 1y2m3w4d5h6i7s  Scales must be one from: y(year), m(month), w(week), d(day), h(hour), i(minute), s(second) (Default: 0s)
-	SubAccount1                   string            `schema:"sub_account_1,omitempty"`                    // Sub1 list, separated by commas
-	SubAccount2                   string            `schema:"sub_account_2,omitempty"`                    // Sub2 list, separated by commas
-	SubAccount1Except             int               `schema:"sub_account_1_except,omitempty"`             // Except Sub1 list set only with sub_account_1 (Default: 0  Available: 0, 1)
-	SubAccount2Except             int               `schema:"sub_account_2_except,omitempty"`             // Except Sub2 list set only with sub_account_2 (Default: 0  Available: 0, 1)
-	SmartlinkCategories           []string          `schema:"smartlink_categories,omitempty"`             // Smartlink category ID. Use /3.0/admin/smartlink/categories to get an ID. Use empty value to remove a Smartlink category from an offer.
-	UniqIPOnly                    int               `schema:"uniqIpOnly,omitempty"`                       // Unique IP only flag (Default:  0  Available: 0, 1)
-	RejectNotUniqIP               int               `schema:"rejectNotUniqIp,omitempty"`                  // Reject not unique Ip flag (Default:  0  Available: 0, 1)
-	StrictlyIsp                   []string          `schema:"strictly_isp,omitempty"`                     // Deprecated : use restriction_isp
-	RestrictionIsp                []string          `schema:"restriction_isp,omitempty"`                  // Stricly ISP (See Structure)
-	ExternalOfferID               string            `schema:"external_offer_id,omitempty"`                // External offer id
-	BundleID                      string            `schema:"bundle_id,omitempty"`                        // Bundle id
-	HideCaps                      int               `schema:"hide_caps,omitempty"`                        // Hide caps in partner interface (Available: 0, 1)
-	SearchEmptySub                int               `schema:"search_empty_sub,omitempty"`                 // Search for an empty sub with this number (Available: 1..8)
-	CapsGoalOvercap               string            `schema:"caps_goal_overcap,omitempty"`                // Enabled - When cap for chosen default goal is reached, clicks would be redirected to Trafficback url
-	AllowImpressions              int               `schema:"allow_impressions,omitempty"`                // Allow impressions for offer (Available: 0, 1)
-	ImpressionsURL                string            `schema:"impressions_url,omitempty"`                  // Impressions destination URL
-	ConsiderPersonalTargetingOnly string            `schema:"consider_personal_targeting_only,omitempty"` // (Available: true/false)
-	Caps                          []Cap             `schema:"-"`                                          // Caps (See CapStructure)
-	CommissionTiers               []CommissionTier  `schema:"-"`                                          // Commission tiers (See CommissionTierStructure). Commission tier list replaces existing list. To prevent a counter reset do not change fields in new list except value and modifier_value. To delete commission tiers set empty field.
-	Targeting                     []TargetingGroup  `schema:"-"`                                          // Array of targeting groups (See Structure)
-	SubRestrictions               map[string]string `schema:"-"`                                          // Sub restriction pair. Example or structure: sub_restrictions[0][sub1] = ‘sub1_val’, sub_restrictions[0][sub2] = ‘sub2_val’, sub_restrictions[1][sub1] = ‘sub2_val’, etc..
+	Title                         string              `schema:"title,omitempty"`                            // Title
+	Advertiser                    string              `schema:"advertiser,omitempty"`                       // Advertiser ID
+	URL                           string              `schema:"url,omitempty"`                              // Tracking URL
+	CrossPostbackURL              string              `schema:"cross_postback_url,omitempty"`               // Cross-postback URL
+	MacroURL                      string              `schema:"macro_url,omitempty"`                        // Additional macro
+	URLPreview                    string              `schema:"url_preview,omitempty"`                      // View URL
+	TrafficbackURL                string              `schema:"trafficback_url,omitempty"`                  // Trafficback URL
+	DomainURL                     int                 `schema:"domain_url,omitempty"`                       // The domain Id for the tracking URL
+	DescriptionLang               []string            `schema:"description_lang,omitempty"`                 // Offer description on specified language. Example: description_lang[en] = ‘English description’
+	Kpi                           []string            `schema:"kpi,omitempty"`                              // KPI description on specified language. Example: kpi[en] = ‘English text’
+	StopDate                      string              `schema:"stopDate,omitempty"`                         // Stop date (Available: YYYY-MM-DD)
+	CreativeFiles                 []*File             `schema:"-"`                                          // Creative files to upload (Available: image/jpeg, image/png, image/gif, application/zip)
+	CreativeUrls                  []string            `schema:"creativeUrls,omitempty"`                     // An array of URLs to external creative resources
+	CreativeDownloads             []string            `schema:"creativeDownloads,omitempty"`                // An array of URLs to external creative resources for download
+	Sources                       []string            `schema:"sources,omitempty"`                          // An array of traffic sources The list of available sources of traffic in the section
+	Logo                          *File               `schema:"-"`                                          // Logo file to upload (Available: image/jpeg, image/pjpeg, image/png, image/gif)
+	Status                        string              `schema:"status,omitempty"`                           // Offer status (Default: stopped  Available: stopped, active, suspended)
+	Tags                          []string            `schema:"tags,omitempty"`                             // Offer tags
+	Privacy                       string              `schema:"privacy,omitempty"`                          // Privacy level (Available: public, protected, private)
+	IsTop                         int                 `schema:"is_top,omitempty"`                           // The top offer (Available: 0, 1)
+	IsCpi                         int                 `schema:"is_cpi,omitempty"`                           // CPI (Available: 0, 1)
+	Payments                      []Payment           `schema:"-"`                                          // Payments array (See Structure)
+	PartnerPayments               []Payment           `schema:"-"`                                          // An array of personal paymentsy (See add offer)
+	NoticePercentOvercap          int                 `schema:"notice_percent_overcap,omitempty"`           // The percentage conversions to achieve the daily limit at which the messages will be sent
+	Landings                      []Landing           `schema:"-"`                                          // An array of landings(See Structure)
+	StrictlyCountry               int                 `schema:"strictly_country,omitempty"`                 // Strictly identify the country (Available: 0, 1)
+	StrictlyConnectionType        string              `schema:"strictly_connection_type,omitempty"`         // Strictly identify the connection type. Set a value to empty for choosing the all strictly connection type. (Available: “”, wi-fi, cellular)
+	StrictlyOs                    []string            `schema:"strictly_os,omitempty"`                      // Deprecated : use restriction_os
+	RestrictionOs                 []OSStrictly        `schema:"-"`                                          // Strictly identify the operating system (See add offer)
+	StrictlyDevices               []string            `schema:"strictly_devices,omitempty"`                 // Strictly identify the device (See Possible values)
+	CapsStatus                    []string            `schema:"caps_status,omitempty"`                      // Array of conversion statuses for caps calculation. Available values: “confirmed”, “pending”, “hold”, “not_found”, “declined”
+	CapsTimezone                  string              `schema:"caps_timezone,omitempty"`                    // Select timezone of conversions calculating for caps with periods day/month
+	EnabledCommissionTiers        int                 `schema:"enabled_commission_tiers,omitempty"`         // Enable commission tiers (Available: 0, 1 Default: 0)
+	HoldPeriod                    int                 `schema:"hold_period,omitempty"`                      // Hold time (Available: between 0 and 60)
+	Categories                    []string            `schema:"categories,omitempty"`                       // An array of categories
+	Notes                         string              `schema:"notes,omitempty"`                            // Offer notes
+	AllowedIP                     string              `schema:"allowed_ip,omitempty"`                       // Allowed IP. Example: 127.0.0.1\n127.0.1.1-127.0.2.1
+	AllowDeeplink                 int                 `schema:"allow_deeplink,omitempty"`                   // Allow diplinks (Available: 0, 1)
+	HideReferer                   int                 `schema:"hide_referer,omitempty"`                     // Hide referrer. Deprecated: use redirect_type (Available: 0, 1)
+	RedirectType                  string              `schema:"redirect_type,omitempty"`                    // Redirect types: http302 - usual http redirect with code 302. Without referrer passing: http302hidden, meta (meta-tag redirect), js (javascript redirect) (http302, http302hidden, js, meta)
+	StartAt                       string              `schema:"start_at,omitempty"`                         // Date time of launch (Available: YYYY-MM-DD HH:MM:SS)
+	SendEmails                    int                 `schema:"send_emails,omitempty"`                      // Send emails to affiliates by offer changing. (Default: 0  Available: 0, 1)
+	IsRedirectOvercap             int                 `schema:"is_redirect_overcap,omitempty"`              // Send traffic to trafficback by daily overcaps. (Default: 0  Available: 0, 1)
+	HidePayments                  int                 `schema:"hide_payments,omitempty"`                    // Hide the percentage of contributions to offer for partners if it is the type of Percent payment. (Default: 0  Available: 0, 1)
+	ClickSession                  string              `schema:"click_session,omitempty"`                    // Click Session Lifespan  Example: 1y2m3w4d5h6i7s  Scales must be one from: y(year), m(month), w(week), d(day), h(hour), i(minute), s(second) (Default: 1y)
+	MinimalClickSession           string              `schema:"minimal_click_session,omitempty"`            // Minimal click session lifespan  Example: 1y2m3w4d5h6i7s  Scales must be one from: y(year), m(month), w(week), d(day), h(hour), i(minute), s(second) (Default: 0s)
+	SubAccount1                   string              `schema:"sub_account_1,omitempty"`                    // Sub1 list, separated by commas
+	SubAccount2                   string              `schema:"sub_account_2,omitempty"`                    // Sub2 list, separated by commas
+	SubAccount1Except             int                 `schema:"sub_account_1_except,omitempty"`             // Except Sub1 list set only with sub_account_1 (Default: 0  Available: 0, 1)
+	SubAccount2Except             int                 `schema:"sub_account_2_except,omitempty"`             // Except Sub2 list set only with sub_account_2 (Default: 0  Available: 0, 1)
+	SmartlinkCategories           []string            `schema:"smartlink_categories,omitempty"`             // Smartlink category ID. Use /3.0/admin/smartlink/categories to get an ID. Use empty value to remove a Smartlink category from an offer.
+	UniqIPOnly                    int                 `schema:"uniqIpOnly,omitempty"`                       // Unique IP only flag (Default:  0  Available: 0, 1)
+	RejectNotUniqIP               int                 `schema:"rejectNotUniqIp,omitempty"`                  // Reject not unique Ip flag (Default:  0  Available: 0, 1)
+	StrictlyIsp                   []string            `schema:"strictly_isp,omitempty"`                     // Deprecated : use restriction_isp
+	RestrictionIsp                []ISP               `schema:"-"`                                          // Stricly ISP (See Structure)
+	ExternalOfferID               string              `schema:"external_offer_id,omitempty"`                // External offer id
+	BundleID                      string              `schema:"bundle_id,omitempty"`                        // Bundle id
+	HideCaps                      int                 `schema:"hide_caps,omitempty"`                        // Hide caps in partner interface (Available: 0, 1)
+	SearchEmptySub                int                 `schema:"search_empty_sub,omitempty"`                 // Search for an empty sub with this number (Available: 1..8)
+	CapsGoalOvercap               string              `schema:"caps_goal_overcap,omitempty"`                // Enabled - When cap for chosen default goal is reached, clicks would be redirected to Trafficback url
+	AllowImpressions              int                 `schema:"allow_impressions,omitempty"`                // Allow impressions for offer (Available: 0, 1)
+	ImpressionsURL                string              `schema:"impressions_url,omitempty"`                  // Impressions destination URL
+	ConsiderPersonalTargetingOnly string              `schema:"consider_personal_targeting_only,omitempty"` // (Available: true/false)
+	Caps                          []Cap               `schema:"-"`                                          // Caps (See CapStructure)
+	CommissionTiers               []CommissionTier    `schema:"-"`                                          // Commission tiers (See CommissionTierStructure). Commission tier list replaces existing list. To prevent a counter reset do not change fields in new list except value and modifier_value. To delete commission tiers set empty field.
+	Targeting                     []TargetingGroup    `schema:"-"`                                          // Array of targeting groups (See Structure)
+	SubRestrictions               []map[string]string `schema:"-"`                                          // Sub restriction groups. Example or structure: sub_restrictions[0][sub1] = ‘sub1_val’, sub_restrictions[0][sub2] = ‘sub2_val’, sub_restrictions[1][sub1] = ‘sub2_val’, etc..
 }
 
 func (opts *AdminOfferUpdateOfferOpts) values() (url.Values, error) {
-	// todo encode Caps, CommissionTiers, Targeting, SubRestrictions
-	return defaultEncoder.encode(opts)
+	values, err := defaultEncoder.encode(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeValues(values, encodeOfferRules(&offerRules{
+		Payments:        opts.Payments,
+		PartnerPayments: opts.PartnerPayments,
+		Landings:        opts.Landings,
+		RestrictionOs:   opts.RestrictionOs,
+		RestrictionIsp:  opts.RestrictionIsp,
+		Caps:            opts.Caps,
+		CommissionTiers: opts.CommissionTiers,
+		Targeting:       opts.Targeting,
+		SubRestrictions: opts.SubRestrictions,
+	})), nil
+}
+
+func (opts *AdminOfferUpdateOfferOpts) files() []formFile {
+	return offerFiles(opts.Logo, opts.CreativeFiles)
+}
+
+// offerFiles returns the logo and creative files of an offer to upload.
+func offerFiles(logo *File, creatives []*File) []formFile {
+	var files []formFile
+	if logo != nil {
+		files = append(files, formFile{field: "logo", file: logo})
+	}
+	for _, f := range creatives {
+		files = append(files, formFile{field: "creativeFiles[]", file: f})
+	}
+
+	return files
+}
+
+// offerRules are the offer options encoded with the bracket notation.
+type offerRules struct {
+	Payments        []Payment
+	PartnerPayments []Payment
+	Landings        []Landing
+	RestrictionOs   []OSStrictly
+	RestrictionIsp  []ISP
+	Caps            []Cap
+	CommissionTiers []CommissionTier
+	Targeting       []TargetingGroup
+	SubRestrictions []map[string]string
+}
+
+// encodeOfferRules encodes payments, landings, restrictions, caps, commission
+// tiers, targeting groups and sub restrictions of an offer with the bracket
+// notation, e.g. payments[0][countries][]=US, caps[0][period]=day or
+// sub_restrictions[1][sub1]=val. A non-nil empty tiers slice deletes the
+// existing commission tiers.
+func encodeOfferRules(r *offerRules) url.Values {
+	values := mergeValues(
+		defaultEncoder.encodeNested("payments", r.Payments),
+		defaultEncoder.encodeNested("partner_payments", r.PartnerPayments),
+		defaultEncoder.encodeNested("landings", r.Landings),
+		defaultEncoder.encodeNested("restriction_os", r.RestrictionOs),
+		defaultEncoder.encodeNested("restriction_isp", r.RestrictionIsp),
+		defaultEncoder.encodeNested("caps", r.Caps),
+		defaultEncoder.encodeNested("commission_tiers", r.CommissionTiers),
+		defaultEncoder.encodeNested("targeting", r.Targeting),
+		defaultEncoder.encodeNested("sub_restrictions", r.SubRestrictions),
+	)
+	if r.CommissionTiers != nil && len(r.CommissionTiers) == 0 {
+		values.Set("commission_tiers", "")
+	}
+
+	return values
 }
 
 // adminOfferUpdateOfferResponse specifies response for UpdateOffer.
@@ -421,6 +677,8 @@ type adminOfferUpdateOfferResponse struct {
 
 // UpdateOffer changes an offer settings.
 func (s *AdminOfferService) UpdateOffer(ctx context.Context, id int, opts *AdminOfferUpdateOfferOpts) (*Offer, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOffer.UpdateOffer")
+
 	path := fmt.Sprintf("/3.0/admin/offer/%d", id)
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -453,6 +711,8 @@ func (o *AdminOfferDeleteOfferOpts) values() (url.Values, error) {
 
 // DeleteOffer deletes the offer.
 func (s *AdminOfferService) DeleteOffer(ctx context.Context, opts *AdminOfferDeleteOfferOpts) (*Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOffer.DeleteOffer")
+
 	path := "/3.0/admin/offer/delete"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -475,6 +735,8 @@ type adminOfferListSourcesResponse struct {
 
 // ListSources gets list of sources.
 func (s *AdminOfferService) ListSources(ctx context.Context) ([]*Source, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOffer.ListSources")
+
 	path := "/3.0/admin/offer/sources"
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, true)
@@ -507,6 +769,8 @@ type adminOfferCreateSourceResponse struct {
 
 // CreateSource creates a source.
 func (s *AdminOfferService) CreateSource(ctx context.Context, opts *AdminOfferCreateSourceOpts) (*Source, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOffer.CreateSource")
+
 	path := "/3.0/admin/offer/source"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -539,6 +803,8 @@ type adminOfferUpdateSourceResponse struct {
 
 // UpdateSource updates a source.
 func (s *AdminOfferService) UpdateSource(ctx context.Context, id string, opts *AdminOfferUpdateSourceOpts) (*Source, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOffer.UpdateSource")
+
 	path := fmt.Sprintf("/3.0/admin/offer/source/%s", id)
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -562,6 +828,8 @@ type adminOfferDeleteSourceResponse struct {
 
 // DeleteSource deletes a source by ID.
 func (s *AdminOfferService) DeleteSource(ctx context.Context, id string) (*Source, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOffer.DeleteSource")
+
 	path := fmt.Sprintf("/3.0/admin/offer/source/%s", id)
 
 	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil, true)
@@ -590,6 +858,8 @@ type adminOfferCreateCategoryResponse struct {
 
 // CreateCategory adds new category.
 func (s *AdminOfferService) CreateCategory(ctx context.Context, opts *AdminOfferCreateCategoryOpts) (*Category, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOffer.CreateCategory")
+
 	path := "/3.0/admin/category"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -618,6 +888,8 @@ type adminOfferUpdateCategoryResponse struct {
 
 // POST /3.0/admin/category/{ID}.
 func (s *AdminOfferService) UpdateCategory(ctx context.Context, id string, opts *AdminOfferUpdateCategoryOpts) (*Category, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOffer.UpdateCategory")
+
 	path := fmt.Sprintf("/3.0/admin/category/%s", id)
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -655,6 +927,8 @@ func (o *AdminOfferEnableAffiliateOpts) values() (url.Values, error) {
 
 // EnableAffiliate connections an affiliate to offer.
 func (s *AdminOfferService) EnableAffiliate(ctx context.Context, opts *AdminOfferEnableAffiliateOpts) (*Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOffer.EnableAffiliate")
+
 	path := "/3.0/offer/enable-affiliate"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -691,6 +965,8 @@ func (o *AdminOfferDisableAffiliateOpts) values() (url.Values, error) {
 
 // DisableAffiliate disconnects an affiliate from offer.
 func (s *AdminOfferService) DisableAffiliate(ctx context.Context, opts *AdminOfferDisableAffiliateOpts) (*Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOffer.DisableAffiliate")
+
 	path := "/3.0/offer/disable-affiliate"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -731,6 +1007,8 @@ func (o *AdminOfferMassUpdateOffersOpts) values() (url.Values, error) {
 
 // MassUpdateOffers updates offer`s status.
 func (s *AdminOfferService) MassUpdateOffers(ctx context.Context, opts *AdminOfferMassUpdateOffersOpts) (*Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOffer.MassUpdateOffers")
+
 	path := "/3.0/admin/offer/mass-update"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -748,6 +1026,8 @@ func (s *AdminOfferService) MassUpdateOffers(ctx context.Context, opts *AdminOff
 
 // DisableAffiliates disconnects all affiliates from private or protected offer.
 func (s *AdminOfferService) DisableAffiliates(ctx context.Context, offerID string) (*Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOffer.DisableAffiliates")
+
 	path := fmt.Sprintf("/3.0/admin/offer/%s/disable-affiliates", offerID)
 
 	req, err := s.client.NewRequest(ctx, http.MethodPost, path, nil, true)
@@ -765,6 +1045,8 @@ func (s *AdminOfferService) DisableAffiliates(ctx context.Context, offerID strin
 
 // DisableOffers disconnects all private or protected offers from affiliate.
 func (s *AdminOfferService) DisableOffers(ctx context.Context, affiliateID uint64) (*Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOffer.DisableOffers")
+
 	path := fmt.Sprintf("/3.0/admin/affiliate/%d/disable-offers", affiliateID)
 
 	req, err := s.client.NewRequest(ctx, http.MethodPost, path, nil, true)
@@ -801,6 +1083,8 @@ type adminRemoveOfferCreativesResponse struct {
 
 // RemoveOfferCreatives removes creative from offer by creative id.
 func (s *AdminOfferService) RemoveOfferCreatives(ctx context.Context, offerID string, opts *AdminRemoveOfferCreativesOpts) ([]int, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOffer.RemoveOfferCreatives")
+
 	path := fmt.Sprintf("/3.0/admin/offer/%s/remove-creative", offerID)
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodDelete, path, opts, nil, true)
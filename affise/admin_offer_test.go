@@ -1,8 +1,18 @@
 package affise_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -359,3 +369,385 @@ func TestAdminOfferService_Mock(t *testing.T) {
 		require.Equal(t, opts.Creatives, v)
 	})
 }
+
+func TestAdminOfferService_EncodeRules(t *testing.T) {
+	t.Parallel()
+
+	caps := []affise.Cap{{
+		Period:        "day",
+		Type:          "conversions",
+		Value:         "100",
+		GoalType:      "exact",
+		Goals:         []string{"1", "2"},
+		AffiliateType: "exact",
+		Affiliates:    []int{7},
+		CountryType:   "exact",
+		Country:       []string{"US"},
+	}}
+	tiers := []affise.CommissionTier{{
+		Timeframe:        "month",
+		Type:             "conversions",
+		Value:            "1000",
+		ModifierValue:    1.5,
+		ModifierType:     "by_percent",
+		Goals:            []string{"1"},
+		AffiliateType:    "each",
+		ConversionStatus: []string{"confirmed"},
+	}}
+	targeting := []affise.TargetingGroup{{
+		CountryAllow:  []string{"US", "CA"},
+		RegionAllow:   map[string][]int{"US": {33, 34}},
+		SubDenyGroups: map[string]map[string]string{"0": {"1": "A", "2": "B"}},
+		OSAllow:       []affise.OS{{Name: "iOS", Comparison: "GTE", Version: "14"}},
+		OSDeny:        []affise.OS{{Name: "Android"}},
+		ISPDeny:       map[string][]string{"US": {"Att"}},
+		Urls:          []affise.URLWeight{{URL: "https://example.com/a", Weight: 70}, {URL: "https://example.com/b", Weight: 30}},
+		BlockProxy:    1,
+	}}
+	subRestrictions := []map[string]string{{"sub1": "a", "sub2": "b"}, {"sub1": ""}}
+
+	want := url.Values{
+		"caps[0][period]":                          {"day"},
+		"caps[0][type]":                            {"conversions"},
+		"caps[0][value]":                           {"100"},
+		"caps[0][goal_type]":                       {"exact"},
+		"caps[0][goals][]":                         {"1", "2"},
+		"caps[0][affiliate_type]":                  {"exact"},
+		"caps[0][affiliates][]":                    {"7"},
+		"caps[0][country_type]":                    {"exact"},
+		"caps[0][country][]":                       {"US"},
+		"commission_tiers[0][timeframe]":           {"month"},
+		"commission_tiers[0][type]":                {"conversions"},
+		"commission_tiers[0][value]":               {"1000"},
+		"commission_tiers[0][modifier_value]":      {"1.5"},
+		"commission_tiers[0][modifier_type]":       {"by_percent"},
+		"commission_tiers[0][goals][]":             {"1"},
+		"commission_tiers[0][affiliate_type]":      {"each"},
+		"commission_tiers[0][conversion_status][]": {"confirmed"},
+		"targeting[0][country][allow][]":           {"US", "CA"},
+		"targeting[0][region][allow][US][]":        {"33", "34"},
+		"targeting[0][sub][deny_groups][0][1]":     {"A"},
+		"targeting[0][sub][deny_groups][0][2]":     {"B"},
+		"targeting[0][os][allow][0][name]":         {"iOS"},
+		"targeting[0][os][allow][0][comparison]":   {"GTE"},
+		"targeting[0][os][allow][0][version]":      {"14"},
+		"targeting[0][os][deny][0][name]":          {"Android"},
+		"targeting[0][isp][deny][US][]":            {"Att"},
+		"targeting[0][urls][0][url]":               {"https://example.com/a"},
+		"targeting[0][urls][0][weight]":            {"70"},
+		"targeting[0][urls][1][url]":               {"https://example.com/b"},
+		"targeting[0][urls][1][weight]":            {"30"},
+		"targeting[0][block_proxy]":                {"1"},
+		"sub_restrictions[0][sub1]":                {"a"},
+		"sub_restrictions[0][sub2]":                {"b"},
+		"sub_restrictions[1][sub1]":                {""},
+	}
+
+	// rules returns the received parameters of caps, tiers, targeting and sub restrictions.
+	rules := func(q url.Values) url.Values {
+		ret := url.Values{}
+		for k, v := range q {
+			for _, prefix := range []string{"caps", "commission_tiers", "targeting", "sub_restrictions"} {
+				if strings.HasPrefix(k, prefix) {
+					ret[k] = v
+				}
+			}
+		}
+
+		return ret
+	}
+
+	t.Run("CreateOffer", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		defer env.teardown()
+
+		var got url.Values
+		env.Mux.HandleFunc("/3.0/admin/offer", func(w http.ResponseWriter, r *http.Request) {
+			got = r.URL.Query()
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":1,"offer":{"id":1}}`))
+		})
+
+		_, _, err := env.Client.AdminOffer.CreateOffer(env.Ctx, &affise.AdminOfferCreateOfferOpts{
+			Title:           "test",
+			Advertiser:      "573c69a33b7d9b0e638b4576",
+			URL:             "http://example.com",
+			Caps:            caps,
+			CommissionTiers: tiers,
+			Targeting:       targeting,
+			SubRestrictions: subRestrictions,
+		})
+		require.NoError(t, err)
+		require.Equal(t, want, rules(got))
+		require.Equal(t, "test", got.Get("title"))
+	})
+
+	t.Run("UpdateOffer", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		defer env.teardown()
+
+		var got url.Values
+		env.Mux.HandleFunc("/3.0/admin/offer/1", func(w http.ResponseWriter, r *http.Request) {
+			got = r.URL.Query()
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":1,"offer":{"id":1}}`))
+		})
+
+		_, _, err := env.Client.AdminOffer.UpdateOffer(env.Ctx, 1, &affise.AdminOfferUpdateOfferOpts{
+			Caps:            caps,
+			CommissionTiers: tiers,
+			Targeting:       targeting,
+			SubRestrictions: subRestrictions,
+		})
+		require.NoError(t, err)
+		require.Equal(t, want, rules(got))
+
+		_, _, err = env.Client.AdminOffer.UpdateOffer(env.Ctx, 1, &affise.AdminOfferUpdateOfferOpts{
+			CommissionTiers: []affise.CommissionTier{},
+		})
+		require.NoError(t, err)
+		require.Equal(t, url.Values{"commission_tiers": {""}}, rules(got))
+	})
+}
+
+func TestAdminOfferService_EncodePayments(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+	defer env.teardown()
+
+	var got url.Values
+	env.Mux.HandleFunc("/3.0/admin/offer", func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":1,"offer":{"id":1}}`))
+	})
+
+	_, _, err := env.Client.AdminOffer.CreateOffer(env.Ctx, &affise.AdminOfferCreateOfferOpts{
+		Title:      "test",
+		Advertiser: "573c69a33b7d9b0e638b4576",
+		URL:        "http://example.com",
+		Payments: []affise.Payment{
+			{
+				Countries: []string{"US", "CA"},
+				Cities:    []affise.City{{ID: 5}},
+				Devices:   []string{"mobile"},
+				OS:        []string{"iOS"},
+				Goal:      "1",
+				Total:     10,
+				Revenue:   8,
+				Currency:  "usd",
+				Type:      "fixed",
+			},
+			{Countries: []string{""}, CountryExclude: true, Total: 5, Currency: "eur", Type: "fixed"},
+		},
+		PartnerPayments: []affise.Payment{{Partners: []int{7, 8}, Total: 12, Revenue: 11, Currency: "usd", Type: "fixed"}},
+		Landings:        []affise.Landing{{Title: "Main", URL: "https://example.com/landing", Type: "landing"}},
+		RestrictionOs:   []affise.OSStrictly{{OS: "Android", Versions: ">=10"}},
+		RestrictionIsp:  []affise.ISP{{Country: "US", Name: "Att"}},
+	})
+	require.NoError(t, err)
+
+	want := url.Values{
+		"title":                                {"test"},
+		"advertiser":                           {"573c69a33b7d9b0e638b4576"},
+		"url":                                  {"http://example.com"},
+		"payments[0][countries][]":             {"US", "CA"},
+		"payments[0][cities][]":                {"5"},
+		"payments[0][devices][]":               {"mobile"},
+		"payments[0][os][]":                    {"iOS"},
+		"payments[0][goal]":                    {"1"},
+		"payments[0][total]":                   {"10"},
+		"payments[0][revenue]":                 {"8"},
+		"payments[0][currency]":                {"usd"},
+		"payments[0][type]":                    {"fixed"},
+		"payments[0][country_exclude]":         {"0"},
+		"payments[0][with_regions]":            {"0"},
+		"payments[1][countries][]":             {""},
+		"payments[1][country_exclude]":         {"1"},
+		"payments[1][total]":                   {"5"},
+		"payments[1][currency]":                {"eur"},
+		"payments[1][type]":                    {"fixed"},
+		"payments[1][revenue]":                 {"0"},
+		"payments[1][with_regions]":            {"0"},
+		"partner_payments[0][partners][]":      {"7", "8"},
+		"partner_payments[0][total]":           {"12"},
+		"partner_payments[0][revenue]":         {"11"},
+		"partner_payments[0][currency]":        {"usd"},
+		"partner_payments[0][type]":            {"fixed"},
+		"partner_payments[0][country_exclude]": {"0"},
+		"partner_payments[0][with_regions]":    {"0"},
+		"landings[0][title]":                   {"Main"},
+		"landings[0][url]":                     {"https://example.com/landing"},
+		"landings[0][type]":                    {"landing"},
+		"restriction_os[0][os]":                {"Android"},
+		"restriction_os[0][versions]":          {">=10"},
+		"restriction_isp[0][country]":          {"US"},
+		"restriction_isp[0][name]":             {"Att"},
+	}
+	require.Equal(t, want, got)
+}
+
+func TestTargetingGroup_JSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Decode", func(t *testing.T) {
+		t.Parallel()
+
+		data := `{
+			"country": {"allow": ["US"], "deny": []},
+			"region": {"allow": {"US": [33]}, "deny": []},
+			"city": {"allow": [], "deny": []},
+			"os": {"allow": [{"name": "iOS", "comparison": "GTE", "version": "14"}], "deny": [{"name": "Android"}]},
+			"isp": {"allow": [], "deny": {"US": ["Att"]}},
+			"ip": {"allow": [], "deny": []},
+			"browser": {"allow": [], "deny": ["Edge"]},
+			"brand": {"allow": [], "deny": []},
+			"device_type": ["mobile"],
+			"connection": [],
+			"affiliate_id": [7],
+			"sub": {"allow": {"1": ["a"]}, "deny": [], "deny_groups": [{"1": "A", "2": "B"}]},
+			"url": "",
+			"block_proxy": true
+		}`
+
+		var g affise.TargetingGroup
+		require.NoError(t, json.Unmarshal([]byte(data), &g))
+		require.Equal(t, affise.TargetingGroup{
+			CountryAllow:  []string{"US"},
+			CountryDeny:   []string{},
+			IPAllow:       []string{},
+			IPDeny:        []string{},
+			BrowserAllow:  []string{},
+			BrowserDeny:   []string{"Edge"},
+			BrandAllow:    []string{},
+			BrandDeny:     []string{},
+			DeviceType:    []string{"mobile"},
+			Connection:    []string{},
+			AffiliateID:   []uint64{7},
+			RegionAllow:   map[string][]int{"US": {33}},
+			ISPDeny:       map[string][]string{"US": {"Att"}},
+			SubAllow:      map[string][]string{"1": {"a"}},
+			SubDenyGroups: map[string]map[string]string{"0": {"1": "A", "2": "B"}},
+			BlockProxy:    1,
+			OSAllow:       []affise.OS{{Name: "iOS", Comparison: "GTE", Version: "14"}},
+			OSDeny:        []affise.OS{{Name: "Android"}},
+		}, g)
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		t.Parallel()
+
+		want := affise.TargetingGroup{
+			CountryAllow: []string{"US", "CA"},
+			CityDeny:     map[string][]int{"US": {57}},
+			ISPAllow:     map[string][]string{"US": {"Att"}},
+			ISPDeny:      map[string][]string{"CA": {"Rogers"}},
+			OSDeny:       []affise.OS{{Name: "Android", Comparison: "LT", Version: "10"}},
+			URL:          "https://example.com",
+			Urls:         []affise.URLWeight{{URL: "https://example.com/a", Weight: 50}},
+		}
+		data, err := json.Marshal(want)
+		require.NoError(t, err)
+
+		var got affise.TargetingGroup
+		require.NoError(t, json.Unmarshal(data, &got))
+		require.Equal(t, want, got)
+	})
+}
+
+func TestAdminOfferService_Upload(t *testing.T) {
+	t.Parallel()
+
+	type upload struct {
+		title     string
+		logo      string
+		logoType  string
+		creatives []string
+	}
+
+	// newUploadServer returns a server failing the first failures requests with 503.
+	newUploadServer := func(t *testing.T, failures int) (*affise.Client, *[]upload) {
+		t.Helper()
+
+		var uploads []upload
+		mux := http.NewServeMux()
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, r.ParseMultipartForm(1<<20))
+
+			u := upload{title: r.URL.Query().Get("title")}
+			logo, header, err := r.FormFile("logo")
+			require.NoError(t, err)
+			data, _ := ioutil.ReadAll(logo)
+			u.logo = header.Filename + ":" + string(data)
+			u.logoType = header.Header.Get("Content-Type")
+			for _, fh := range r.MultipartForm.File["creativeFiles[]"] {
+				f, err := fh.Open()
+				require.NoError(t, err)
+				data, _ := ioutil.ReadAll(f)
+				u.creatives = append(u.creatives, fh.Filename+":"+string(data))
+			}
+			uploads = append(uploads, u)
+
+			w.Header().Set("Content-Type", "application/json")
+			if len(uploads) <= failures {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(`{"status":2,"message":"Service unavailable"}`))
+
+				return
+			}
+			_, _ = w.Write([]byte(`{"status":1,"offer":{"id":1}}`))
+		}
+		mux.HandleFunc("/3.0/admin/offer", handler)
+		mux.HandleFunc("/3.0/admin/offer/1", handler)
+		server := httptest.NewServer(mux)
+		t.Cleanup(server.Close)
+
+		client, err := affise.NewClient(
+			affise.WithBaseURL(server.URL),
+			affise.WithAdminURL(server.URL),
+			affise.WithRetryPolicy(&affise.ExponentialBackoff{MaxAttempts: 2, MinDelay: time.Millisecond, RetryPOST: true}),
+		)
+		require.NoError(t, err)
+
+		return client, &uploads
+	}
+
+	t.Run("Multipart", func(t *testing.T) {
+		t.Parallel()
+		client, uploads := newUploadServer(t, 1)
+
+		_, _, err := client.AdminOffer.CreateOffer(context.Background(), &affise.AdminOfferCreateOfferOpts{
+			Title:      "test",
+			Advertiser: "573c69a33b7d9b0e638b4576",
+			URL:        "http://example.com",
+			Logo:       &affise.File{Name: "logo.png", ContentType: "image/png", Reader: strings.NewReader("png")},
+			CreativeFiles: []*affise.File{
+				{Name: "banner.gif", Reader: bytes.NewReader([]byte("gif"))},
+				{Name: "creatives.zip", ContentType: "application/zip", Reader: strings.NewReader("zip")},
+			},
+		})
+		require.NoError(t, err)
+
+		// the retried request is sent with the same content
+		want := upload{
+			title:     "test",
+			logo:      "logo.png:png",
+			logoType:  "image/png",
+			creatives: []string{"banner.gif:gif", "creatives.zip:zip"},
+		}
+		require.Equal(t, []upload{want, want}, *uploads)
+	})
+
+	t.Run("NotSeeker", func(t *testing.T) {
+		t.Parallel()
+		client, uploads := newUploadServer(t, 1)
+
+		_, _, err := client.AdminOffer.UpdateOffer(context.Background(), 1, &affise.AdminOfferUpdateOfferOpts{
+			Logo: &affise.File{Name: "logo.png", Reader: io.MultiReader(strings.NewReader("png"))},
+		})
+		require.Error(t, err)
+		require.Len(t, *uploads, 1)
+	})
+}
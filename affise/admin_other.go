@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 )
 
 type Domain struct {
@@ -36,6 +37,11 @@ type City struct {
 	RegionCode  string `json:"region_code"`
 }
 
+// nestedValue encodes the city as its ID in offer payments.
+func (c City) nestedValue() string {
+	return strconv.Itoa(c.ID)
+}
+
 type Ticket struct {
 	ID          string    `json:"id"`
 	Status      string    `json:"status"`
@@ -105,6 +111,8 @@ type adminOtherListCitiesResponse struct {
 
 // ListCities gets city list.
 func (s *AdminOtherService) ListCities(ctx context.Context, opts *AdminOtherListCitiesOpts) ([]*City, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOther.ListCities")
+
 	path := "/3.1/cities"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, true)
@@ -128,6 +136,8 @@ type adminOtherListDevicesResponse struct {
 
 // ListDevices gets list of devices.
 func (s *AdminOtherService) ListDevices(ctx context.Context) ([]string, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOther.ListDevices")
+
 	path := "/3.1/devices"
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, true)
@@ -151,6 +161,8 @@ type adminOtherListBrowsersResponse struct {
 
 // ListBrowsers gets browsers list.
 func (s *AdminOtherService) ListBrowsers(ctx context.Context) ([]string, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOther.ListBrowsers")
+
 	path := "/3.1/browsers"
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, true)
@@ -180,6 +192,8 @@ type adminOtherListCurrenciesResponse struct {
 
 // ListCurrencies gets list of currency.
 func (s *AdminOtherService) ListCurrencies(ctx context.Context, opts *AdminOtherListCurrenciesOpts) (map[string]json.Number, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOther.ListCurrencies")
+
 	path := "/3.0/admin/currency"
 
 	if opts != nil {
@@ -207,6 +221,8 @@ type adminOtherListCurrenciesExtendedResponse struct {
 
 // ListCurrencies gets extended list of currency.
 func (s *AdminOtherService) ListCurrenciesExtended(ctx context.Context, opts *AdminOtherListCurrenciesOpts) ([]*ExtendedCurrency, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOther.ListCurrenciesExtended")
+
 	path := "/3.0/admin/currency"
 
 	if opts != nil {
@@ -234,6 +250,8 @@ type adminOtherListPaymentSystemsResponse struct {
 
 // ListPaymentSystems gets list of payment systems.
 func (s *AdminOtherService) ListPaymentSystems(ctx context.Context) ([]*PaymentSystem, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOther.ListPaymentSystems")
+
 	path := "/3.0/admin/payment_systems"
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, true)
@@ -257,6 +275,8 @@ type adminOtherListCustomFieldsResponse struct {
 
 // ListCustomFields gets list of signup settings.
 func (s *AdminOtherService) ListCustomFields(ctx context.Context) ([]*CustomField, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOther.ListCustomFields")
+
 	path := "/3.0/admin/custom_fields"
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, true)
@@ -280,6 +300,8 @@ type adminOtherListDomainsResponse struct {
 
 // ListDomains gets domains.
 func (s *AdminOtherService) ListDomains(ctx context.Context) ([]*Domain, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOther.ListDomains")
+
 	path := "/3.0/admin/domains"
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, true)
@@ -303,6 +325,8 @@ type adminOtherGetTicketResponse struct {
 
 // GetTicket gets ticket.
 func (s *AdminOtherService) GetTicket(ctx context.Context, id string) (*Ticket, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOther.GetTicket")
+
 	path := fmt.Sprintf("/3.0/admin/ticket/%s", id)
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, true)
@@ -333,6 +357,8 @@ type adminOtherListTicketsResponse struct {
 
 // ListTickets gets list of tickets for connection to offers.
 func (s *AdminOtherService) ListTickets(ctx context.Context, opts *AdminOtherListTicketsOpts) ([]*Ticket, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOther.ListTickets")
+
 	path := "/3.0/admin/tickets"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, true)
@@ -349,6 +375,20 @@ func (s *AdminOtherService) ListTickets(ctx context.Context, opts *AdminOtherLis
 	return body.Tickets, resp, nil
 }
 
+// ListTicketsIter returns an iterator over all pages of ListTickets starting from opts.Page.
+func (s *AdminOtherService) ListTicketsIter(ctx context.Context, opts *AdminOtherListTicketsOpts) *TicketIter {
+	o := AdminOtherListTicketsOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newTicketIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Ticket, *Response, error) {
+		o.Page = page
+
+		return s.ListTickets(ctx, &o)
+	})
+}
+
 // AdminOtherApproveTicketOpts specifies options for ApproveTicket.
 type AdminOtherApproveTicketOpts struct {
 	Do string `schema:"do,omitempty"` // What need to do with a ticket (Available: approve, reject)
@@ -356,6 +396,8 @@ type AdminOtherApproveTicketOpts struct {
 
 // ApproveTicket approves or rejects ticket for connect affiliate to offer.
 func (s *AdminOtherService) ApproveTicket(ctx context.Context, id int, opts *AdminOtherApproveTicketOpts) (*Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOther.ApproveTicket")
+
 	path := fmt.Sprintf("/3.0/admin/ticket/%d/offer", id)
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -371,6 +413,40 @@ func (s *AdminOtherService) ApproveTicket(ctx context.Context, id int, opts *Adm
 	return resp, nil
 }
 
+// AdminOtherAddTicketMessageOpts specifies options for AddTicketMessage.
+type AdminOtherAddTicketMessageOpts struct {
+	Message     string  `schema:"message,omitempty"` // Message text
+	Attachments []*File `schema:"-"`                 // Files attached to the message
+}
+
+func (opts *AdminOtherAddTicketMessageOpts) files() []formFile {
+	files := make([]formFile, 0, len(opts.Attachments))
+	for _, f := range opts.Attachments {
+		files = append(files, formFile{field: "attachments[]", file: f})
+	}
+
+	return files
+}
+
+// AddTicketMessage adds a message with attachments to the ticket.
+func (s *AdminOtherService) AddTicketMessage(ctx context.Context, id string, opts *AdminOtherAddTicketMessageOpts) (*Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOther.AddTicketMessage")
+
+	path := fmt.Sprintf("/3.0/admin/ticket/%s/message", id)
+
+	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 // adminOtherListPixelsResponse specifies response for ListPixels.
 type adminOtherListPixelsResponse struct {
 	Pixel map[string]*Pixel `json:"pixel"`
@@ -378,6 +454,8 @@ type adminOtherListPixelsResponse struct {
 
 // ListPixels gets list of a partner’s pixels.
 func (s *AdminOtherService) ListPixels(ctx context.Context, affiliateID uint64) ([]*Pixel, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOther.ListPixels")
+
 	path := fmt.Sprintf("/3.0/partner/pixels/%d", affiliateID)
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, true)
@@ -417,6 +495,8 @@ type adminOtherCreatePixelResponse struct {
 
 // CreatePixel adds a partner’s pixel.
 func (s *AdminOtherService) CreatePixel(ctx context.Context, opts *AdminOtherCreatePixelOpts) (*Pixel, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOther.CreatePixel")
+
 	path := "/3.0/partner/pixel"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -449,6 +529,8 @@ type adminOtherUpdatePixelResponse struct {
 
 // UpdatePixel edits a partner’s pixel.
 func (s *AdminOtherService) UpdatePixel(ctx context.Context, id int, opts *AdminOtherUpdatePixelOpts) (*Pixel, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOther.UpdatePixel")
+
 	path := fmt.Sprintf("/3.0/partner/pixel/%d", id)
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -472,6 +554,8 @@ type adminOtherDeletePixelResponse struct {
 
 // DeletePixel deletes a partner’s pixel.
 func (s *AdminOtherService) DeletePixel(ctx context.Context, id int) (*Pixel, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOther.DeletePixel")
+
 	path := fmt.Sprintf("/3.0/partner/pixel/%d/remove", id)
 
 	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil, true)
@@ -501,6 +585,8 @@ type adminOtherListSmartLinkCategoriesResponse struct {
 
 // ListSmartLinkCategories gets SmartLink categories list.
 func (s *AdminOtherService) ListSmartLinkCategories(ctx context.Context, opts *AdminOtherListSmartLinkCategoriesOpts) ([]*SmartLinkCategory, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOther.ListSmartLinkCategories")
+
 	path := "/3.0/admin/smartlink/categories"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, true)
@@ -531,6 +617,8 @@ type adminOtherCreateSmartLinkCategoryResponse struct {
 
 // CreateSmartLinkCategory adds new SmartLink category.
 func (s *AdminOtherService) CreateSmartLinkCategory(ctx context.Context, opts *AdminOtherCreateSmartLinkCategoryOpts) (*SmartLinkCategory, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOther.CreateSmartLinkCategory")
+
 	path := "/3.0/admin/smartlink/category"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -561,6 +649,8 @@ type adminOtherUpdateSmartLinkCategoryResponse struct {
 
 // UpdateSmartLinkCategory edits a SmartLink category.
 func (s *AdminOtherService) UpdateSmartLinkCategory(ctx context.Context, id string, opts *AdminOtherUpdateSmartLinkCategoryOpts) (*SmartLinkCategory, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOther.UpdateSmartLinkCategory")
+
 	path := fmt.Sprintf("/3.0/admin/smartlink/category/%s", id)
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -584,6 +674,8 @@ type adminOtherDeleteSmartLinkCategoryResponse struct {
 
 // DeleteSmartLinkCategory removes a SmartLink category.
 func (s *AdminOtherService) DeleteSmartLinkCategory(ctx context.Context, id string) (*SmartLinkCategory, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOther.DeleteSmartLinkCategory")
+
 	path := fmt.Sprintf("/3.0/admin/smartlink/category/%s/remove", id)
 
 	req, err := s.client.NewRequest(ctx, http.MethodPost, path, nil, true)
@@ -609,6 +701,8 @@ type adminOtherGetSmartLinkOffersCountResponse struct {
 
 // GetSmartLinkOffersCount adds new SmartLink category.
 func (s *AdminOtherService) GetSmartLinkOffersCount(ctx context.Context, id string) (int, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminOther.GetSmartLinkOffersCount")
+
 	path := fmt.Sprintf("/3.0/admin/smartlink/category/%s/offers-count", id)
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, true)
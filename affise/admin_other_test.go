@@ -2,6 +2,9 @@ package affise_test
 
 import (
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -426,3 +429,40 @@ func TestAdminOtherService_Mock(t *testing.T) {
 		require.True(t, v == 2)
 	})
 }
+
+func TestAdminOtherService_AddTicketMessage(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+	defer env.teardown()
+
+	var (
+		message string
+		parts   []string
+	)
+	env.Mux.HandleFunc("/3.0/admin/ticket/5f4e/message", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+
+		message = r.URL.Query().Get("message")
+		for _, fh := range r.MultipartForm.File["attachments[]"] {
+			f, err := fh.Open()
+			require.NoError(t, err)
+			data, _ := ioutil.ReadAll(f)
+			parts = append(parts, fh.Filename+":"+fh.Header.Get("Content-Type")+":"+string(data))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":1}`))
+	})
+
+	_, err := env.Client.AdminOther.AddTicketMessage(env.Ctx, "5f4e", &affise.AdminOtherAddTicketMessageOpts{
+		Message: "see attached",
+		Attachments: []*affise.File{
+			{Name: "screen.png", ContentType: "image/png", Reader: strings.NewReader("png")},
+			{Name: "log.txt", Reader: strings.NewReader("log")},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "see attached", message)
+	require.Equal(t, []string{"screen.png:image/png:png", "log.txt:application/octet-stream:log"}, parts)
+}
@@ -33,6 +33,8 @@ type adminPresetListResponse struct {
 
 // List gets list of presets.
 func (s *AdminPresetService) List(ctx context.Context, opts *AdminPresetListOpts) ([]*Preset, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminPreset.List")
+
 	path := "/3.1/presets"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, true)
@@ -49,6 +51,20 @@ func (s *AdminPresetService) List(ctx context.Context, opts *AdminPresetListOpts
 	return body.Presets, resp, nil
 }
 
+// ListIter returns an iterator over all pages of List starting from opts.Page.
+func (s *AdminPresetService) ListIter(ctx context.Context, opts *AdminPresetListOpts) *PresetIter {
+	o := AdminPresetListOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newPresetIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Preset, *Response, error) {
+		o.Page = page
+
+		return s.List(ctx, &o)
+	})
+}
+
 // AdminPresetCreateOpts specifies options for Create.
 type AdminPresetCreateOpts struct {
 	Name        string       `schema:"name"`        // Preset name REQUIRED (String)
@@ -63,6 +79,8 @@ type adminPresetCreateResponse struct {
 
 // Create creates preset using JSON dataset.
 func (s *AdminPresetService) Create(ctx context.Context, opts *AdminPresetCreateOpts) (*Preset, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminPreset.Create")
+
 	path := "/3.1/presets"
 
 	buf := new(bytes.Buffer)
@@ -98,6 +116,8 @@ type adminPresetUpdateResponse struct {
 
 // Update updates preset using JSON dataset.
 func (s *AdminPresetService) Update(ctx context.Context, id string, opts *AdminPresetUpdateOpts) (*Preset, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminPreset.Update")
+
 	path := fmt.Sprintf("/3.1/presets/%s", id)
 
 	buf := new(bytes.Buffer)
@@ -122,6 +142,8 @@ func (s *AdminPresetService) Update(ctx context.Context, id string, opts *AdminP
 
 // Delete deletes preset by ID.
 func (s *AdminPresetService) Delete(ctx context.Context, id string) (*Response, error) {
+	ctx = withServiceMethod(ctx, "AdminPreset.Delete")
+
 	path := fmt.Sprintf("/3.1/presets/%s", id)
 
 	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil, true)
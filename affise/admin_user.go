@@ -87,6 +87,8 @@ type adminUserListResponse struct {
 
 // List gets a list of users.
 func (s *AdminUserService) List(ctx context.Context, opts *AdminUserListOpts) ([]*User, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminUser.List")
+
 	path := "/3.0/admin/users"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, true)
@@ -103,6 +105,20 @@ func (s *AdminUserService) List(ctx context.Context, opts *AdminUserListOpts) ([
 	return body.Users, resp, nil
 }
 
+// ListIter returns an iterator over all pages of List starting from opts.Page.
+func (s *AdminUserService) ListIter(ctx context.Context, opts *AdminUserListOpts) *UserIter {
+	o := AdminUserListOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newUserIter(ctx, o.Page, func(ctx context.Context, page int) ([]*User, *Response, error) {
+		o.Page = page
+
+		return s.List(ctx, &o)
+	})
+}
+
 // AdminUserCreateOpts specifies options for Create.
 type AdminUserCreateOpts struct {
 	Email     string   `schema:"email"`                // REQUIRED Email
@@ -122,6 +138,8 @@ type adminUserGetResponse struct {
 
 // Get reads single user.
 func (s *AdminUserService) Get(ctx context.Context, id string) (*User, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminUser.Get")
+
 	path := fmt.Sprintf("/3.0/admin/user/%s", id)
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, true)
@@ -145,6 +163,8 @@ type adminUserCreateResponse struct {
 
 // Create adds a new user.
 func (s *AdminUserService) Create(ctx context.Context, opts *AdminUserCreateOpts) (*User, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminUser.Create")
+
 	path := "/3.0/admin/user"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -181,6 +201,8 @@ type adminUserUpdateResponse struct {
 
 // Update changes the user.
 func (s *AdminUserService) Update(ctx context.Context, id string, opts *AdminUserUpdateOpts) (*User, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminUser.Update")
+
 	path := fmt.Sprintf("/3.0/admin/user/%s", id)
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -204,6 +226,8 @@ type adminUserChangeAPIKeyResponse struct {
 
 // ChangeAPIKey changes user api key.
 func (s *AdminUserService) ChangeAPIKey(ctx context.Context, id string) (*User, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminUser.ChangeAPIKey")
+
 	path := fmt.Sprintf("/3.0/admin/user/api_key/%s", id)
 
 	req, err := s.client.NewRequest(ctx, http.MethodPost, path, nil, true)
@@ -232,6 +256,8 @@ type adminUserChangePasswordResponse struct {
 
 // ChangePassword changes user password.
 func (s *AdminUserService) ChangePassword(ctx context.Context, id string, opts *AdminUserChangePasswordOpts) (*User, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminUser.ChangePassword")
+
 	path := fmt.Sprintf("/3.0/admin/user/%s/password", id)
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, true)
@@ -260,6 +286,8 @@ type adminUserUpdatePermissionsResponse struct {
 
 // UpdatePermissions updates user permissions.
 func (s *AdminUserService) UpdatePermissions(ctx context.Context, id string, opts *AdminUserUpdatePermissionsOpts) (*Permissions, *Response, error) {
+	ctx = withServiceMethod(ctx, "AdminUser.UpdatePermissions")
+
 	path := fmt.Sprintf("/3.1/user/%s/permissions", id)
 
 	buf := new(bytes.Buffer)
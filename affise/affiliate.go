@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
+
+	"github.com/clobucks/go-sdk/affise/internal/macro"
 )
 
 type NewsItem struct {
@@ -32,6 +35,8 @@ type affiliateMeResponse struct {
 
 // Me gets partner own data.
 func (s *AffiliateService) Me(ctx context.Context) (*User, *Response, error) {
+	ctx = withServiceMethod(ctx, "Affiliate.Me")
+
 	path := "/3.1/partner/me"
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, false)
@@ -67,6 +72,8 @@ type affiliateListOffersResponse struct {
 
 // ListOffers gets list of a live offers.
 func (s *AffiliateService) ListOffers(ctx context.Context, opts *AffiliateListOffersOpts) ([]*Offer, *Response, error) {
+	ctx = withServiceMethod(ctx, "Affiliate.ListOffers")
+
 	path := "/3.0/partner/offers"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -83,6 +90,20 @@ func (s *AffiliateService) ListOffers(ctx context.Context, opts *AffiliateListOf
 	return body.Offers, resp, nil
 }
 
+// ListOffersIter returns an iterator over all pages of ListOffers starting from opts.Page.
+func (s *AffiliateService) ListOffersIter(ctx context.Context, opts *AffiliateListOffersOpts) *OfferIter {
+	o := AffiliateListOffersOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newOfferIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Offer, *Response, error) {
+		o.Page = page
+
+		return s.ListOffers(ctx, &o)
+	})
+}
+
 // AffiliateListLiveOffersOpts specifies options for ListLiveOffers.
 type AffiliateListLiveOffersOpts struct {
 	Q          string   `schema:"q,omitempty"`          // Search by title and id
@@ -102,6 +123,8 @@ type affiliateListLiveOffersResponse struct {
 
 // ListLiveOffers gets list of a live offers.
 func (s *AffiliateService) ListLiveOffers(ctx context.Context, opts *AffiliateListLiveOffersOpts) ([]*Offer, *Response, error) {
+	ctx = withServiceMethod(ctx, "Affiliate.ListLiveOffers")
+
 	path := "/3.0/partner/live-offers"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -118,6 +141,20 @@ func (s *AffiliateService) ListLiveOffers(ctx context.Context, opts *AffiliateLi
 	return body.Offers, resp, nil
 }
 
+// ListLiveOffersIter returns an iterator over all pages of ListLiveOffers starting from opts.Page.
+func (s *AffiliateService) ListLiveOffersIter(ctx context.Context, opts *AffiliateListLiveOffersOpts) *OfferIter {
+	o := AffiliateListLiveOffersOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newOfferIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Offer, *Response, error) {
+		o.Page = page
+
+		return s.ListLiveOffers(ctx, &o)
+	})
+}
+
 // AffiliateActivationOfferOpts specifies options for ActivationOffer.
 type AffiliateActivationOfferOpts struct {
 	OfferID int    `schema:"offer_id"` // REQUIRED
@@ -126,6 +163,8 @@ type AffiliateActivationOfferOpts struct {
 
 // ActivationOffer connects to an offer.
 func (s *AffiliateService) ActivationOffer(ctx context.Context, opts *AffiliateActivationOfferOpts) (*Response, error) {
+	ctx = withServiceMethod(ctx, "Affiliate.ActivationOffer")
+
 	path := "/3.0/partner/activation/offer"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, false)
@@ -150,6 +189,20 @@ type AffiliateCreatePostbackOpts struct {
 	Goal        string `schema:"goal,omitempty"`     // Postback goal (value)
 }
 
+// Validate checks the macros of the postback URL.
+func (opts *AffiliateCreatePostbackOpts) Validate() error {
+	return validatePostbackURL(opts.URL)
+}
+
+// validatePostbackURL checks the postback URL like postback.ParseTemplate.
+func validatePostbackURL(raw string) error {
+	if _, problems := macro.ParsePostbackURL(raw); len(problems) != 0 {
+		return fmt.Errorf("%w %q: %s", ErrInvalidPostbackURL, raw, strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
 // affiliateCreatePostbackResponse specifies response for CreatePostback.
 type affiliateCreatePostbackResponse struct {
 	Postback *Postback `json:"postback"`
@@ -157,6 +210,14 @@ type affiliateCreatePostbackResponse struct {
 
 // CreatePostback adds postback.
 func (s *AffiliateService) CreatePostback(ctx context.Context, opts *AffiliateCreatePostbackOpts) (*Postback, *Response, error) {
+	ctx = withServiceMethod(ctx, "Affiliate.CreatePostback")
+
+	if opts != nil {
+		if err := opts.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	path := "/3.0/partner/postback"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, false)
@@ -180,6 +241,11 @@ type AffiliateUpdatePostbackOpts struct {
 	Goal   string `schema:"goal,omitempty"`   // Postback goal (value)
 }
 
+// Validate checks the macros of the postback URL.
+func (opts *AffiliateUpdatePostbackOpts) Validate() error {
+	return validatePostbackURL(opts.URL)
+}
+
 // affiliateUpdatePostbackResponse specifies response for UpdatePostback.
 type affiliateUpdatePostbackResponse struct {
 	Postback *Postback `json:"postback"`
@@ -187,6 +253,14 @@ type affiliateUpdatePostbackResponse struct {
 
 // UpdatePostback edits postback.
 func (s *AffiliateService) UpdatePostback(ctx context.Context, id int, opts *AffiliateUpdatePostbackOpts) (*Postback, *Response, error) {
+	ctx = withServiceMethod(ctx, "Affiliate.UpdatePostback")
+
+	if opts != nil {
+		if err := opts.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	path := fmt.Sprintf("/3.0/partner/postback/%d", id)
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, false)
@@ -210,6 +284,8 @@ type affiliateDeletePostbackResponse struct {
 
 // DeletePostback deletes postback.
 func (s *AffiliateService) DeletePostback(ctx context.Context, id int) (*Postback, *Response, error) {
+	ctx = withServiceMethod(ctx, "Affiliate.DeletePostback")
+
 	path := fmt.Sprintf("/3.0/partner/postback/%d/remove", id)
 
 	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil, false)
@@ -240,6 +316,8 @@ func (opts *AffiliateDeletePostbacksByAffiliatesOpts) values() (url.Values, erro
 
 // DELETE /3.0/partner/postbacks/by-affiliates.
 func (s *AffiliateService) DeletePostbacksByAffiliates(ctx context.Context, opts *AffiliateDeletePostbacksByAffiliatesOpts) (*Response, error) {
+	ctx = withServiceMethod(ctx, "Affiliate.DeletePostbacksByAffiliates")
+
 	path := "/3.0/partner/postbacks/by-affiliates"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodDelete, path, opts, nil, false)
@@ -269,6 +347,8 @@ func (opts *AffiliateDeletePostbacksByOffersOpts) values() (url.Values, error) {
 
 // DeletePostbacksByOffers deletes postbacks by offers ids.
 func (s *AffiliateService) DeletePostbacksByOffers(ctx context.Context, opts *AffiliateDeletePostbacksByOffersOpts) (*Response, error) {
+	ctx = withServiceMethod(ctx, "Affiliate.DeletePostbacksByOffers")
+
 	path := "/3.0/partner/postbacks/by-offers"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodDelete, path, opts, nil, false)
@@ -307,6 +387,8 @@ func (a *affiliateListNewsResponse) slice() []*NewsItem {
 
 // ListNews gets news list.
 func (s *AffiliateService) ListNews(ctx context.Context, opts *AffiliateListNewsOpts) ([]*NewsItem, *Response, error) {
+	ctx = withServiceMethod(ctx, "Affiliate.ListNews")
+
 	path := "/3.0/news"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -330,6 +412,8 @@ type affiliateGetNewsResponse struct {
 
 // GetNews get news by ID.
 func (s *AffiliateService) GetNewsByID(ctx context.Context, id string) (*NewsItem, *Response, error) {
+	ctx = withServiceMethod(ctx, "Affiliate.GetNewsByID")
+
 	path := fmt.Sprintf("/3.0/news/%s", id)
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, false)
@@ -362,6 +446,8 @@ func (a *affiliateListPixelsResponse) slice() []*Pixel {
 
 // ListPixels gets list of a partner’s pixels.
 func (s *AffiliateService) ListPixels(ctx context.Context) ([]*Pixel, *Response, error) {
+	ctx = withServiceMethod(ctx, "Affiliate.ListPixels")
+
 	path := "/3.0/partner/pixels"
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, false)
@@ -393,6 +479,8 @@ type affiliateCreatePixelResponse struct {
 
 // CreatePixel adds a partner’s pixel.
 func (s *AffiliateService) CreatePixel(ctx context.Context, opts *AffiliateCreatePixelOpts) (*Pixel, *Response, error) {
+	ctx = withServiceMethod(ctx, "Affiliate.CreatePixel")
+
 	path := "/3.0/partner/pixel"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, false)
@@ -423,6 +511,8 @@ type affiliateUpdatePixelResponse struct {
 
 // UpdatePixel edits a partner’s pixel.
 func (s *AffiliateService) UpdatePixel(ctx context.Context, id int, opts *AffiliateUpdatePixelOpts) (*Pixel, *Response, error) {
+	ctx = withServiceMethod(ctx, "Affiliate.UpdatePixel")
+
 	path := fmt.Sprintf("/3.0/partner/pixel/%d", id)
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodPost, path, opts, nil, false)
@@ -446,6 +536,8 @@ type affiliateDeletePixelResponse struct {
 
 // Pixel remove deletes a partner’s pixel.
 func (s *AffiliateService) DeletePixel(ctx context.Context, id int) (*Pixel, *Response, error) {
+	ctx = withServiceMethod(ctx, "Affiliate.DeletePixel")
+
 	path := fmt.Sprintf("/3.0/partner/pixel/%d/remove", id)
 
 	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil, false)
@@ -469,6 +561,8 @@ type affiliateGetAffiliateBalanceResponse struct {
 
 // GetAffiliateBalance gets current affiliate balance.
 func (s *AffiliateService) GetAffiliateBalance(ctx context.Context) (Balance, *Response, error) {
+	ctx = withServiceMethod(ctx, "Affiliate.GetAffiliateBalance")
+
 	path := "/3.0/balance"
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, false)
@@ -497,6 +591,8 @@ type affiliateGetSmartLinkCategoriesResponse struct {
 
 // GetSmartLinkCategories gets SmartLink categories list.
 func (s *AffiliateService) GetSmartLinkCategories(ctx context.Context, opts *AffiliateGetSmartLinkCategoriesOpts) ([]*SmartLinkCategory, *Response, error) {
+	ctx = withServiceMethod(ctx, "Affiliate.GetSmartLinkCategories")
+
 	path := "/3.0/partner/smartlink/categories"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -522,6 +618,8 @@ type affiliateGetSmartLinkOfferCountResponse struct {
 
 // GetSmartLinkOfferCount gets SmartLink offer count.
 func (s *AffiliateService) GetSmartLinkOfferCount(ctx context.Context, id string) (int, *Response, error) {
+	ctx = withServiceMethod(ctx, "Affiliate.GetSmartLinkOfferCount")
+
 	path := fmt.Sprintf("/3.0/partner/smartlink/category/%s/offers-count", id)
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, false)
@@ -554,6 +652,8 @@ func (a *affiliateGetReferralsResponse) slice() []*Affiliate {
 
 // GetReferrals gets referrals by partner ID.
 func (s *AffiliateService) GetReferrals(ctx context.Context, affiliateID uint64) ([]*Affiliate, *Response, error) {
+	ctx = withServiceMethod(ctx, "Affiliate.GetReferrals")
+
 	path := fmt.Sprintf("/3.0/admin/partner/%d/referrals", affiliateID)
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, false)
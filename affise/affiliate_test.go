@@ -1,7 +1,10 @@
 package affise_test
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -413,3 +416,23 @@ func TestAffiliateService_Mock(t *testing.T) {
 		require.True(t, ok)
 	})
 }
+
+func TestAffiliateService_InvalidPostbackURL(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+	defer env.teardown()
+
+	var calls int32
+	env.Mux.HandleFunc("/3.0/partner/postback", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	})
+
+	opts := &affise.AffiliateCreatePostbackOpts{AffiliateID: 610, URL: "https://example.com/pb?cid={clikid}"}
+	_, _, err := env.Client.Affiliate.CreatePostback(env.Ctx, opts)
+	require.True(t, errors.Is(err, affise.ErrInvalidPostbackURL))
+	require.Contains(t, err.Error(), "did you mean {clickid}")
+	require.Zero(t, atomic.LoadInt32(&calls))
+
+	require.NoError(t, (&affise.AdminAffiliateEditPostbackOpts{URL: "https://example.com/pb?cid={clickid}&sum={sum}"}).Validate())
+	require.Error(t, (&affise.AffiliateUpdatePostbackOpts{URL: "example.com/pb"}).Validate())
+}
@@ -0,0 +1,1091 @@
+package affisetest
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/schema"
+
+	"github.com/clobucks/go-sdk/affise"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// Backend is an in-memory Affise backend. Offers, partners, conversions,
+// postbacks and pixels created through the API are kept and returned by the
+// list and get endpoints with real pagination.
+//
+// Served endpoints:
+//
+//	POST   /3.0/admin/offer, /3.0/admin/offer/{id}, /3.0/admin/offer/delete
+//	GET    /3.0/offers, /3.0/offer/{id}
+//	POST   /3.0/admin/partner, /3.0/admin/partner/{id}
+//	GET    /3.0/admin/partners, /3.0/admin/partner/{id}
+//	POST   /3.0/admin/conversion/import, /3.0/admin/conversions/import
+//	GET    /3.0/stats/conversions
+//	POST   /3.0/partner/postback, /3.0/partner/postback/{id}
+//	DELETE /3.0/partner/postback/{id}/remove, /3.0/partner/postbacks/by-affiliates, /3.0/partner/postbacks/by-offers
+//	GET    /3.0/admin/postbacks
+//	POST   /3.0/partner/pixel, /3.0/partner/pixel/{id}
+//	DELETE /3.0/partner/pixel/{id}/remove
+//	GET    /3.0/partner/pixels
+//
+// Offer payments, landings, caps, commission tiers and targeting sent with
+// the bracket notation are stored. Date filters of the statistics are ignored.
+type Backend struct {
+	mu          sync.Mutex
+	ids         map[string]int
+	offers      []*affise.Offer
+	partners    []*affise.Affiliate
+	conversions []*affise.Conversion
+	postbacks   []*postback
+	pixels      []*affise.Pixel
+}
+
+// postback is a stored postback with the offer it belongs to.
+type postback struct {
+	affise.Postback
+	OfferID string `json:"offer_id,omitempty"`
+}
+
+// NewBackend creates an empty backend.
+func NewBackend() *Backend {
+	return &Backend{ids: make(map[string]int)}
+}
+
+// AddOffer stores a copy of the offer. A new ID is assigned if o.ID is zero.
+func (b *Backend) AddOffer(o affise.Offer) *affise.Offer {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if o.ID == 0 {
+		o.ID = b.nextID("offer")
+	}
+	if o.OfferID == "" {
+		o.OfferID = fmt.Sprintf("%024x", o.ID)
+	}
+	b.offers = append(b.offers, &o)
+
+	return &o
+}
+
+// AddPartner stores a copy of the partner. A new ID is assigned if p.ID is zero.
+func (b *Backend) AddPartner(p affise.Affiliate) *affise.Affiliate {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if p.ID == 0 {
+		p.ID = uint64(b.nextID("partner"))
+	}
+	b.partners = append(b.partners, &p)
+
+	return &p
+}
+
+// AddConversion stores a copy of the conversion. A new ID is assigned if c.ID is empty.
+func (b *Backend) AddConversion(c affise.Conversion) *affise.Conversion {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if c.ID == "" {
+		c.ID = fmt.Sprintf("%024x", b.nextID("conversion"))
+	}
+	b.conversions = append(b.conversions, &c)
+
+	return &c
+}
+
+// Offers returns copies of the stored offers.
+func (b *Backend) Offers() []affise.Offer {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ret := make([]affise.Offer, 0, len(b.offers))
+	for _, o := range b.offers {
+		ret = append(ret, *o)
+	}
+
+	return ret
+}
+
+// Partners returns copies of the stored partners.
+func (b *Backend) Partners() []affise.Affiliate {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ret := make([]affise.Affiliate, 0, len(b.partners))
+	for _, p := range b.partners {
+		ret = append(ret, *p)
+	}
+
+	return ret
+}
+
+// Conversions returns copies of the stored conversions.
+func (b *Backend) Conversions() []affise.Conversion {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ret := make([]affise.Conversion, 0, len(b.conversions))
+	for _, c := range b.conversions {
+		ret = append(ret, *c)
+	}
+
+	return ret
+}
+
+// Postbacks returns copies of the stored postbacks.
+func (b *Backend) Postbacks() []affise.Postback {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ret := make([]affise.Postback, 0, len(b.postbacks))
+	for _, p := range b.postbacks {
+		ret = append(ret, p.Postback)
+	}
+
+	return ret
+}
+
+// Pixels returns copies of the stored pixels.
+func (b *Backend) Pixels() []affise.Pixel {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ret := make([]affise.Pixel, 0, len(b.pixels))
+	for _, p := range b.pixels {
+		ret = append(ret, *p)
+	}
+
+	return ret
+}
+
+func (b *Backend) nextID(kind string) int {
+	b.ids[kind]++
+
+	return b.ids[kind]
+}
+
+func (b *Backend) routes() []*route {
+	handlers := []struct {
+		method  string
+		pattern string
+		handler func(http.ResponseWriter, *http.Request)
+	}{
+		{http.MethodPost, "/3.0/admin/offer/delete", b.deleteOffers},
+		{http.MethodPost, "/3.0/admin/offer", b.createOffer},
+		{http.MethodPost, "/3.0/admin/offer/{id}", b.updateOffer},
+		{http.MethodGet, "/3.0/offers", b.listOffers},
+		{http.MethodGet, "/3.0/offer/{id}", b.getOffer},
+		{http.MethodPost, "/3.0/admin/partner", b.createPartner},
+		{http.MethodPost, "/3.0/admin/partner/{id}", b.updatePartner},
+		{http.MethodGet, "/3.0/admin/partner/{id}", b.getPartner},
+		{http.MethodGet, "/3.0/admin/partners", b.listPartners},
+		{http.MethodPost, "/3.0/admin/conversion/import", b.importConversion},
+		{http.MethodPost, "/3.0/admin/conversions/import", b.importConversions},
+		{http.MethodGet, "/3.0/stats/conversions", b.listConversions},
+		{http.MethodPost, "/3.0/partner/postback", b.createPostback},
+		{http.MethodPost, "/3.0/partner/postback/{id}", b.updatePostback},
+		{http.MethodDelete, "/3.0/partner/postback/{id}/remove", b.deletePostback},
+		{http.MethodDelete, "/3.0/partner/postbacks/by-affiliates", b.deletePostbacksByAffiliates},
+		{http.MethodDelete, "/3.0/partner/postbacks/by-offers", b.deletePostbacksByOffers},
+		{http.MethodGet, "/3.0/admin/postbacks", b.listPostbacks},
+		{http.MethodPost, "/3.0/partner/pixel", b.createPixel},
+		{http.MethodPost, "/3.0/partner/pixel/{id}", b.updatePixel},
+		{http.MethodDelete, "/3.0/partner/pixel/{id}/remove", b.deletePixel},
+		{http.MethodGet, "/3.0/partner/pixels", b.listPixels},
+	}
+
+	routes := make([]*route, 0, len(handlers))
+	for _, h := range handlers {
+		routes = append(routes, newRoute(h.method, h.pattern, http.HandlerFunc(h.handler)))
+	}
+
+	return routes
+}
+
+// Offers.
+
+func (b *Backend) createOffer(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if errs := required(q, "title", "advertiser", "url"); errs != nil {
+		validationError(w, errs)
+
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now().Format(timeLayout)
+	o := &affise.Offer{
+		ID:        b.nextID("offer"),
+		Status:    "stopped",
+		Privacy:   "public",
+		CreatedAt: now,
+	}
+	o.OfferID = fmt.Sprintf("%024x", o.ID)
+	applyOffer(o, q)
+	o.UpdatedAt = now
+	b.offers = append(b.offers, o)
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"status": 1, "offer": o})
+}
+
+func (b *Backend) updateOffer(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	o := b.findOffer(r, 3)
+	if o == nil {
+		NotFound(w, "Offer not found")
+
+		return
+	}
+	applyOffer(o, r.URL.Query())
+	o.UpdatedAt = time.Now().Format(timeLayout)
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"status": 1, "offer": o})
+}
+
+func (b *Backend) deleteOffers(w http.ResponseWriter, r *http.Request) {
+	ids := indexedInts(r.URL.Query(), "offer_id")
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	offers := b.offers[:0]
+	for _, o := range b.offers {
+		if !containsInt(ids, o.ID) {
+			offers = append(offers, o)
+		}
+	}
+	b.offers = offers
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"status": 1})
+}
+
+func (b *Backend) getOffer(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	o := b.findOffer(r, 2)
+	if o == nil {
+		NotFound(w, "Offer not found")
+
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"status": 1, "offer": o})
+}
+
+func (b *Backend) listOffers(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	search := strings.ToLower(q.Get("q"))
+	intIDs := ints(q, "int_id")
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	offers := make([]*affise.Offer, 0, len(b.offers))
+	for _, o := range b.offers {
+		switch {
+		case search != "" && !strings.Contains(strings.ToLower(o.Title), search) && strconv.Itoa(o.ID) != search:
+		case len(intIDs) != 0 && !containsInt(intIDs, o.ID):
+		case !matchString(q, "ids", o.OfferID):
+		case !matchString(q, "status", o.Status):
+		case !matchString(q, "advertiser", o.Advertiser):
+		case q.Get("bundle_id") != "" && q.Get("bundle_id") != o.BundleID:
+		default:
+			offers = append(offers, o)
+		}
+	}
+
+	from, to, pagination := paginate(q, len(offers), 20)
+	WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"status":     1,
+		"offers":     offers[from:to],
+		"pagination": pagination,
+	})
+}
+
+func (b *Backend) findOffer(r *http.Request, segment int) *affise.Offer {
+	id, ok := pathInt(r, segment)
+	if !ok {
+		return nil
+	}
+
+	for _, o := range b.offers {
+		if o.ID == id {
+			return o
+		}
+	}
+
+	return nil
+}
+
+func applyOffer(o *affise.Offer, q url.Values) {
+	setString(&o.Title, q, "title")
+	setString(&o.Advertiser, q, "advertiser")
+	setString(&o.URL, q, "url")
+	setString(&o.URLPreview, q, "url_preview")
+	setString(&o.MacroURL, q, "macro_url")
+	setString(&o.CrossPostbackURL, q, "cross_postback_url")
+	setString(&o.Status, q, "status")
+	setString(&o.Privacy, q, "privacy")
+	setString(&o.Notes, q, "notes")
+	setString(&o.ExternalOfferID, q, "external_offer_id")
+	setString(&o.BundleID, q, "bundle_id")
+	setString(&o.StartAt, q, "start_at")
+	setString(&o.AllowedIP, q, "allowed_ip")
+	setString(&o.DisallowedIP, q, "disallowed_ip")
+	setString(&o.RedirectType, q, "redirect_type")
+	setString(&o.CapsTimezone, q, "caps_timezone")
+	setString(&o.ClickSession, q, "click_session")
+	setString(&o.MinimalClickSession, q, "minimal_click_session")
+	setStrings(&o.Tags, q, "tags")
+	setStrings(&o.Categories, q, "categories")
+	setStrings(&o.StrictlyDevices, q, "strictly_devices")
+	setStrings(&o.StrictlyBrands, q, "strictly_brands")
+	setStrings(&o.CapsStatus, q, "caps_status")
+	setInt(&o.IsTop, q, "is_top")
+	setInt(&o.HoldPeriod, q, "hold_period")
+	setInt(&o.StrictlyCountry, q, "strictly_country")
+	setBool(&o.IsCPI, q, "is_cpi")
+	setBool(&o.HidePayments, q, "hide_payments")
+	decodeForm(q, "payments", &o.Payments)
+	decodeForm(q, "partner_payments", &o.PartnerPayments)
+	decodeForm(q, "landings", &o.Landings)
+	decodeForm(q, "caps", &o.Caps)
+	decodeForm(q, "commission_tiers", &o.CommissionTiers)
+	decodeForm(q, "targeting", &o.Targeting)
+	if v, ok := q["sources"]; ok {
+		o.Sources = make([]affise.Source, 0, len(v))
+		for _, id := range v {
+			o.Sources = append(o.Sources, affise.Source{ID: id, Allowed: 1})
+		}
+	}
+}
+
+// Partners.
+
+var partnerStatuses = map[string]string{
+	"0": "not active",
+	"1": "active",
+	"2": "banned",
+	"3": "on moderation",
+}
+
+func (b *Backend) createPartner(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	errs := required(q, "email", "password", "country")
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	email := q.Get("email")
+	for _, p := range b.partners {
+		if email != "" && strings.EqualFold(p.Email, email) {
+			if errs == nil {
+				errs = make(map[string][]string)
+			}
+			errs["email"] = append(errs["email"], "Email has already been taken.")
+		}
+	}
+	if errs != nil {
+		validationError(w, errs)
+
+		return
+	}
+
+	now := time.Now().Format(timeLayout)
+	p := &affise.Affiliate{
+		ID:        uint64(b.nextID("partner")),
+		Email:     email,
+		Status:    "active",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	p.APIKey = fmt.Sprintf("%032x", p.ID)
+	applyPartner(p, q)
+	b.partners = append(b.partners, p)
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"status": 1, "partner": p})
+}
+
+func (b *Backend) updatePartner(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	p := b.findPartner(r, 3)
+	if p == nil {
+		NotFound(w, "Partner not found")
+
+		return
+	}
+	applyPartner(p, r.URL.Query())
+	p.UpdatedAt = time.Now().Format(timeLayout)
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"status": 1, "partner": p})
+}
+
+func (b *Backend) getPartner(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	p := b.findPartner(r, 3)
+	if p == nil {
+		NotFound(w, "Partner not found")
+
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"status": 1, "partner": p})
+}
+
+func (b *Backend) listPartners(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	ids := ints(q, "id")
+	status, filterStatus := partnerStatuses[q.Get("status_partner")]
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	partners := make([]*affise.Affiliate, 0, len(b.partners))
+	for _, p := range b.partners {
+		switch {
+		case len(ids) != 0 && !containsInt(ids, int(p.ID)):
+		case filterStatus && p.Status != status:
+		default:
+			partners = append(partners, p)
+		}
+	}
+
+	from, to, pagination := paginate(q, len(partners), 100)
+	WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"status":     1,
+		"partners":   partners[from:to],
+		"pagination": pagination,
+	})
+}
+
+func (b *Backend) findPartner(r *http.Request, segment int) *affise.Affiliate {
+	id, ok := pathInt(r, segment)
+	if !ok {
+		return nil
+	}
+
+	for _, p := range b.partners {
+		if p.ID == uint64(id) {
+			return p
+		}
+	}
+
+	return nil
+}
+
+func applyPartner(p *affise.Affiliate, q url.Values) {
+	setString(&p.Login, q, "login")
+	setString(&p.Country, q, "country")
+	setString(&p.ContactPerson, q, "contact_person")
+	setString(&p.RefPercent, q, "ref_percent")
+	setString(&p.Notes, q, "notes")
+	setString(&p.Status, q, "status")
+}
+
+// Conversions.
+
+var conversionStatuses = map[string]string{
+	"1": "confirmed",
+	"2": "pending",
+	"3": "declined",
+	"4": "not_found",
+	"5": "hold",
+}
+
+var decoder = newDecoder()
+
+func newDecoder() *schema.Decoder {
+	d := schema.NewDecoder()
+	d.IgnoreUnknownKeys(true)
+
+	return d
+}
+
+func (b *Backend) importConversion(w http.ResponseWriter, r *http.Request) {
+	opts, errs := decodeConversion(r.URL.Query())
+	if errs != nil {
+		validationError(w, errs)
+
+		return
+	}
+
+	b.mu.Lock()
+	b.addImported(opts)
+	b.mu.Unlock()
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  1,
+		"data":    opts,
+		"message": "Conversion import will take a few minutes",
+	})
+}
+
+func (b *Backend) importConversions(w http.ResponseWriter, r *http.Request) {
+	// the list is sent in the query or, by BulkImport, as a form body
+	if err := r.ParseForm(); err != nil {
+		validationError(w, map[string][]string{"list": {err.Error()}})
+
+		return
+	}
+	rows := indexedValues(r.Form, "list")
+
+	list := make([]*affise.AdminConversionImportOpts, 0, len(rows))
+	for i, row := range rows {
+		opts, errs := decodeConversion(row)
+		if errs != nil {
+			fields := make(map[string][]string, len(errs))
+			for k, v := range errs {
+				fields[fmt.Sprintf("list.%d.%s", i, k)] = v
+			}
+			validationError(w, fields)
+
+			return
+		}
+		list = append(list, opts)
+	}
+
+	b.mu.Lock()
+	for _, opts := range list {
+		b.addImported(opts)
+	}
+	b.mu.Unlock()
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  1,
+		"data":    map[string]interface{}{"list": list},
+		"message": "Conversions import will take a few minutes",
+	})
+}
+
+func decodeConversion(q url.Values) (*affise.AdminConversionImportOpts, map[string][]string) {
+	if errs := required(q, "offer", "pid"); errs != nil {
+		return nil, errs
+	}
+
+	opts := new(affise.AdminConversionImportOpts)
+	if err := decoder.Decode(opts, q); err != nil {
+		return nil, map[string][]string{"data": {err.Error()}}
+	}
+
+	return opts, nil
+}
+
+func (b *Backend) addImported(opts *affise.AdminConversionImportOpts) {
+	id := b.nextID("conversion")
+	c := &affise.Conversion{
+		ID:           fmt.Sprintf("%024x", id),
+		ConversionID: fmt.Sprintf("%024x", id),
+		ActionID:     opts.ActionID,
+		Clickid:      opts.ClickID,
+		Status:       opts.Status,
+		OfferID:      uint64(opts.Offer),
+		AffiliateID:  opts.AffiliateID,
+		Goal:         "1",
+		IP:           opts.IP,
+		UA:           opts.UA,
+		Comment:      opts.Comment,
+		Sum:          float32(opts.Sum),
+		CustomField1: opts.CustomField1,
+		CustomField2: opts.CustomField2,
+		CustomField3: opts.CustomField3,
+		CustomField4: opts.CustomField4,
+		CustomField5: opts.CustomField5,
+		CustomField6: opts.CustomField6,
+		CustomField7: opts.CustomField7,
+		CreatedAt:    time.Now().Format(timeLayout),
+	}
+	if c.ActionID == "" {
+		c.ActionID = c.ID
+	}
+	if c.Status == "" {
+		c.Status = "confirmed"
+	}
+	if opts.Goal != 0 {
+		c.Goal = strconv.Itoa(opts.Goal)
+	}
+
+	for _, o := range b.offers {
+		if o.ID == opts.Offer {
+			c.Offer = o
+			c.AdvertiserID = o.Advertiser
+		}
+	}
+	for _, p := range b.partners {
+		if p.ID == opts.AffiliateID {
+			c.Partner = p
+		}
+	}
+
+	b.conversions = append(b.conversions, c)
+}
+
+func (b *Backend) listConversions(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	offers := ints(q, "offer")
+	partners := ints(q, "partner")
+	statuses := make([]string, 0, len(q["status"]))
+	for _, s := range q["status"] {
+		statuses = append(statuses, conversionStatuses[s])
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	conversions := make([]*affise.Conversion, 0, len(b.conversions))
+	for _, c := range b.conversions {
+		switch {
+		case len(offers) != 0 && !containsInt(offers, int(c.OfferID)):
+		case len(partners) != 0 && !containsInt(partners, int(c.AffiliateID)):
+		case len(statuses) != 0 && !containsString(statuses, c.Status):
+		case q.Get("action_id") != "" && q.Get("action_id") != c.ActionID:
+		case q.Get("clickid") != "" && q.Get("clickid") != c.Clickid:
+		case q.Get("goal") != "" && q.Get("goal") != c.Goal:
+		default:
+			conversions = append(conversions, c)
+		}
+	}
+
+	from, to, pagination := paginate(q, len(conversions), 100)
+	WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"status":      1,
+		"conversions": conversions[from:to],
+		"pagination":  pagination,
+	})
+}
+
+// Postbacks.
+
+func (b *Backend) createPostback(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if errs := required(q, "url", "pid"); errs != nil {
+		validationError(w, errs)
+
+		return
+	}
+
+	affiliateID, err := strconv.ParseUint(q.Get("pid"), 10, 64)
+	if err != nil {
+		validationError(w, map[string][]string{"pid": {"Pid must be an integer."}})
+
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	p := &postback{
+		Postback: affise.Postback{
+			ID:          b.nextID("postback"),
+			AffiliateID: affiliateID,
+			Created:     time.Now().Format(timeLayout),
+			Forced:      "0",
+		},
+		OfferID: q.Get("offer_id"),
+	}
+	applyPostback(p, q)
+	b.postbacks = append(b.postbacks, p)
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"status": 1, "postback": p})
+}
+
+func (b *Backend) updatePostback(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	i := b.findPostback(r)
+	if i < 0 {
+		NotFound(w, "Postback not found")
+
+		return
+	}
+	p := b.postbacks[i]
+	applyPostback(p, r.URL.Query())
+	p.UpdatedAt = time.Now().Format(timeLayout)
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"status": 1, "postback": p})
+}
+
+func (b *Backend) deletePostback(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	i := b.findPostback(r)
+	if i < 0 {
+		NotFound(w, "Postback not found")
+
+		return
+	}
+	p := b.postbacks[i]
+	b.postbacks = append(b.postbacks[:i], b.postbacks[i+1:]...)
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"status": 1, "postback": p})
+}
+
+func (b *Backend) deletePostbacksByAffiliates(w http.ResponseWriter, r *http.Request) {
+	ids := ints(r.URL.Query(), "ids")
+
+	b.deletePostbacks(func(p *postback) bool {
+		return containsInt(ids, int(p.AffiliateID))
+	})
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"status": 1})
+}
+
+func (b *Backend) deletePostbacksByOffers(w http.ResponseWriter, r *http.Request) {
+	ids := ints(r.URL.Query(), "ids")
+
+	b.deletePostbacks(func(p *postback) bool {
+		id, err := strconv.Atoi(p.OfferID)
+
+		return err == nil && containsInt(ids, id)
+	})
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"status": 1})
+}
+
+func (b *Backend) deletePostbacks(match func(*postback) bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	postbacks := b.postbacks[:0]
+	for _, p := range b.postbacks {
+		if !match(p) {
+			postbacks = append(postbacks, p)
+		}
+	}
+	b.postbacks = postbacks
+}
+
+func (b *Backend) listPostbacks(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	postbacks := make([]*postback, 0, len(b.postbacks))
+	for _, p := range b.postbacks {
+		if q.Get("partner_id") == "" || q.Get("partner_id") == strconv.FormatUint(p.AffiliateID, 10) {
+			postbacks = append(postbacks, p)
+		}
+	}
+
+	from, to, pagination := paginate(q, len(postbacks), 100)
+	WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"status":     1,
+		"postbacks":  postbacks[from:to],
+		"pagination": pagination,
+	})
+}
+
+func (b *Backend) findPostback(r *http.Request) int {
+	id, ok := pathInt(r, 3)
+	if !ok {
+		return -1
+	}
+
+	for i, p := range b.postbacks {
+		if p.ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func applyPostback(p *postback, q url.Values) {
+	setString(&p.URL, q, "url")
+	setString(&p.Status, q, "status")
+	setString(&p.Goal, q, "goal")
+}
+
+// Pixels.
+
+func (b *Backend) createPixel(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if errs := required(q, "offer_id", "name", "code", "code_type"); errs != nil {
+		validationError(w, errs)
+
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now().Format(timeLayout)
+	p := &affise.Pixel{
+		ID:               b.nextID("pixel"),
+		OfferID:          q.Get("offer_id"),
+		IsActive:         "1",
+		ModerationStatus: "0",
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	applyPixel(p, q)
+	b.pixels = append(b.pixels, p)
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"status": 1, "pixel": p})
+}
+
+func (b *Backend) updatePixel(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	i := b.findPixel(r)
+	if i < 0 {
+		NotFound(w, "Pixel not found")
+
+		return
+	}
+	p := b.pixels[i]
+	applyPixel(p, r.URL.Query())
+	p.UpdatedAt = time.Now().Format(timeLayout)
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"status": 1, "pixel": p})
+}
+
+func (b *Backend) deletePixel(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	i := b.findPixel(r)
+	if i < 0 {
+		NotFound(w, "Pixel not found")
+
+		return
+	}
+	p := b.pixels[i]
+	b.pixels = append(b.pixels[:i], b.pixels[i+1:]...)
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"status": 1, "pixel": p})
+}
+
+func (b *Backend) listPixels(w http.ResponseWriter, _ *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	pixels := make(map[int]*affise.Pixel, len(b.pixels))
+	for _, p := range b.pixels {
+		pixels[p.ID] = p
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"status": 1, "pixel": pixels})
+}
+
+func (b *Backend) findPixel(r *http.Request) int {
+	id, ok := pathInt(r, 3)
+	if !ok {
+		return -1
+	}
+
+	for i, p := range b.pixels {
+		if p.ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func applyPixel(p *affise.Pixel, q url.Values) {
+	setString(&p.Name, q, "name")
+	setString(&p.Code, q, "code")
+	setString(&p.CodeType, q, "code_type")
+}
+
+// Helpers.
+
+// paginate returns the bounds of the requested page of n items and its pagination meta.
+func paginate(q url.Values, n, defaultLimit int) (from, to int, p affise.Pagination) {
+	page, _ := strconv.Atoi(q.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit < 1 {
+		limit = defaultLimit
+	}
+
+	from = (page - 1) * limit
+	if from > n {
+		from = n
+	}
+	to = from + limit
+	if to > n {
+		to = n
+	}
+
+	p = affise.Pagination{Page: page, PerPage: limit, TotalCount: n}
+	if to < n {
+		p.NextPage = page + 1
+	}
+
+	return from, to, p
+}
+
+func validationError(w http.ResponseWriter, fields map[string][]string) {
+	WriteJSON(w, http.StatusBadRequest, map[string]interface{}{
+		"status":  2,
+		"message": fields,
+	})
+}
+
+// required returns field errors for the missing keys or nil.
+func required(q url.Values, keys ...string) map[string][]string {
+	var errs map[string][]string
+	for _, key := range keys {
+		if q.Get(key) != "" {
+			continue
+		}
+		if errs == nil {
+			errs = make(map[string][]string)
+		}
+		name := strings.ReplaceAll(key, "_", " ")
+		errs[key] = []string{strings.ToUpper(name[:1]) + name[1:] + " cannot be blank."}
+	}
+
+	return errs
+}
+
+func pathInt(r *http.Request, segment int) (int, bool) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if segment >= len(segments) {
+		return 0, false
+	}
+
+	id, err := strconv.Atoi(segments[segment])
+
+	return id, err == nil
+}
+
+// ints parses the repeated or comma separated integer values of the key.
+func ints(q url.Values, key string) []int {
+	var ret []int
+	for _, v := range append(q[key], q[key+"[]"]...) {
+		for _, s := range strings.Split(v, ",") {
+			if n, err := strconv.Atoi(strings.TrimSpace(s)); err == nil {
+				ret = append(ret, n)
+			}
+		}
+	}
+
+	return ret
+}
+
+// indexedInts parses values of keys like "offer_id[0]".
+func indexedInts(q url.Values, key string) []int {
+	var ret []int
+	for k, v := range q {
+		if !strings.HasPrefix(k, key+"[") {
+			continue
+		}
+		for _, s := range v {
+			if n, err := strconv.Atoi(s); err == nil {
+				ret = append(ret, n)
+			}
+		}
+	}
+
+	return ret
+}
+
+// indexedValues splits keys like "list[0][offer]" into values per index.
+func indexedValues(q url.Values, key string) []url.Values {
+	rows := make(map[int]url.Values)
+	last := -1
+	for k, v := range q {
+		if !strings.HasPrefix(k, key+"[") || !strings.HasSuffix(k, "]") {
+			continue
+		}
+		rest := strings.TrimSuffix(strings.TrimPrefix(k, key+"["), "]")
+		j := strings.Index(rest, "][")
+		if j < 0 {
+			continue
+		}
+		i, err := strconv.Atoi(rest[:j])
+		if err != nil {
+			continue
+		}
+
+		if rows[i] == nil {
+			rows[i] = url.Values{}
+		}
+		rows[i][rest[j+2:]] = v
+		if i > last {
+			last = i
+		}
+	}
+
+	ret := make([]url.Values, 0, len(rows))
+	for i := 0; i <= last; i++ {
+		if row, ok := rows[i]; ok {
+			ret = append(ret, row)
+		}
+	}
+
+	return ret
+}
+
+// matchString reports whether the value is one of the values of the key or the key is missing.
+func matchString(q url.Values, key, value string) bool {
+	values := append(q[key], q[key+"[]"]...)
+
+	return len(values) == 0 || containsString(values, value)
+}
+
+func containsInt(s []int, n int) bool {
+	for _, v := range s {
+		if v == n {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsString(s []string, str string) bool {
+	for _, v := range s {
+		if v == str {
+			return true
+		}
+	}
+
+	return false
+}
+
+func setString(dst *string, q url.Values, key string) {
+	if v, ok := q[key]; ok {
+		*dst = v[0]
+	}
+}
+
+func setStrings(dst *[]string, q url.Values, key string) {
+	if v, ok := q[key]; ok {
+		*dst = append([]string(nil), v...)
+	}
+}
+
+func setInt(dst *int, q url.Values, key string) {
+	if v, ok := q[key]; ok {
+		*dst, _ = strconv.Atoi(v[0])
+	}
+}
+
+func setBool(dst *bool, q url.Values, key string) {
+	if v, ok := q[key]; ok {
+		*dst = v[0] == "1" || v[0] == "true"
+	}
+}
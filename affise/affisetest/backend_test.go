@@ -0,0 +1,295 @@
+package affisetest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/clobucks/go-sdk/affise"
+	"github.com/clobucks/go-sdk/affise/affisetest"
+)
+
+func TestBackend_Offers(t *testing.T) {
+	t.Parallel()
+
+	backend := affisetest.NewBackend()
+	server := affisetest.NewServer(t, affisetest.WithBackend(backend))
+	client := server.Client
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		offer, _, err := client.AdminOffer.CreateOffer(ctx, &affise.AdminOfferCreateOfferOpts{
+			Title:      fmt.Sprintf("Offer %d", i),
+			Advertiser: "5bc9d7c16d73e41c008b4567",
+			URL:        "https://example.com/?click={clickid}",
+			Tags:       []string{"test"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, i, offer.ID)
+		require.Equal(t, "stopped", offer.Status)
+	}
+
+	offer, _, err := client.Offer.Get(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, "Offer 2", offer.Title)
+	require.Equal(t, []string{"test"}, offer.Tags)
+
+	offer, _, err = client.AdminOffer.UpdateOffer(ctx, 2, &affise.AdminOfferUpdateOfferOpts{Status: "active"})
+	require.NoError(t, err)
+	require.Equal(t, "active", offer.Status)
+	require.Equal(t, "Offer 2", offer.Title)
+
+	offers, resp, err := client.Offer.List(ctx, &affise.OfferListOpts{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, offers, 2)
+	require.Equal(t, affise.Pagination{Page: 1, PerPage: 2, TotalCount: 3, NextPage: 2}, *resp.Meta.Pagination)
+
+	offers, err = client.Offer.ListIter(ctx, &affise.OfferListOpts{Limit: 2}).All(0)
+	require.NoError(t, err)
+	require.Len(t, offers, 3)
+
+	offers, _, err = client.Offer.List(ctx, &affise.OfferListOpts{Status: []string{"active"}})
+	require.NoError(t, err)
+	require.Len(t, offers, 1)
+	require.Equal(t, 2, offers[0].ID)
+
+	_, err = client.AdminOffer.DeleteOffer(ctx, &affise.AdminOfferDeleteOfferOpts{OfferID: []int{1, 3}})
+	require.NoError(t, err)
+	require.Len(t, backend.Offers(), 1)
+
+	_, _, err = client.Offer.Get(ctx, 1)
+	require.True(t, errors.Is(err, affise.ErrNotFound))
+
+	_, _, err = client.AdminOffer.CreateOffer(ctx, &affise.AdminOfferCreateOfferOpts{Advertiser: "1"})
+	var vErr *affise.ValidationError
+	require.True(t, errors.As(err, &vErr))
+	require.Equal(t, []string{"Title cannot be blank."}, vErr.FieldErrors["title"])
+	require.Equal(t, []string{"Url cannot be blank."}, vErr.FieldErrors["url"])
+}
+
+func TestBackend_OfferRules(t *testing.T) {
+	t.Parallel()
+
+	server := affisetest.NewServer(t, affisetest.WithBackend(affisetest.NewBackend()))
+	client := server.Client
+	ctx := context.Background()
+
+	payments := []affise.Payment{
+		{Countries: []string{"US", "CA"}, Cities: []affise.City{{ID: 5}}, Goal: "1", Total: 10, Revenue: 8, Currency: "usd", Type: "fixed"},
+		{Total: 5, Currency: "eur", Type: "fixed", CountryExclude: true},
+	}
+	targeting := []affise.TargetingGroup{{
+		CountryAllow: []string{"US"},
+		RegionAllow:  map[string][]int{"US": {33}},
+		OSAllow:      []affise.OS{{Name: "iOS", Comparison: "GTE", Version: "14"}},
+	}}
+
+	offer, _, err := client.AdminOffer.CreateOffer(ctx, &affise.AdminOfferCreateOfferOpts{
+		Title:      "Rules",
+		Advertiser: "5bc9d7c16d73e41c008b4567",
+		URL:        "https://example.com",
+		Sources:    []string{"s1"},
+		Payments:   payments,
+		Landings:   []affise.Landing{{Title: "Main", URL: "https://example.com/l", Type: "landing"}},
+		Caps:       []affise.Cap{{Period: "day", Type: "conversions", Value: "100", GoalType: "all"}},
+		Targeting:  targeting,
+	})
+	require.NoError(t, err)
+
+	offer, _, err = client.Offer.Get(ctx, offer.ID)
+	require.NoError(t, err)
+	require.Equal(t, payments[0].Countries, offer.Payments[0].Countries)
+	require.Equal(t, 5, offer.Payments[0].Cities[0].ID)
+	require.Equal(t, 10.0, offer.Payments[0].Total)
+	require.True(t, offer.Payments[1].CountryExclude)
+	require.Equal(t, "s1", offer.Sources[0].ID)
+	require.Equal(t, "Main", offer.Landings[0].Title)
+	require.Equal(t, "100", offer.Caps[0].Value.String())
+	require.Equal(t, targeting, offer.Targeting)
+
+	offer, _, err = client.AdminOffer.UpdateOffer(ctx, offer.ID, &affise.AdminOfferUpdateOfferOpts{
+		Payments: []affise.Payment{{Total: 12, Currency: "usd", Type: "fixed"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, offer.Payments, 1)
+	require.Equal(t, 12.0, offer.Payments[0].Total)
+	require.Len(t, offer.Landings, 1)
+}
+
+func TestBackend_Partners(t *testing.T) {
+	t.Parallel()
+
+	server := affisetest.NewServer(t, affisetest.WithBackend(affisetest.NewBackend()))
+	client := server.Client
+	ctx := context.Background()
+
+	partner, _, err := client.AdminAffiliate.Create(ctx, &affise.AdminAffiliateCreateOpts{
+		Email:    "john@example.com",
+		Password: "secret",
+		Country:  "US",
+		Login:    "John",
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), partner.ID)
+
+	_, _, err = client.AdminAffiliate.Create(ctx, &affise.AdminAffiliateCreateOpts{
+		Email:    "john@example.com",
+		Password: "secret",
+		Country:  "US",
+	})
+	var vErr *affise.ValidationError
+	require.True(t, errors.As(err, &vErr))
+	require.Equal(t, []string{"Email has already been taken."}, vErr.FieldErrors["email"])
+
+	_, _, err = client.AdminAffiliate.Update(ctx, partner.ID, &affise.AdminAffiliateUpdateOpts{Status: "banned"})
+	require.NoError(t, err)
+
+	partners, resp, err := client.AdminAffiliate.ListPartners(ctx, &affise.AdminAffiliateListPartnersOpts{})
+	require.NoError(t, err)
+	require.Len(t, partners, 1)
+	require.Equal(t, "john@example.com", partners[0].Email)
+	require.Equal(t, "banned", partners[0].Status)
+	require.Equal(t, 1, resp.Meta.Pagination.TotalCount)
+
+	partners, _, err = client.AdminAffiliate.ListPartners(ctx, &affise.AdminAffiliateListPartnersOpts{StatusPartner: "1"})
+	require.NoError(t, err)
+	require.Empty(t, partners)
+}
+
+func TestBackend_Conversions(t *testing.T) {
+	t.Parallel()
+
+	backend := affisetest.NewBackend()
+	server := affisetest.NewServer(t, affisetest.WithBackend(backend))
+	client := server.Client
+	ctx := context.Background()
+
+	offer := backend.AddOffer(affise.Offer{Title: "Seeded"})
+
+	_, _, err := client.AdminConversion.Import(ctx, &affise.AdminConversionImportOpts{
+		Offer:       offer.ID,
+		AffiliateID: 7,
+		ActionID:    "a-1",
+		Status:      "pending",
+	})
+	require.NoError(t, err)
+
+	list, _, err := client.AdminConversion.ImportList(ctx, &affise.AdminConversionImportListOpts{
+		List: []affise.AdminConversionImportOpts{
+			{Offer: offer.ID, AffiliateID: 7, ActionID: "a-2", Goal: 2},
+			{Offer: offer.ID, AffiliateID: 8, ActionID: "a-3"},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	conversions, resp, err := client.Statistic.Conversions(ctx, &affise.StatisticConversionsOpts{Partner: []int{7}})
+	require.NoError(t, err)
+	require.Len(t, conversions, 2)
+	require.Equal(t, 2, resp.Meta.Pagination.TotalCount)
+	require.Equal(t, "Seeded", conversions[0].Offer.Title)
+
+	conversions, _, err = client.Statistic.Conversions(ctx, &affise.StatisticConversionsOpts{Status: []int{2}})
+	require.NoError(t, err)
+	require.Len(t, conversions, 1)
+	require.Equal(t, "a-1", conversions[0].ActionID)
+
+	conversions, err = client.Statistic.ConversionsIter(ctx, &affise.StatisticConversionsOpts{Limit: 1}).All(0)
+	require.NoError(t, err)
+	require.Len(t, conversions, 3)
+	require.Equal(t, "2", conversions[1].Goal)
+
+	rows := make(chan affise.AdminConversionImportOpts)
+	go func() {
+		defer close(rows)
+		for i := 0; i < 5; i++ {
+			rows <- affise.AdminConversionImportOpts{Offer: offer.ID, AffiliateID: 9, ActionID: fmt.Sprintf("b-%d", i)}
+		}
+	}()
+	results, err := client.AdminConversion.BulkImport(ctx, rows, &affise.AdminConversionBulkImportOpts{ChunkSize: 2})
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+	for _, res := range results {
+		require.Equal(t, affise.ImportStatusImported, res.Status)
+	}
+
+	conversions, _, err = client.Statistic.Conversions(ctx, &affise.StatisticConversionsOpts{Partner: []int{9}})
+	require.NoError(t, err)
+	require.Len(t, conversions, 5)
+}
+
+func TestBackend_Postbacks(t *testing.T) {
+	t.Parallel()
+
+	backend := affisetest.NewBackend()
+	server := affisetest.NewServer(t, affisetest.WithBackend(backend))
+	client := server.Client
+	ctx := context.Background()
+
+	postback, _, err := client.AdminAffiliate.AddPostback(ctx, &affise.AdminAffiliateAddPostbackOpts{
+		AffiliateID: 7,
+		OfferID:     1,
+		URL:         "https://example.com/postback?click={clickid}",
+		Status:      "confirmed",
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), postback.AffiliateID)
+
+	_, _, err = client.Affiliate.CreatePostback(ctx, &affise.AffiliateCreatePostbackOpts{AffiliateID: 8, URL: "https://example.org"})
+	require.NoError(t, err)
+
+	postback, _, err = client.Affiliate.UpdatePostback(ctx, postback.ID, &affise.AffiliateUpdatePostbackOpts{URL: "https://example.com/v2"})
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/v2", postback.URL)
+	require.Equal(t, "confirmed", postback.Status)
+
+	postbacks, _, err := client.AdminAffiliate.ListPostbacks(ctx, &affise.AdminAffiliateListPostbacksOpts{AffiliateID: 7})
+	require.NoError(t, err)
+	require.Len(t, postbacks, 1)
+	require.Equal(t, "https://example.com/v2", postbacks[0].URL)
+
+	_, _, err = client.Affiliate.DeletePostback(ctx, postback.ID)
+	require.NoError(t, err)
+	_, _, err = client.Affiliate.DeletePostback(ctx, postback.ID)
+	require.True(t, errors.Is(err, affise.ErrNotFound))
+
+	_, err = client.AdminAffiliate.DeletePostbacksByAffiliates(ctx, &affise.AdminAffiliateDeletePostbacksByAffiliatesOpts{IDs: []int{8}})
+	require.NoError(t, err)
+	require.Empty(t, backend.Postbacks())
+}
+
+func TestBackend_Pixels(t *testing.T) {
+	t.Parallel()
+
+	server := affisetest.NewServer(t, affisetest.WithBackend(affisetest.NewBackend()))
+	client := server.Client
+	ctx := context.Background()
+
+	pixel, _, err := client.Affiliate.CreatePixel(ctx, &affise.AffiliateCreatePixelOpts{
+		OfferID:  1,
+		Name:     "Pixel",
+		Code:     "<img src=\"https://example.com/pixel.gif\">",
+		CodeType: "image",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "1", pixel.OfferID)
+
+	pixel, _, err = client.Affiliate.UpdatePixel(ctx, pixel.ID, &affise.AffiliateUpdatePixelOpts{Name: "Renamed"})
+	require.NoError(t, err)
+	require.Equal(t, "Renamed", pixel.Name)
+
+	pixels, _, err := client.Affiliate.ListPixels(ctx)
+	require.NoError(t, err)
+	require.Len(t, pixels, 1)
+	require.Equal(t, "Renamed", pixels[0].Name)
+
+	_, _, err = client.Affiliate.DeletePixel(ctx, pixel.ID)
+	require.NoError(t, err)
+
+	pixels, _, err = client.Affiliate.ListPixels(ctx)
+	require.NoError(t, err)
+	require.Empty(t, pixels)
+}
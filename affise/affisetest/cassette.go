@@ -0,0 +1,314 @@
+package affisetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/clobucks/go-sdk/affise"
+)
+
+// CassetteIndex is the file in the cassette directory describing recorded
+// exchanges. It is skipped when the directory is used for fixtures.
+const CassetteIndex = "_cassette.json"
+
+// ErrNotRecorded is returned by the replayer for requests without a recorded response.
+var ErrNotRecorded = errors.New("affisetest: response is not recorded")
+
+// DefaultScrubFields are personal data fields scrubbed from recorded
+// responses and queries in addition to credentials and IPs.
+var DefaultScrubFields = []string{"email", "phone", "address_1", "address_2", "contact_person", "contactPerson"}
+
+// A CassetteOption is used to configure WithRecorder and WithReplayer.
+type CassetteOption func(*cassette)
+
+// WithScrubFields replaces DefaultScrubFields. Values of JSON fields and query
+// parameters with these names are replaced with affise.Redacted.
+func WithScrubFields(fields ...string) CassetteOption {
+	return func(c *cassette) {
+		c.scrubFields = append([]string(nil), fields...)
+	}
+}
+
+// WithRecorder is a client option recording every API exchange into the
+// directory. Response bodies are saved in the fixture naming scheme, e.g.
+// "3.0.stats.getbydate@get.json"; requests to the same endpoint with another
+// query are saved as variants like "3.0.stats.getbydate@get.2.json". The
+// API-Key header, credentials and personal data are scrubbed.
+func WithRecorder(dir string, options ...CassetteOption) affise.ClientOption {
+	return func(client *affise.Client) error {
+		c, err := newCassette(dir, options)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("affisetest: create cassette dir err: %w", err)
+		}
+
+		return affise.WithMiddleware(c.record)(client)
+	}
+}
+
+// WithReplayer is a client option answering API requests with the exchanges
+// recorded by WithRecorder without sending them. Requests are matched on
+// method, path and the normalized query. Requests without a recorded
+// response fail with ErrNotRecorded.
+func WithReplayer(dir string, options ...CassetteOption) affise.ClientOption {
+	return func(client *affise.Client) error {
+		c, err := newCassette(dir, options)
+		if err != nil {
+			return err
+		}
+
+		return affise.WithMiddleware(c.replay)(client)
+	}
+}
+
+// Exchange is a recorded API exchange.
+type Exchange struct {
+	Method     string      `json:"method"`
+	Path       string      `json:"path"`
+	Query      string      `json:"query"`  // normalized and scrubbed query
+	Header     http.Header `json:"header"` // scrubbed request header
+	StatusCode int         `json:"status_code"`
+	File       string      `json:"file"` // name of the response body file
+}
+
+type cassette struct {
+	dir         string
+	scrubFields []string
+
+	mu        sync.Mutex
+	exchanges []*Exchange
+}
+
+// newCassette reads the cassette index. The returned cassette is usable even
+// if the index does not exist.
+func newCassette(dir string, options []CassetteOption) (*cassette, error) {
+	c := &cassette{dir: dir, scrubFields: DefaultScrubFields}
+	for _, option := range options {
+		option(c)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, CassetteIndex))
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(data, &c.exchanges); err != nil {
+		return c, fmt.Errorf("affisetest: read cassette index err: %w", err)
+	}
+
+	return c, nil
+}
+
+func (c *cassette) record(next affise.RoundTripFunc) affise.RoundTripFunc {
+	return func(r *http.Request) (*http.Response, error) {
+		resp, err := next(r)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := ioutil.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		if err := c.save(r, resp.StatusCode, body); err != nil {
+			return nil, fmt.Errorf("affisetest: record err: %w", err)
+		}
+
+		return resp, nil
+	}
+}
+
+func (c *cassette) save(r *http.Request, statusCode int, body []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	query := c.normalizeQuery(r.URL.Query())
+	e := c.find(r.Method, r.URL.Path, query)
+	if e == nil {
+		e = &Exchange{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  query,
+			File:   c.fileName(r.Method, r.URL.Path),
+		}
+		c.exchanges = append(c.exchanges, e)
+	}
+	e.Header = affise.RedactHeader(r.Header)
+	e.StatusCode = statusCode
+
+	if err := ioutil.WriteFile(filepath.Join(c.dir, e.File), c.scrubBody(body), 0o644); err != nil {
+		return err
+	}
+
+	index, err := json.MarshalIndent(c.exchanges, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(c.dir, CassetteIndex), append(index, '\n'), 0o644)
+}
+
+// fileName returns the fixture name for the endpoint not used by other exchanges.
+func (c *cassette) fileName(method, path string) string {
+	name := FixtureName(method, path)
+	n := 0
+	for _, e := range c.exchanges {
+		if e.Method == method && e.Path == path {
+			n++
+		}
+	}
+	if n == 0 {
+		return name
+	}
+
+	return fmt.Sprintf("%s.%d.json", strings.TrimSuffix(name, ".json"), n+1)
+}
+
+func (c *cassette) replay(affise.RoundTripFunc) affise.RoundTripFunc {
+	return func(r *http.Request) (*http.Response, error) {
+		query := c.normalizeQuery(r.URL.Query())
+
+		c.mu.Lock()
+		e := c.find(r.Method, r.URL.Path, query)
+		c.mu.Unlock()
+		if e == nil {
+			return nil, fmt.Errorf("%w: %s %s?%s", ErrNotRecorded, r.Method, r.URL.Path, query)
+		}
+
+		body, err := ioutil.ReadFile(filepath.Join(c.dir, e.File))
+		if err != nil {
+			return nil, fmt.Errorf("affisetest: replay err: %w", err)
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+			StatusCode:    e.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {"application/json"}},
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       r,
+		}, nil
+	}
+}
+
+func (c *cassette) find(method, path, query string) *Exchange {
+	for _, e := range c.exchanges {
+		if e.Method == method && e.Path == path && e.Query == query {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// normalizeQuery returns the scrubbed query encoded with sorted keys.
+func (c *cassette) normalizeQuery(q url.Values) string {
+	q = affise.RedactValues(q)
+	for k := range q {
+		if c.isScrubField(k) {
+			q[k] = []string{affise.Redacted}
+		}
+	}
+
+	return q.Encode()
+}
+
+// scrubBody redacts credentials and personal data in the JSON or form
+// encoded body. Numbers are kept as is. Other bodies are kept as is.
+func (c *cassette) scrubBody(body []byte) []byte {
+	if !json.Valid(body) {
+		if form, ok := parseForm(body); ok {
+			return []byte(c.normalizeQuery(form))
+		}
+
+		return body
+	}
+
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return body
+	}
+
+	data, err := json.Marshal(c.scrubValue(v))
+	if err != nil {
+		return body
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, affise.RedactJSON(data), "", "  "); err != nil {
+		return body
+	}
+	buf.WriteByte('\n')
+
+	return buf.Bytes()
+}
+
+// parseForm decodes a form encoded body like "email=a%40b.c&password=secret".
+func parseForm(body []byte) (url.Values, bool) {
+	s := string(body)
+	if !strings.Contains(s, "=") || strings.ContainsAny(s, " \t\r\n<>{}") {
+		return nil, false
+	}
+	form, err := url.ParseQuery(s)
+	if err != nil {
+		return nil, false
+	}
+
+	return form, true
+}
+
+func (c *cassette) scrubValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if c.isScrubField(k) && val != nil {
+				t[k] = affise.Redacted
+
+				continue
+			}
+			t[k] = c.scrubValue(val)
+		}
+	case []interface{}:
+		for i, val := range t {
+			t[i] = c.scrubValue(val)
+		}
+	}
+
+	return v
+}
+
+// isScrubField reports whether the key is one of the scrub fields. For
+// bracket parameters like list[0][email] the last key is checked.
+func (c *cassette) isScrubField(key string) bool {
+	key = strings.TrimSuffix(key, "]")
+	if i := strings.LastIndex(key, "["); i >= 0 {
+		key = key[i+1:]
+	}
+
+	for _, f := range c.scrubFields {
+		if strings.EqualFold(f, key) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,128 @@
+package affisetest_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/clobucks/go-sdk/affise"
+	"github.com/clobucks/go-sdk/affise/affisetest"
+)
+
+func TestCassette(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	recorder := affisetest.NewServer(t, affisetest.WithClientOptions(affisetest.WithRecorder(dir)))
+	recorded, _, err := recorder.Client.AdminAffiliate.ListPartners(ctx, &affise.AdminAffiliateListPartnersOpts{Page: 1})
+	require.NoError(t, err)
+	require.NotEmpty(t, recorded)
+	_, _, err = recorder.Client.AdminAffiliate.ListPartners(ctx, &affise.AdminAffiliateListPartnersOpts{Page: 2})
+	require.NoError(t, err)
+	_, _, err = recorder.Client.Offer.Get(ctx, 42)
+	require.NoError(t, err)
+
+	t.Run("Files", func(t *testing.T) {
+		for _, name := range []string{"3.0.admin.partners@get.json", "3.0.admin.partners@get.2.json", "3.0.offer.42@get.json"} {
+			_, err := ioutil.ReadFile(filepath.Join(dir, name))
+			require.NoError(t, err, name)
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, affisetest.CassetteIndex))
+		require.NoError(t, err)
+
+		var exchanges []affisetest.Exchange
+		require.NoError(t, json.Unmarshal(data, &exchanges))
+		require.Len(t, exchanges, 3)
+		require.Equal(t, "page=1", exchanges[0].Query)
+		require.Equal(t, affise.Redacted, exchanges[0].Header.Get("API-Key"))
+	})
+
+	t.Run("Scrub", func(t *testing.T) {
+		data, err := ioutil.ReadFile(filepath.Join(dir, "3.0.admin.partners@get.json"))
+		require.NoError(t, err)
+
+		var body struct {
+			Partners []map[string]interface{} `json:"partners"`
+		}
+		require.NoError(t, json.Unmarshal(data, &body))
+		require.NotEmpty(t, body.Partners)
+		for _, p := range body.Partners {
+			require.Equal(t, affise.Redacted, p["email"])
+			require.Equal(t, affise.Redacted, p["api_key"])
+		}
+	})
+
+	t.Run("Replay", func(t *testing.T) {
+		client, err := affise.NewClient(
+			affise.WithBaseURL("http://127.0.0.1:1"),
+			affise.WithAdminURL("http://127.0.0.1:1"),
+			affise.WithAPIKey("another"),
+			affisetest.WithReplayer(dir),
+		)
+		require.NoError(t, err)
+
+		partners, resp, err := client.AdminAffiliate.ListPartners(ctx, &affise.AdminAffiliateListPartnersOpts{Page: 1})
+		require.NoError(t, err)
+		require.Equal(t, 200, resp.StatusCode)
+		require.Len(t, partners, len(recorded))
+		require.Equal(t, recorded[0].ID, partners[0].ID)
+		require.Equal(t, affise.Redacted, partners[0].Email)
+
+		offer, _, err := client.Offer.Get(ctx, 42)
+		require.NoError(t, err)
+		require.NotNil(t, offer)
+
+		_, _, err = client.AdminAffiliate.ListPartners(ctx, &affise.AdminAffiliateListPartnersOpts{Page: 3})
+		require.True(t, errors.Is(err, affisetest.ErrNotRecorded))
+	})
+
+	t.Run("MissingCassette", func(t *testing.T) {
+		_, err := affise.NewClient(affisetest.WithReplayer(filepath.Join(dir, "missing")))
+		require.Error(t, err)
+	})
+}
+
+func TestCassette_ScrubBodies(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	recorder := affisetest.NewServer(t, affisetest.WithClientOptions(affisetest.WithRecorder(dir)))
+	recorder.HandleFunc(http.MethodGet, "/3.0/offer/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":1,"offer":{"id":12345678901234567,"payout":1.10,"email":"a@example.com","api_key":"key"}}`))
+	})
+	recorder.HandleFunc(http.MethodGet, "/3.0/admin/partner/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("id=1&email=a%40example.com&password=secret"))
+	})
+
+	_, _, err := recorder.Client.Offer.Get(ctx, 1)
+	require.NoError(t, err)
+	req, err := recorder.Client.NewRequest(ctx, http.MethodGet, "/3.0/admin/partner/1", nil, true)
+	require.NoError(t, err)
+	_, _ = recorder.Client.Do(req, nil)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "3.0.offer.1@get.json"))
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"id": 12345678901234567`)
+	require.Contains(t, string(data), `"payout": 1.10`)
+	require.NotContains(t, string(data), "a@example.com")
+	require.NotContains(t, string(data), `"key"`)
+
+	data, err = ioutil.ReadFile(filepath.Join(dir, "3.0.admin.partner.1@get.json"))
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "secret")
+	require.NotContains(t, string(data), "example.com")
+	require.Contains(t, string(data), "id=1")
+}
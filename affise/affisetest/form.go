@@ -0,0 +1,208 @@
+package affisetest
+
+import (
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// formNode is a parameter tree of the bracket notation, e.g. for
+// payments[0][countries][]=US the node "0" has the child "countries" with
+// the values [US].
+type formNode struct {
+	values   []string
+	children map[string]*formNode
+}
+
+func (n *formNode) child(key string) *formNode {
+	if n.children == nil {
+		n.children = make(map[string]*formNode)
+	}
+	c, ok := n.children[key]
+	if !ok {
+		c = &formNode{}
+		n.children[key] = c
+	}
+
+	return c
+}
+
+// decodeForm decodes parameters sent with the bracket notation under the
+// alias into dst, the way the API replaces a list. Field names are taken
+// from schema or json tags. dst is not changed if there are no parameters.
+func decodeForm(q url.Values, alias string, dst interface{}) {
+	root := &formNode{}
+	found := false
+	for k, v := range q {
+		if k != alias && !strings.HasPrefix(k, alias+"[") {
+			continue
+		}
+		found = true
+
+		n := root
+		for _, seg := range splitBrackets(k[len(alias):]) {
+			if seg == "" {
+				break
+			}
+			n = n.child(seg)
+		}
+		n.values = v
+	}
+	if !found {
+		return
+	}
+
+	v := reflect.ValueOf(dst).Elem()
+	v.Set(reflect.Zero(v.Type()))
+	fillForm(v, root)
+}
+
+// splitBrackets splits "[0][countries][]" into "0", "countries" and "".
+func splitBrackets(s string) []string {
+	var ret []string
+	for strings.HasPrefix(s, "[") {
+		i := strings.Index(s, "]")
+		if i < 0 {
+			break
+		}
+		ret = append(ret, s[1:i])
+		s = s[i+1:]
+	}
+
+	return ret
+}
+
+func fillForm(v reflect.Value, n *formNode) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		fillForm(v.Elem(), n)
+	case reflect.Interface:
+		if n.children == nil {
+			v.Set(reflect.ValueOf(append([]string(nil), n.values...)))
+
+			return
+		}
+		m := make(map[string]interface{}, len(n.children))
+		for k, c := range n.children {
+			if len(c.values) != 0 {
+				m[k] = c.values[0]
+			}
+		}
+		v.Set(reflect.ValueOf(m))
+	case reflect.Struct:
+		if n.children == nil {
+			// a struct sent as a single value like a city ID
+			if f := v.FieldByName("ID"); f.IsValid() && len(n.values) != 0 {
+				setFormScalar(f, n.values[0])
+			}
+
+			return
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			c := fieldNode(n, formFieldName(t.Field(i)))
+			if c != nil {
+				fillForm(v.Field(i), c)
+			}
+		}
+	case reflect.Slice:
+		if n.children == nil {
+			s := reflect.MakeSlice(v.Type(), 0, len(n.values))
+			for _, val := range n.values {
+				if val == "" {
+					continue
+				}
+				item := reflect.New(v.Type().Elem()).Elem()
+				fillForm(item, &formNode{values: []string{val}})
+				s = reflect.Append(s, item)
+			}
+			v.Set(s)
+
+			return
+		}
+		keys := make([]int, 0, len(n.children))
+		for k := range n.children {
+			if i, err := strconv.Atoi(k); err == nil {
+				keys = append(keys, i)
+			}
+		}
+		sort.Ints(keys)
+		s := reflect.MakeSlice(v.Type(), 0, len(keys))
+		for _, k := range keys {
+			item := reflect.New(v.Type().Elem()).Elem()
+			fillForm(item, n.children[strconv.Itoa(k)])
+			s = reflect.Append(s, item)
+		}
+		v.Set(s)
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		for k, c := range n.children {
+			item := reflect.New(v.Type().Elem()).Elem()
+			fillForm(item, c)
+			m.SetMapIndex(reflect.ValueOf(k).Convert(v.Type().Key()), item)
+		}
+		v.Set(m)
+	default:
+		if len(n.values) != 0 {
+			setFormScalar(v, n.values[0])
+		}
+	}
+}
+
+// fieldNode returns the node of the field name like "country[allow]".
+func fieldNode(n *formNode, name string) *formNode {
+	if name == "" {
+		return nil
+	}
+
+	i := strings.Index(name, "[")
+	if i < 0 {
+		return n.children[name]
+	}
+	for _, seg := range append([]string{name[:i]}, splitBrackets(name[i:])...) {
+		if n = n.children[seg]; n == nil {
+			return nil
+		}
+	}
+
+	return n
+}
+
+func formFieldName(f reflect.StructField) string {
+	if f.PkgPath != "" {
+		return ""
+	}
+
+	for _, tag := range []string{"schema", "json"} {
+		name := strings.Split(f.Tag.Get(tag), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+
+	return f.Name
+}
+
+func setFormScalar(v reflect.Value, s string) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		v.SetBool(s == "1" || s == "true")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, _ := strconv.ParseInt(s, 10, 64)
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, _ := strconv.ParseUint(s, 10, 64)
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, _ := strconv.ParseFloat(s, 64)
+		v.SetFloat(n)
+	}
+}
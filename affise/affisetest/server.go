@@ -0,0 +1,407 @@
+// Package affisetest provides a fake Affise API server for tests.
+//
+// By default the server answers every endpoint with the fixtures from the
+// test/testdata directory of this module. Fixture names follow the scheme
+// "{version}.{path segments}@{method}.json", e.g. "3.0.stats.getbydate@get.json"
+// is served for GET /3.0/stats/getbydate and "3.0.offer.{id}@get.json" for
+// GET /3.0/offer/{id} with any id.
+package affisetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/clobucks/go-sdk/affise"
+)
+
+// Request is a request received by the server.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// An Option is used to configure a Server.
+type Option func(*Server)
+
+// WithFixtureDir sets the directory to load fixtures from.
+func WithFixtureDir(dir string) Option {
+	return func(s *Server) {
+		s.fixtureDir = dir
+	}
+}
+
+// WithoutFixtures disables the default fixture routes.
+func WithoutFixtures() Option {
+	return func(s *Server) {
+		s.fixtureDir = ""
+	}
+}
+
+// WithClientOptions adds options used to create Server.Client.
+func WithClientOptions(options ...affise.ClientOption) Option {
+	return func(s *Server) {
+		s.clientOptions = append(s.clientOptions, options...)
+	}
+}
+
+// WithBackend serves the endpoints of the stateful backend b. Its routes take
+// precedence over fixtures, handlers registered with Handle take precedence over them.
+func WithBackend(b *Backend) Option {
+	return func(s *Server) {
+		s.backend = append(s.backend, b.routes()...)
+	}
+}
+
+// Server is a fake Affise API server.
+type Server struct {
+	*httptest.Server
+	Client *affise.Client // Client configured to use the server for both base and admin URLs
+
+	tb            testing.TB
+	fixtureDir    string
+	clientOptions []affise.ClientOption
+
+	mu        sync.Mutex
+	overrides []*route
+	backend   []*route
+	fixtures  []*route
+	requests  []Request
+}
+
+// NewServer starts a server and closes it when the test finishes.
+func NewServer(tb testing.TB, options ...Option) *Server {
+	tb.Helper()
+
+	s := &Server{tb: tb, fixtureDir: DefaultFixtureDir()}
+	for _, option := range options {
+		option(s)
+	}
+
+	if s.fixtureDir != "" {
+		fixtures, err := loadFixtures(s.fixtureDir)
+		if err != nil {
+			tb.Fatalf("affisetest: load fixtures err: %v", err)
+		}
+		s.fixtures = fixtures
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	tb.Cleanup(s.Close)
+
+	clientOptions := append([]affise.ClientOption{
+		affise.WithBaseURL(s.URL),
+		affise.WithAdminURL(s.URL),
+		affise.WithAPIKey("test"),
+	}, s.clientOptions...)
+
+	client, err := affise.NewClient(clientOptions...)
+	if err != nil {
+		tb.Fatalf("affisetest: create client err: %v", err)
+	}
+	s.Client = client
+
+	return s
+}
+
+// FixtureDirEnv is the environment variable overriding DefaultFixtureDir.
+const FixtureDirEnv = "AFFISETEST_FIXTURE_DIR"
+
+// DefaultFixtureDir returns the directory set with the AFFISETEST_FIXTURE_DIR
+// environment variable or the test/testdata directory of this module. The
+// module directory is found from the source path or, when it is not available
+// like in builds with -trimpath, from the working directory and its parents.
+// It is empty when the fixtures are not found.
+func DefaultFixtureDir() string {
+	if dir := os.Getenv(FixtureDirEnv); dir != "" {
+		return dir
+	}
+
+	if _, file, _, ok := runtime.Caller(0); ok && filepath.IsAbs(file) {
+		dir := filepath.Join(filepath.Dir(file), "..", "..", "test", "testdata")
+		if isDir(dir) {
+			return dir
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	for {
+		dir := filepath.Join(wd, "test", "testdata")
+		if isDir(dir) {
+			return dir
+		}
+		parent := filepath.Dir(wd)
+		if parent == wd {
+			return ""
+		}
+		wd = parent
+	}
+}
+
+func isDir(path string) bool {
+	fi, err := os.Stat(path)
+
+	return err == nil && fi.IsDir()
+}
+
+// Handle registers the handler for the method and path pattern. Patterns may
+// contain "{name}" segments matching any value. Handlers registered later
+// take precedence over earlier ones and over fixtures.
+func (s *Server) Handle(method, pattern string, handler http.Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.overrides = append([]*route{newRoute(method, pattern, handler)}, s.overrides...)
+}
+
+// HandleFunc registers the handler function for the method and path pattern.
+func (s *Server) HandleFunc(method, pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	s.Handle(method, pattern, http.HandlerFunc(handler))
+}
+
+// HandleJSON registers a handler responding with v encoded as JSON. It fails
+// the test if v cannot be encoded.
+func (s *Server) HandleJSON(method, pattern string, statusCode int, v interface{}) {
+	s.tb.Helper()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		s.tb.Fatalf("affisetest: json.Marshal err: %v", err)
+
+		return
+	}
+
+	s.Handle(method, pattern, jsonHandler(statusCode, data))
+}
+
+// HandleFixture registers a handler responding with the fixture file from the
+// fixture directory. It fails the test if the fixture cannot be read.
+func (s *Server) HandleFixture(method, pattern, fixture string, statusCode int) {
+	s.tb.Helper()
+
+	data, err := ioutil.ReadFile(filepath.Join(s.fixtureDir, fixture))
+	if err != nil {
+		s.tb.Fatalf("affisetest: read fixture err: %v", err)
+
+		return
+	}
+
+	s.Handle(method, pattern, jsonHandler(statusCode, data))
+}
+
+// Requests returns all requests received by the server.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// LastRequest returns the last received request. It returns false if there are no requests.
+func (s *Server) LastRequest() (Request, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.requests) == 0 {
+		return Request{}, false
+	}
+
+	return s.requests[len(s.requests)-1], true
+}
+
+// Reset forgets received requests and removes all overrides.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = nil
+	s.overrides = nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	_ = r.Body.Close()
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	handler := s.match(r)
+	s.mu.Unlock()
+
+	if handler == nil {
+		NotFound(w, fmt.Sprintf("%s %s is not found", r.Method, r.URL.Path))
+
+		return
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+func (s *Server) match(r *http.Request) http.Handler {
+	for _, routes := range [][]*route{s.overrides, s.backend, s.fixtures} {
+		for _, rt := range routes {
+			if rt.match(r.Method, r.URL.Path) {
+				return rt.handler
+			}
+		}
+	}
+
+	return nil
+}
+
+// WriteJSON writes v as a JSON response.
+func WriteJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// NotFound writes an API error response with 404 status code.
+func NotFound(w http.ResponseWriter, message string) {
+	WriteJSON(w, http.StatusNotFound, map[string]interface{}{
+		"status":  2,
+		"message": message,
+	})
+}
+
+func jsonHandler(statusCode int, data []byte) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		_, _ = w.Write(data)
+	})
+}
+
+type route struct {
+	method   string
+	segments []string
+	handler  http.Handler
+}
+
+func newRoute(method, pattern string, handler http.Handler) *route {
+	return &route{
+		method:   strings.ToUpper(method),
+		segments: strings.Split(strings.Trim(pattern, "/"), "/"),
+		handler:  handler,
+	}
+}
+
+func (rt *route) match(method, path string) bool {
+	if rt.method != method {
+		return false
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) != len(rt.segments) {
+		return false
+	}
+
+	for i, seg := range rt.segments {
+		if !isParam(seg) && seg != segments[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// literals returns the number of non-parameter segments.
+func (rt *route) literals() int {
+	n := 0
+	for _, seg := range rt.segments {
+		if !isParam(seg) {
+			n++
+		}
+	}
+
+	return n
+}
+
+func isParam(seg string) bool {
+	return strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}")
+}
+
+// loadFixtures creates routes for fixtures like "3.0.offer.{id}@get.json".
+// Files starting with "_" and variants like "3.0.admin.offer@post.validation.json" are skipped.
+func loadFixtures(dir string) ([]*route, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*@*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	routes := make([]*route, 0, len(files))
+	for _, file := range files {
+		method, pattern, ok := FixtureRoute(filepath.Base(file))
+		if !ok {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		routes = append(routes, newRoute(method, pattern, jsonHandler(http.StatusOK, data)))
+	}
+
+	// more specific routes first
+	sort.SliceStable(routes, func(i, j int) bool {
+		return routes[i].literals() > routes[j].literals()
+	})
+
+	return routes, nil
+}
+
+// FixtureRoute returns the method and path pattern served by the fixture file name.
+func FixtureRoute(name string) (method, pattern string, ok bool) {
+	if strings.HasPrefix(name, "_") || !strings.HasSuffix(name, ".json") {
+		return "", "", false
+	}
+
+	i := strings.LastIndex(name, "@")
+	if i < 0 {
+		return "", "", false
+	}
+	method = strings.TrimSuffix(name[i+1:], ".json")
+	if strings.Contains(method, ".") {
+		return "", "", false
+	}
+
+	// the version like "3.0" is the first path segment
+	parts := strings.Split(name[:i], ".")
+	if len(parts) < 3 {
+		return "", "", false
+	}
+	segments := append([]string{parts[0] + "." + parts[1]}, parts[2:]...)
+
+	return strings.ToUpper(method), "/" + strings.Join(segments, "/"), true
+}
+
+// FixtureName returns the fixture file name for the method and path,
+// e.g. "3.0.stats.getbydate@get.json" for GET /3.0/stats/getbydate.
+func FixtureName(method, path string) string {
+	return strings.ReplaceAll(strings.Trim(path, "/"), "/", ".") + "@" + strings.ToLower(method) + ".json"
+}
@@ -0,0 +1,171 @@
+package affisetest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/clobucks/go-sdk/affise"
+	"github.com/clobucks/go-sdk/affise/affisetest"
+)
+
+func TestServer_Fixtures(t *testing.T) {
+	t.Parallel()
+
+	server := affisetest.NewServer(t)
+	files, err := filepath.Glob(filepath.Join(affisetest.DefaultFixtureDir(), "*@*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	param := regexp.MustCompile(`\{[^/]+\}`)
+	for _, file := range files {
+		method, pattern, ok := affisetest.FixtureRoute(filepath.Base(file))
+		if !ok {
+			continue
+		}
+
+		req, err := http.NewRequest(method, server.URL+param.ReplaceAllString(pattern, "1"), nil)
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode, file)
+	}
+}
+
+func TestServer_Client(t *testing.T) {
+	t.Parallel()
+
+	server := affisetest.NewServer(t)
+	ctx := context.Background()
+
+	stats, _, err := server.Client.Statistic.GetByDate(ctx, &affise.StatisticGetByDateOpts{
+		StatFilter: affise.StatFilter{DateFrom: "2021-01-01", DateTo: "2021-01-02"},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, stats)
+
+	offer, _, err := server.Client.Offer.Get(ctx, 42)
+	require.NoError(t, err)
+	require.NotNil(t, offer)
+
+	req, ok := server.LastRequest()
+	require.True(t, ok)
+	require.Equal(t, http.MethodGet, req.Method)
+	require.Equal(t, "/3.0/offer/42", req.Path)
+	require.Equal(t, "test", req.Header.Get("API-Key"))
+
+	requests := server.Requests()
+	require.Len(t, requests, 2)
+	require.Equal(t, "2021-01-01", requests[0].Query.Get("filter[date_from]"))
+
+	devices, _, err := server.Client.AdminOther.ListDevices(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, devices)
+
+	server.Reset()
+	require.Empty(t, server.Requests())
+}
+
+func TestServer_Override(t *testing.T) {
+	t.Parallel()
+
+	server := affisetest.NewServer(t)
+	ctx := context.Background()
+
+	server.HandleJSON(http.MethodGet, "/3.0/offer/{id}", http.StatusOK, map[string]interface{}{
+		"status": 1,
+		"offer":  map[string]interface{}{"id": 7, "title": "Overridden"},
+	})
+
+	offer, _, err := server.Client.Offer.Get(ctx, 7)
+	require.NoError(t, err)
+	require.Equal(t, "Overridden", offer.Title)
+
+	server.HandleFixture(http.MethodPost, "/3.0/admin/partner", "3.0.admin.partner@post.validation.json", http.StatusOK)
+	_, _, err = server.Client.AdminAffiliate.Create(ctx, &affise.AdminAffiliateCreateOpts{Email: "john@example"})
+	require.True(t, errors.Is(err, affise.ErrValidation))
+
+	req, ok := server.LastRequest()
+	require.True(t, ok)
+	require.Equal(t, "john@example", req.Query.Get("email"))
+
+	server.Reset()
+	offer, _, err = server.Client.Offer.Get(ctx, 7)
+	require.NoError(t, err)
+	require.NotEqual(t, "Overridden", offer.Title)
+}
+
+func TestServer_NotFound(t *testing.T) {
+	t.Parallel()
+
+	server := affisetest.NewServer(t, affisetest.WithoutFixtures())
+
+	_, _, err := server.Client.Offer.Get(context.Background(), 1)
+	require.True(t, errors.Is(err, affise.ErrNotFound))
+}
+
+// fatalTB records Fatalf calls instead of stopping the test.
+type fatalTB struct {
+	testing.TB
+	fatal string
+}
+
+func (tb *fatalTB) Fatalf(format string, args ...interface{}) {
+	tb.fatal = fmt.Sprintf(format, args...)
+}
+
+func TestServer_HandleErrors(t *testing.T) {
+	t.Parallel()
+
+	tb := &fatalTB{TB: t}
+	server := affisetest.NewServer(tb)
+
+	server.HandleJSON(http.MethodGet, "/3.0/offer/{id}", http.StatusOK, func() {})
+	require.Contains(t, tb.fatal, "json.Marshal err")
+
+	tb.fatal = ""
+	server.HandleFixture(http.MethodGet, "/3.0/offer/{id}", "missing.json", http.StatusOK)
+	require.Contains(t, tb.fatal, "read fixture err")
+}
+
+func TestDefaultFixtureDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Setenv(affisetest.FixtureDirEnv, dir))
+	defer os.Unsetenv(affisetest.FixtureDirEnv)
+
+	require.Equal(t, dir, affisetest.DefaultFixtureDir())
+}
+
+func TestFixtureRoute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		method  string
+		pattern string
+		ok      bool
+	}{
+		{"3.0.stats.getbydate@get.json", http.MethodGet, "/3.0/stats/getbydate", true},
+		{"3.1.presets.{preset_id}@delete.json", http.MethodDelete, "/3.1/presets/{preset_id}", true},
+		{"3.0.admin.offer@post.validation.json", "", "", false},
+		{"_permissions.json", "", "", false},
+	}
+
+	for _, tt := range tests {
+		method, pattern, ok := affisetest.FixtureRoute(tt.name)
+		require.Equal(t, tt.ok, ok, tt.name)
+		require.Equal(t, tt.method, method, tt.name)
+		require.Equal(t, tt.pattern, pattern, tt.name)
+	}
+
+	require.Equal(t, "3.0.offer.42@get.json", affisetest.FixtureName(http.MethodGet, "/3.0/offer/42"))
+}
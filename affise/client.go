@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 )
 
 const (
@@ -22,11 +23,19 @@ const (
 )
 
 type Client struct {
-	httpClient *http.Client
-	BaseURL    *url.URL
-	AdminURL   *url.URL
-	APIKey     string
-	UserAgent  string
+	httpClient   *http.Client
+	retryPolicy  RetryPolicy
+	baseLimiter  *limiter
+	adminLimiter *limiter
+	middlewares  []Middleware
+	roundTrip    RoundTripFunc
+	logger       Logger
+	tracer       Tracer
+	observers    []Observer
+	BaseURL      *url.URL
+	AdminURL     *url.URL
+	APIKey       string
+	UserAgent    string
 
 	// Services used for communicating with the API
 	AdminAdvertiser        *AdminAdvertiserService
@@ -89,6 +98,75 @@ func WithHTTPClient(httpClient *http.Client) ClientOption {
 	}
 }
 
+// WithRetryPolicy configures a Client to retry failed requests according to the policy.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(client *Client) error {
+		client.retryPolicy = policy
+
+		return nil
+	}
+}
+
+// WithRateLimit configures a Client to limit the rate of requests. The base and
+// admin budgets are independent and shared by all services of the Client.
+func WithRateLimit(base, admin RateLimit) ClientOption {
+	return func(client *Client) error {
+		var err error
+		if client.baseLimiter, err = newLimiter(base); err != nil {
+			return fmt.Errorf("base rate limit err: %w", err)
+		}
+		if client.adminLimiter, err = newLimiter(admin); err != nil {
+			return fmt.Errorf("admin rate limit err: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// RoundTripFunc sends an HTTP request and returns the HTTP response.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps a RoundTripFunc with additional behaviour.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// WithMiddleware configures a Client to pass every attempt of a request
+// through the middlewares. The first middleware is the outermost one.
+func WithMiddleware(middlewares ...Middleware) ClientOption {
+	return func(client *Client) error {
+		client.middlewares = append(client.middlewares, middlewares...)
+
+		return nil
+	}
+}
+
+// WithLogger configures a Client to log every API call. Credentials and
+// personal data such as passwords, API keys and IPs are redacted.
+func WithLogger(logger Logger) ClientOption {
+	return func(client *Client) error {
+		client.logger = logger
+
+		return nil
+	}
+}
+
+// WithTracer configures a Client to trace every API call.
+func WithTracer(tracer Tracer) ClientOption {
+	return func(client *Client) error {
+		client.tracer = tracer
+
+		return nil
+	}
+}
+
+// WithObserver configures a Client to notify the observers about every API call.
+func WithObserver(observers ...Observer) ClientOption {
+	return func(client *Client) error {
+		client.observers = append(client.observers, observers...)
+
+		return nil
+	}
+}
+
 // NewClient creates a new client.
 func NewClient(options ...ClientOption) (*Client, error) {
 	client := &Client{
@@ -102,6 +180,11 @@ func NewClient(options ...ClientOption) (*Client, error) {
 		}
 	}
 
+	client.roundTrip = client.httpClient.Do
+	for i := len(client.middlewares) - 1; i >= 0; i-- {
+		client.roundTrip = client.middlewares[i](client.roundTrip)
+	}
+
 	client.AdminAdvertiser = &AdminAdvertiserService{client: client}
 	client.AdminAdvertiserBilling = &AdminAdvertiserBillingService{client: client}
 	client.AdminConversion = &AdminConversionService{client: client}
@@ -158,6 +241,7 @@ func (c *Client) NewRequest(ctx context.Context,
 		req.Header.Set("Content-Type", "application/json")
 	}
 
+	ctx = context.WithValue(ctx, adminRequestKey{}, isAdmin)
 	req = req.WithContext(ctx)
 
 	return req, nil
@@ -179,92 +263,194 @@ func (c *Client) NewRequestOpts(ctx context.Context,
 
 	urlStr = fmt.Sprintf("%s?%s", urlStr, val.Encode())
 
-	return c.NewRequest(ctx, method, urlStr, body, isAdmin)
+	// files are sent as multipart/form-data, other options stay in the query
+	var mb *multipartBody
+	if f, ok := opts.(filer); ok && body == nil {
+		if files := f.files(); len(files) != 0 {
+			mb = newMultipartBody(files)
+			body = mb
+		}
+	}
+
+	req, err := c.NewRequest(ctx, method, urlStr, body, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	if mb != nil {
+		req.Header.Set("Content-Type", mb.contentType())
+		req.GetBody = mb.getBody()
+	}
+
+	return req, nil
 }
 
 // Do performs an HTTP request against the API.
 func (c *Client) Do(r *http.Request, v interface{}) (*Response, error) {
-	resp, err := c.httpClient.Do(r)
+	start := time.Now()
+	r, span := c.startSpan(r)
+	response, size, err := c.do(r, v)
+
+	cl := newCall(r, response, size, time.Since(start), err)
+	endSpan(span, cl)
+	c.log(r.Context(), cl)
+	c.observe(r.Context(), cl)
+
+	return response, err
+}
+
+// do performs an HTTP request and returns the response with the size of its body.
+func (c *Client) do(r *http.Request, v interface{}) (*Response, int, error) {
+	response := &Response{}
+	resp, err := c.send(r, response)
 	if err != nil {
-		return nil, fmt.Errorf("httpClient.Do err: %w", err)
+		return nil, 0, fmt.Errorf("httpClient.Do err: %w", err)
 	}
-	response := &Response{Response: resp}
+	response.Response = resp
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		_ = resp.Body.Close()
 
-		return response, fmt.Errorf("ioutil.ReadAll err: %w", err)
+		return response, len(body), fmt.Errorf("ioutil.ReadAll err: %w", err)
 	}
 	_ = resp.Body.Close()
 	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
 
 	if err := response.readMeta(body); err != nil {
-		return response, fmt.Errorf("read response meta data err: %w", err)
+		return response, len(body), fmt.Errorf("read response meta data err: %w", err)
 	}
 
 	if err := c.checkResponse(response); err != nil {
-		return response, err
+		return response, len(body), err
 	}
 
 	if v == nil {
-		return response, nil
+		return response, len(body), nil
 	}
 
 	if w, ok := v.(io.Writer); ok {
 		if _, err := io.Copy(w, bytes.NewReader(body)); err != nil {
-			return response, fmt.Errorf("io.Copy err: %w", err)
+			return response, len(body), fmt.Errorf("io.Copy err: %w", err)
 		}
 	} else if err := json.Unmarshal(body, v); err != nil {
-		return response, fmt.Errorf("json.Unmarshal err: %w", err)
+		return response, len(body), fmt.Errorf("json.Unmarshal err: %w", err)
 	}
 
-	return response, nil
+	return response, len(body), nil
 }
 
-func (c *Client) checkResponse(r *Response) error {
-	if (r.StatusCode >= 400 && r.StatusCode <= 599) || r.Meta.Status != 1 {
-		return responseErr(r)
-	}
+// send performs the request, waiting for the rate limiter and retrying it
+// according to the retry policy. The number of attempts and the time spent
+// in the rate limiter are recorded to response.
+func (c *Client) send(r *http.Request, response *Response) (*http.Response, error) {
+	ctx := r.Context()
+	l := c.limiterFor(ctx)
+	req := r
+	for attempt := 1; ; attempt++ {
+		response.Attempts = attempt
+
+		wait, err := l.wait(ctx)
+		response.RateLimitWait += wait
+		if err != nil {
+			return nil, err
+		}
 
-	return nil
-}
+		resp, err := c.roundTrip(req)
+		if c.retryPolicy == nil {
+			return resp, err
+		}
+
+		// a body without GetBody can't be sent twice
+		if r.Body != nil && r.Body != http.NoBody && r.GetBody == nil {
+			return resp, err
+		}
+
+		policyErr := err
+		if err == nil {
+			policyErr = metaErr(resp)
+		}
+
+		delay, ok := c.retryPolicy.Retry(req, resp, policyErr, attempt)
+		if !ok {
+			return resp, err
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, err
+		}
 
-type ResponseErr struct {
-	Method      string
-	URL         *url.URL
-	Status      string
-	MetaStatus  int
-	MetaMessage string
+		if resp != nil {
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		req = r.Clone(ctx)
+		if r.GetBody != nil {
+			body, err := r.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
 }
 
-func responseErr(r *Response) *ResponseErr {
-	return &ResponseErr{
-		Method:      r.Request.Method,
-		URL:         r.Request.URL,
-		Status:      r.Status,
-		MetaStatus:  r.Meta.Status,
-		MetaMessage: r.Meta.Message,
+// metaErr returns the meta of a successful JSON response as a *ResponseErr
+// if its status isn't 1, so that the retry policy can see it. The body is
+// left readable for Do.
+func metaErr(resp *http.Response) error {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 ||
+		!strings.Contains(resp.Header.Get("Content-Type"), "application/json") {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("ioutil.ReadAll err: %w", err)
+	}
+
+	r := &Response{Response: resp}
+	if err := r.readMeta(body); err != nil || r.Meta.Status == 1 {
+		return nil
 	}
+
+	return newResponseErr(r)
 }
 
-// Error implements error interface.
-func (r *ResponseErr) Error() string {
-	return fmt.Sprintf("%s %v %s (status %d) err: %s", r.Method, r.URL, r.Status, r.MetaStatus, r.MetaMessage)
+func (c *Client) checkResponse(r *Response) error {
+	if (r.StatusCode >= 400 && r.StatusCode <= 599) || r.Meta.Status != 1 {
+		return newResponseErr(r)
+	}
+
+	return nil
 }
 
 // Meta represents meta information included in an API response.
 type Meta struct {
-	Status     int         `json:"status"`
-	Message    string      `json:"message"`
-	Pagination *Pagination `json:"pagination,omitempty"`
+	Status      int                 `json:"status"`
+	Message     string              `json:"message"`
+	Pagination  *Pagination         `json:"pagination,omitempty"`
+	RawMessage  json.RawMessage     `json:"-"` // Message as received, including the object form
+	FieldErrors map[string][]string `json:"-"` // Field errors decoded from the object form of Message
 }
 
 // UnmarshalJSON implements json.Unmarshaller.
 func (m *Meta) UnmarshalJSON(data []byte) error {
 	type RawMeta struct {
-		Status     int         `json:"status"`
-		Message    interface{} `json:"message"`
-		Pagination *Pagination `json:"pagination,omitempty"`
+		Status     int             `json:"status"`
+		Message    json.RawMessage `json:"message"`
+		Pagination *Pagination     `json:"pagination,omitempty"`
 	}
 
 	raw := RawMeta{}
@@ -274,9 +460,13 @@ func (m *Meta) UnmarshalJSON(data []byte) error {
 
 	m.Status = raw.Status
 	m.Pagination = raw.Pagination
+	m.RawMessage = raw.Message
 	// some handles use this field as an object
-	if s, ok := raw.Message.(string); ok {
+	var s string
+	if err := json.Unmarshal(raw.Message, &s); err == nil {
 		m.Message = s
+	} else {
+		m.FieldErrors = parseFieldErrors(raw.Message)
 	}
 
 	return nil
@@ -293,7 +483,9 @@ type Pagination struct {
 // Response represents a response from the API. It embeds http.Response.
 type Response struct {
 	*http.Response
-	Meta Meta
+	Meta          Meta
+	Attempts      int           // Number of attempts made to get the response
+	RateLimitWait time.Duration // Time spent waiting for the client rate limiter
 }
 
 func (r *Response) readMeta(body []byte) error {
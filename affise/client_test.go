@@ -77,3 +77,41 @@ func (env *testEnv) testPermissions() *affise.Permissions {
 
 	return &affise.Permissions{data}
 }
+
+func TestClient_WithMiddleware(t *testing.T) {
+	t.Parallel()
+
+	env := newTestEnv(t)
+	defer env.teardown()
+
+	var order []string
+	env.Mux.HandleFunc("/3.0/admin/users", func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "server")
+		require.Equal(t, "42", r.Header.Get("X-Request-ID"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":1,"users":[]}`))
+	})
+
+	middleware := func(name string) affise.Middleware {
+		return func(next affise.RoundTripFunc) affise.RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				req.Header.Set("X-Request-ID", "42")
+
+				return next(req)
+			}
+		}
+	}
+
+	client, err := affise.NewClient(
+		affise.WithAdminURL(env.Server.URL),
+		affise.WithMiddleware(middleware("first"), middleware("second")),
+		affise.WithMiddleware(middleware("third")),
+	)
+	require.NoError(t, err)
+
+	_, _, err = client.AdminUser.List(env.Ctx, &affise.AdminUserListOpts{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"first", "second", "third", "server"}, order)
+}
@@ -9,6 +9,8 @@ import (
 	"strings"
 
 	"github.com/gorilla/schema"
+
+	"github.com/clobucks/go-sdk/affise/internal/form"
 )
 
 var (
@@ -83,6 +85,33 @@ func (e *encoder) encodeMap(alias string, src map[string]string) (url.Values, er
 	return ret, nil
 }
 
+// nestedScalar is implemented by structs encoded as a single value with the
+// bracket notation, e.g. a City as its ID.
+type nestedScalar interface {
+	nestedValue() string
+}
+
+var nestedScalarType = reflect.TypeOf((*nestedScalar)(nil)).Elem()
+
+// encodeNested encodes src with the bracket notation of form.Encoder.
+func (e *encoder) encodeNested(alias string, src interface{}) url.Values {
+	return nestedEncoder.Encode(alias, src)
+}
+
+var nestedEncoder = form.Encoder{Scalar: nestedScalarValue}
+
+// nestedScalarValue returns the value of nestedScalar implementations.
+func nestedScalarValue(v reflect.Value) (string, bool) {
+	if !v.Type().Implements(nestedScalarType) {
+		return "", false
+	}
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return "", true
+	}
+
+	return v.Interface().(nestedScalar).nestedValue(), true
+}
+
 func commaSeparatedInts(src []int) string {
 	s := make([]string, 0, len(src))
 	for _, v := range src {
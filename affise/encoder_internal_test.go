@@ -53,6 +53,49 @@ func Test_encoder_encodeSliceErr(t *testing.T) {
 	}
 }
 
+func Test_encoder_encodeNested(t *testing.T) {
+	t.Parallel()
+
+	type item struct {
+		Name    string            `json:"name,omitempty"`
+		Count   int               `json:"count"`
+		Skipped string            `json:"-"`
+		Allow   []string          `schema:"list[allow],omitempty"`
+		Groups  map[string][]int  `schema:"groups"`
+		Flag    bool              `json:"flag"`
+		Rate    float64           `json:"rate"`
+		Any     interface{}       `json:"any"`
+		Labels  map[string]string `json:"labels"`
+	}
+
+	tests := []struct {
+		has  interface{}
+		want string
+	}{
+		{
+			[]item{{Name: "a", Count: 1, Skipped: "x", Allow: []string{"US", "DE"}, Flag: true, Rate: 0.5}},
+			"items[0][name]=a&items[0][count]=1&items[0][list][allow][]=US&items[0][list][allow][]=DE&items[0][flag]=1&items[0][rate]=0.5",
+		},
+		{
+			[]*item{{Groups: map[string][]int{"US": {33}}, Any: []string{"1"}, Labels: map[string]string{"k": "v"}}},
+			"items[0][count]=0&items[0][groups][US][]=33&items[0][flag]=0&items[0][rate]=0&items[0][any][]=1&items[0][labels][k]=v",
+		},
+		{[]item{{}}, "items[0][count]=0&items[0][flag]=0&items[0][rate]=0"},
+		{[]struct {
+			Flag  *bool `json:"flag,omitempty"`
+			Count int   `json:"count,omitempty"`
+		}{{Flag: new(bool)}}, "items[0][flag]=0"},
+		{[]map[string]string{{"sub1": "", "sub2": "b"}}, "items[0][sub1]=&items[0][sub2]=b"},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		want, err := url.ParseQuery(tt.want)
+		require.NoError(t, err)
+		require.Equal(t, want, defaultEncoder.encodeNested("items", tt.has))
+	}
+}
+
 func Test_commaSeparatedInts(t *testing.T) {
 	t.Parallel()
 
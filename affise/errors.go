@@ -0,0 +1,170 @@
+package affise
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Errors returned by the API can be matched with errors.Is.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrValidation   = errors.New("validation failed")
+)
+
+// ErrInvalidPostbackURL is returned by the postback methods before sending
+// URLs with macros Affise cannot fill, see the Validate methods of their options.
+var ErrInvalidPostbackURL = errors.New("postback: invalid template")
+
+// ResponseErr is returned when the API responds with an HTTP error or meta status other than 1.
+type ResponseErr struct {
+	Method      string
+	URL         *url.URL
+	Status      string
+	StatusCode  int
+	MetaStatus  int
+	MetaMessage string              // Meta message, or the summary of FieldErrors for the object form
+	RawMessage  json.RawMessage     // Meta message as received
+	FieldErrors map[string][]string // Error messages by field name
+}
+
+func newResponseErr(r *Response) error {
+	err := &ResponseErr{
+		Method:      r.Request.Method,
+		URL:         r.Request.URL,
+		Status:      r.Status,
+		StatusCode:  r.StatusCode,
+		MetaStatus:  r.Meta.Status,
+		MetaMessage: r.Meta.Message,
+		RawMessage:  r.Meta.RawMessage,
+		FieldErrors: r.Meta.FieldErrors,
+	}
+
+	if len(err.FieldErrors) > 0 {
+		err.MetaMessage = formatFieldErrors(err.FieldErrors)
+
+		return &ValidationError{ResponseErr: err}
+	}
+
+	return err
+}
+
+// Error implements error interface.
+func (r *ResponseErr) Error() string {
+	return fmt.Sprintf("%s %v %s (status %d) err: %s", r.Method, r.URL, r.Status, r.MetaStatus, r.MetaMessage)
+}
+
+// Is reports whether the error matches one of the sentinel errors by its HTTP
+// status code. Affise reports some errors with HTTP 200 and a meta status
+// other than 1 only; those match ErrNotFound when the meta message says the
+// object is not found, e.g. "Offer not found", and no other sentinel.
+func (r *ResponseErr) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return r.StatusCode == http.StatusNotFound || r.metaNotFound()
+	case ErrUnauthorized:
+		return r.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return r.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return r.StatusCode == http.StatusTooManyRequests
+	case ErrValidation:
+		return r.StatusCode == http.StatusBadRequest || r.StatusCode == http.StatusUnprocessableEntity
+	default:
+		return false
+	}
+}
+
+// metaNotFound reports whether a successful HTTP response carries a "not found" meta error.
+func (r *ResponseErr) metaNotFound() bool {
+	if r.StatusCode < 200 || r.StatusCode >= 300 || r.MetaStatus == 1 {
+		return false
+	}
+	msg := strings.ToLower(r.MetaMessage)
+
+	return strings.Contains(msg, "not found") || strings.Contains(msg, "does not exist")
+}
+
+// ValidationError is returned when the API rejects request fields listed in
+// FieldErrors. It matches ErrValidation and wraps ResponseErr.
+type ValidationError struct {
+	*ResponseErr
+}
+
+// Is implements errors.Is.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation || e.ResponseErr.Is(target)
+}
+
+// Unwrap returns the underlying ResponseErr.
+func (e *ValidationError) Unwrap() error {
+	return e.ResponseErr
+}
+
+// formatFieldErrors formats field errors sorted by field name.
+func formatFieldErrors(fields map[string][]string) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	details := make([]string, 0, len(names))
+	for _, name := range names {
+		details = append(details, fmt.Sprintf("%s: %s", name, strings.Join(fields[name], ", ")))
+	}
+
+	return strings.Join(details, "; ")
+}
+
+// parseFieldErrors decodes the object form of the meta message like
+// {"email": ["Email is invalid"], "payments": {"0": {"countries": "Unknown country"}}}.
+// Nested keys are joined with dots. It returns nil if the object contains
+// anything but strings, so objects carrying data are not mistaken for errors.
+func parseFieldErrors(data json.RawMessage) map[string][]string {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil || len(raw) == 0 {
+		return nil
+	}
+
+	res := make(map[string][]string)
+	if !collectFieldErrors(res, "", raw) {
+		return nil
+	}
+
+	return res
+}
+
+func collectFieldErrors(res map[string][]string, prefix string, v interface{}) bool {
+	switch t := v.(type) {
+	case string:
+		res[prefix] = append(res[prefix], t)
+	case []interface{}:
+		for _, item := range t {
+			if !collectFieldErrors(res, prefix, item) {
+				return false
+			}
+		}
+	case map[string]interface{}:
+		for k, item := range t {
+			name := k
+			if prefix != "" {
+				name = prefix + "." + k
+			}
+			if !collectFieldErrors(res, name, item) {
+				return false
+			}
+		}
+	default:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,148 @@
+package affise_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/clobucks/go-sdk/affise"
+)
+
+func TestResponseErr(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{"NotFound", http.StatusNotFound, `{"status":2,"message":"Not found"}`, affise.ErrNotFound},
+		{"Unauthorized", http.StatusUnauthorized, `{"status":2,"message":"Invalid API key"}`, affise.ErrUnauthorized},
+		{"Forbidden", http.StatusForbidden, `{"status":2,"message":"Access denied"}`, affise.ErrForbidden},
+		{"RateLimited", http.StatusTooManyRequests, `{"status":2,"message":"Too many requests"}`, affise.ErrRateLimited},
+		{"BadRequest", http.StatusBadRequest, `{"status":2,"message":"Bad request"}`, affise.ErrValidation},
+		{"MetaNotFound", http.StatusOK, `{"status":2,"message":"Offer not found"}`, affise.ErrNotFound},
+		{"MetaError", http.StatusOK, `{"status":2,"message":"Offer is disabled"}`, nil},
+		{"FieldErrors", http.StatusOK, `{"status":2,"message":{"email":["Email is invalid"]}}`, affise.ErrValidation},
+	}
+
+	sentinels := []error{
+		affise.ErrNotFound,
+		affise.ErrUnauthorized,
+		affise.ErrForbidden,
+		affise.ErrRateLimited,
+		affise.ErrValidation,
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			env := newTestEnv(t)
+			defer env.teardown()
+
+			env.Mux.HandleFunc("/3.0/offer/1", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+
+			_, _, err := env.Client.Offer.Get(env.Ctx, 1)
+			require.Error(t, err)
+
+			for _, sentinel := range sentinels {
+				require.Equal(t, sentinel == tt.want, errors.Is(err, sentinel), sentinel.Error())
+			}
+
+			var respErr *affise.ResponseErr
+			require.True(t, errors.As(err, &respErr))
+			require.Equal(t, tt.status, respErr.StatusCode)
+		})
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	t.Parallel()
+	t.Run("AdminAffiliateCreate", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		defer env.teardown()
+
+		var (
+			fixture = "3.0.admin.partner@post.validation.json"
+			method  = "POST"
+			path    = "/3.0/admin/partner"
+			status  = 200
+		)
+		env.mockHandle(t, fixture, method, path, status)
+
+		_, _, err := env.Client.AdminAffiliate.Create(env.Ctx, &affise.AdminAffiliateCreateOpts{})
+		require.Error(t, err)
+		require.True(t, errors.Is(err, affise.ErrValidation))
+
+		var vErr *affise.ValidationError
+		require.True(t, errors.As(err, &vErr))
+		require.Equal(t, []string{
+			`Email "john@example" is not a valid email address.`,
+			"Email has already been taken.",
+		}, vErr.FieldErrors["email"])
+		require.Equal(t, []string{"Country cannot be blank."}, vErr.FieldErrors["country"])
+		require.Equal(t, 2, vErr.MetaStatus)
+		require.Contains(t, vErr.MetaMessage, "country: Country cannot be blank.; email: ")
+		require.Contains(t, err.Error(), vErr.MetaMessage)
+		require.Contains(t, string(vErr.RawMessage), `"email"`)
+	})
+
+	t.Run("AdminOfferCreateOffer", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		defer env.teardown()
+
+		var (
+			fixture = "3.0.admin.offer@post.validation.json"
+			method  = "POST"
+			path    = "/3.0/admin/offer"
+			status  = 400
+		)
+		env.mockHandle(t, fixture, method, path, status)
+
+		_, resp, err := env.Client.AdminOffer.CreateOffer(env.Ctx, &affise.AdminOfferCreateOfferOpts{})
+		require.Error(t, err)
+		require.Nil(t, resp)
+
+		var respErr *affise.ResponseErr
+		require.True(t, errors.As(err, &respErr))
+		require.Equal(t, map[string][]string{
+			"title":                {"Title cannot be blank."},
+			"advertiser":           {"Advertiser is invalid."},
+			"payments.0.countries": {"Country XX is unknown."},
+		}, respErr.FieldErrors)
+	})
+}
+
+func TestMeta_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		data    string
+		message string
+		fields  map[string][]string
+	}{
+		{`{"status":1,"message":"Invoice updated"}`, "Invoice updated", nil},
+		{`{"status":1,"message":{"number":1,"status":"paid"}}`, "", nil},
+		{`{"status":1,"message":[{"number":1}]}`, "", nil},
+		{`{"status":2,"message":{"email":"Email is invalid"}}`, "", map[string][]string{"email": {"Email is invalid"}}},
+		{`{"status":1}`, "", nil},
+	}
+
+	for _, tt := range tests {
+		var meta affise.Meta
+		require.NoError(t, json.Unmarshal([]byte(tt.data), &meta))
+		require.Equal(t, tt.message, meta.Message)
+		require.Equal(t, tt.fields, meta.FieldErrors)
+	}
+}
@@ -0,0 +1,169 @@
+// Package form encodes nested options with the bracket notation used by the
+// offer endpoints, e.g. payments[0][countries][]=US.
+package form
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Encoder encodes values with the bracket notation.
+type Encoder struct {
+	// Scalar returns the value of types encoded as a single value, e.g.
+	// a city as its ID, and false for other types. It may be nil.
+	Scalar func(v reflect.Value) (string, bool)
+}
+
+// Encode encodes src with the bracket notation: slices of structs as
+// alias[0][field] and slices of scalars as alias[field][]. Field names are
+// taken from schema or json tags. Tags with brackets like "country[allow]"
+// produce nested keys. Zero values of fields tagged with omitempty and zero
+// strings of other fields are omitted, so false and 0 are sent unless the
+// field is tagged with omitempty. Map values, slice items and values of
+// non-nil pointers are always encoded.
+func (e Encoder) Encode(alias string, src interface{}) url.Values {
+	ret := url.Values{}
+	e.encode(ret, alias, reflect.ValueOf(src), omitZero)
+
+	return ret
+}
+
+// zeroMode specifies which zero scalars encode encodes.
+type zeroMode int
+
+const (
+	omitZero    zeroMode = iota // omit all zero values
+	omitZeroStr                 // omit zero strings only
+	keepZero                    // encode zero values
+)
+
+func (e Encoder) encode(values url.Values, key string, v reflect.Value, mode zeroMode) {
+	if s, ok := e.scalar(v); ok {
+		switch {
+		case !v.IsZero(), mode == keepZero && v.Kind() != reflect.Ptr:
+			values.Set(key, s)
+		case mode == omitZeroStr && v.Kind() != reflect.String && v.Kind() != reflect.Ptr:
+			values.Set(key, s)
+		}
+
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			e.encode(values, key, v.Elem(), keepZero)
+		}
+	case reflect.Interface:
+		if !v.IsNil() {
+			e.encode(values, key, v.Elem(), mode)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name, omitEmpty := FieldName(t.Field(i))
+			if name == "" {
+				continue
+			}
+			fieldMode := omitZeroStr
+			if omitEmpty {
+				fieldMode = omitZero
+			}
+			e.encode(values, key+nestedKey(name), v.Field(i), fieldMode)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			e.encode(values, fmt.Sprintf("%s[%v]", key, iter.Key().Interface()), iter.Value(), keepZero)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			item := v.Index(i)
+			if s, ok := e.scalar(item); ok {
+				values.Add(key+"[]", s)
+
+				continue
+			}
+			e.encode(values, fmt.Sprintf("%s[%d]", key, i), item, omitZero)
+		}
+	}
+}
+
+// scalar returns the string form of scalar values.
+func (e Encoder) scalar(v reflect.Value) (string, bool) {
+	if !v.IsValid() {
+		return "", false
+	}
+	if e.Scalar != nil {
+		if s, ok := e.Scalar(v); ok {
+			return s, true
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return "1", true
+		}
+
+		return "0", true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), true
+	case reflect.String:
+		return v.String(), true
+	}
+
+	return "", false
+}
+
+// FieldName returns the name of the struct field from schema or json tags
+// and whether the tag has the omitempty option. The name is empty for
+// skipped and unexported fields.
+func FieldName(f reflect.StructField) (string, bool) {
+	if f.PkgPath != "" {
+		return "", false
+	}
+
+	for _, tag := range []string{"schema", "json"} {
+		value, ok := f.Tag.Lookup(tag)
+		if !ok {
+			continue
+		}
+		parts := strings.Split(value, ",")
+		name := parts[0]
+		if name == "-" {
+			return "", false
+		}
+		if name != "" {
+			return name, hasTagOption(parts[1:], "omitempty")
+		}
+	}
+
+	return f.Name, false
+}
+
+func hasTagOption(options []string, option string) bool {
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nestedKey returns the key part for the field name, e.g. "[country][allow]" for "country[allow]".
+func nestedKey(name string) string {
+	if i := strings.Index(name, "["); i >= 0 {
+		return "[" + name[:i] + "]" + name[i:]
+	}
+
+	return "[" + name + "]"
+}
@@ -0,0 +1,15 @@
+// Package macro handles macros like {clickid} in tracking and postback URLs.
+package macro
+
+import (
+	"net/url"
+	"strings"
+)
+
+var unescaper = strings.NewReplacer("%7B", "{", "%7D", "}")
+
+// QueryEscape escapes the query value like url.QueryEscape, but keeps macros
+// readable for the services replacing them.
+func QueryEscape(value string) string {
+	return unescaper.Replace(url.QueryEscape(value))
+}
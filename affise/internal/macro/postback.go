@@ -0,0 +1,136 @@
+package macro
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// postback holds the macros Affise replaces in postback URLs.
+var postback = map[string]struct{}{}
+
+func init() {
+	for _, name := range []string{
+		"clickid", "action_id", "conversion_id", "offer_id", "pid", "goal", "status",
+		"sum", "revenue", "payouts", "currency", "ip", "country",
+		"city", "device", "os", "browser", "ua", "referrer",
+		"sub1", "sub2", "sub3", "sub4", "sub5", "sub6", "sub7", "sub8",
+		"custom_field1", "custom_field2", "custom_field3", "custom_field4",
+		"custom_field5", "custom_field6", "custom_field7",
+	} {
+		postback[name] = struct{}{}
+	}
+}
+
+// PostbackMacros returns the names of the macros allowed in postback URLs, sorted.
+func PostbackMacros() []string {
+	names := make([]string, 0, len(postback))
+	for name := range postback {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ParsePostbackURL checks the postback URL and returns the macros it uses in
+// order of appearance and the problems found: schemes other than http and
+// https, unknown or unclosed macros and characters that must be escaped.
+func ParsePostbackURL(raw string) (macros, problems []string) {
+	u, err := url.Parse(raw)
+	switch {
+	case err != nil:
+		problems = append(problems, err.Error())
+	case u.Scheme != "http" && u.Scheme != "https":
+		problems = append(problems, fmt.Sprintf("scheme %q is not http or https", u.Scheme))
+	case u.Host == "":
+		problems = append(problems, "no host")
+	}
+
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case c == '{':
+			end := strings.IndexAny(raw[i+1:], "{}")
+			if end < 0 || raw[i+1+end] != '}' {
+				problems = append(problems, fmt.Sprintf("unclosed macro at %d", i))
+
+				continue
+			}
+			name := raw[i+1 : i+1+end]
+			if _, ok := postback[name]; ok {
+				macros = append(macros, name)
+			} else if s := suggest(name); s != "" {
+				problems = append(problems, fmt.Sprintf("unknown macro {%s}, did you mean {%s}", name, s))
+			} else {
+				problems = append(problems, fmt.Sprintf("unknown macro {%s}", name))
+			}
+			i += end + 1
+		case c == '}':
+			problems = append(problems, fmt.Sprintf("unexpected '}' at %d", i))
+		case c == '%':
+			if i+2 >= len(raw) || !isHex(raw[i+1]) || !isHex(raw[i+2]) {
+				problems = append(problems, fmt.Sprintf("invalid escape at %d", i))
+			}
+		case !isURLChar(c):
+			problems = append(problems, fmt.Sprintf("unescaped character %q at %d", c, i))
+		}
+	}
+
+	return macros, problems
+}
+
+func isHex(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// isURLChar reports whether c is an unreserved or reserved URL character.
+func isURLChar(c byte) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	}
+
+	return strings.IndexByte("-._~:/?#[]@!$&'()*+,;=", c) >= 0
+}
+
+// suggest returns the supported macro closest to the misspelled name.
+func suggest(name string) string {
+	best, bestDist := "", 3
+	for _, m := range PostbackMacros() {
+		if d := distance(strings.ToLower(name), m); d < bestDist {
+			best, bestDist = m, d
+		}
+	}
+
+	return best
+}
+
+// distance returns the Levenshtein distance of the strings.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j-1] + cost
+			if d := prev[j] + 1; d < cur[j] {
+				cur[j] = d
+			}
+			if d := cur[j-1] + 1; d < cur[j] {
+				cur[j] = d
+			}
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
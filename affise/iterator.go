@@ -0,0 +1,672 @@
+package affise
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrMaxItems is returned by All when the iterator yields more items than allowed.
+var ErrMaxItems = errors.New("iterator: max items exceeded")
+
+// pageFetcher fetches the given page and returns the number of received items.
+type pageFetcher func(ctx context.Context, page int) (int, *Response, error)
+
+// pager walks through the pages of a list endpoint using Meta.Pagination.
+type pager struct {
+	ctx   context.Context
+	fetch pageFetcher
+	page  int
+	idx   int
+	size  int
+	last  bool
+	resp  *Response
+	err   error
+}
+
+func newPager(ctx context.Context, page int, fetch pageFetcher) pager {
+	if page < 1 {
+		page = 1
+	}
+
+	return pager{
+		ctx:   ctx,
+		fetch: fetch,
+		page:  page,
+		idx:   -1,
+	}
+}
+
+// Next advances the iterator to the next item, fetching the next page when
+// the current one is exhausted. It returns false when there are no more items
+// or an error occurred.
+func (p *pager) Next() bool {
+	if p.err != nil {
+		return false
+	}
+
+	p.idx++
+	for p.idx >= p.size {
+		if p.last {
+			return false
+		}
+
+		if err := p.ctx.Err(); err != nil {
+			p.err = err
+
+			return false
+		}
+
+		n, resp, err := p.fetch(p.ctx, p.page)
+		if err != nil {
+			p.err = err
+
+			return false
+		}
+
+		p.resp = resp
+		p.idx = 0
+		p.size = n
+		p.advance(n)
+	}
+
+	return true
+}
+
+// advance computes the next page number from the last response.
+func (p *pager) advance(n int) {
+	if n == 0 || p.resp == nil || p.resp.Meta.Pagination == nil {
+		p.last = true
+
+		return
+	}
+
+	pg := p.resp.Meta.Pagination
+	switch {
+	case pg.NextPage > p.page:
+		p.page = pg.NextPage
+	case pg.NextPage == 0 && pg.PerPage > 0 && pg.Page*pg.PerPage < pg.TotalCount:
+		p.page = pg.Page + 1
+	default:
+		p.last = true
+	}
+}
+
+// Err returns the error occurred during iteration, if any.
+func (p *pager) Err() error {
+	return p.err
+}
+
+// Response returns the response of the last fetched page.
+func (p *pager) Response() *Response {
+	return p.resp
+}
+
+// ConversionIter iterates over conversions page by page.
+type ConversionIter struct {
+	pager
+	items []*Conversion
+}
+
+func newConversionIter(ctx context.Context, page int,
+	fetch func(ctx context.Context, page int) ([]*Conversion, *Response, error)) *ConversionIter {
+	it := &ConversionIter{}
+	it.pager = newPager(ctx, page, func(ctx context.Context, page int) (int, *Response, error) {
+		v, resp, err := fetch(ctx, page)
+		it.items = v
+
+		return len(v), resp, err
+	})
+
+	return it
+}
+
+// Value returns the current conversion.
+func (it *ConversionIter) Value() *Conversion {
+	return it.items[it.idx]
+}
+
+// All collects the remaining conversions. If max is positive and there are
+// more than max items, the first max items are returned with ErrMaxItems.
+func (it *ConversionIter) All(max int) ([]*Conversion, error) {
+	var res []*Conversion
+	for it.Next() {
+		if max > 0 && len(res) >= max {
+			return res, ErrMaxItems
+		}
+		res = append(res, it.Value())
+	}
+
+	return res, it.Err()
+}
+
+// ClickIter iterates over clicks page by page.
+type ClickIter struct {
+	pager
+	items []*Click
+}
+
+func newClickIter(ctx context.Context, page int,
+	fetch func(ctx context.Context, page int) ([]*Click, *Response, error)) *ClickIter {
+	it := &ClickIter{}
+	it.pager = newPager(ctx, page, func(ctx context.Context, page int) (int, *Response, error) {
+		v, resp, err := fetch(ctx, page)
+		it.items = v
+
+		return len(v), resp, err
+	})
+
+	return it
+}
+
+// Value returns the current click.
+func (it *ClickIter) Value() *Click {
+	return it.items[it.idx]
+}
+
+// All collects the remaining clicks. If max is positive and there are
+// more than max items, the first max items are returned with ErrMaxItems.
+func (it *ClickIter) All(max int) ([]*Click, error) {
+	var res []*Click
+	for it.Next() {
+		if max > 0 && len(res) >= max {
+			return res, ErrMaxItems
+		}
+		res = append(res, it.Value())
+	}
+
+	return res, it.Err()
+}
+
+// StatIter iterates over statistics page by page.
+type StatIter struct {
+	pager
+	items []*Stat
+}
+
+func newStatIter(ctx context.Context, page int,
+	fetch func(ctx context.Context, page int) ([]*Stat, *Response, error)) *StatIter {
+	it := &StatIter{}
+	it.pager = newPager(ctx, page, func(ctx context.Context, page int) (int, *Response, error) {
+		v, resp, err := fetch(ctx, page)
+		it.items = v
+
+		return len(v), resp, err
+	})
+
+	return it
+}
+
+// Value returns the current stat.
+func (it *StatIter) Value() *Stat {
+	return it.items[it.idx]
+}
+
+// All collects the remaining stats. If max is positive and there are
+// more than max items, the first max items are returned with ErrMaxItems.
+func (it *StatIter) All(max int) ([]*Stat, error) {
+	var res []*Stat
+	for it.Next() {
+		if max > 0 && len(res) >= max {
+			return res, ErrMaxItems
+		}
+		res = append(res, it.Value())
+	}
+
+	return res, it.Err()
+}
+
+// StatPostbackIter iterates over postback logs page by page.
+type StatPostbackIter struct {
+	pager
+	items []*StatPostback
+}
+
+func newStatPostbackIter(ctx context.Context, page int,
+	fetch func(ctx context.Context, page int) ([]*StatPostback, *Response, error)) *StatPostbackIter {
+	it := &StatPostbackIter{}
+	it.pager = newPager(ctx, page, func(ctx context.Context, page int) (int, *Response, error) {
+		v, resp, err := fetch(ctx, page)
+		it.items = v
+
+		return len(v), resp, err
+	})
+
+	return it
+}
+
+// Value returns the current postback log entry.
+func (it *StatPostbackIter) Value() *StatPostback {
+	return it.items[it.idx]
+}
+
+// All collects the remaining postback log entries. If max is positive and there are
+// more than max items, the first max items are returned with ErrMaxItems.
+func (it *StatPostbackIter) All(max int) ([]*StatPostback, error) {
+	var res []*StatPostback
+	for it.Next() {
+		if max > 0 && len(res) >= max {
+			return res, ErrMaxItems
+		}
+		res = append(res, it.Value())
+	}
+
+	return res, it.Err()
+}
+
+// OfferIter iterates over offers page by page.
+type OfferIter struct {
+	pager
+	items []*Offer
+}
+
+func newOfferIter(ctx context.Context, page int,
+	fetch func(ctx context.Context, page int) ([]*Offer, *Response, error)) *OfferIter {
+	it := &OfferIter{}
+	it.pager = newPager(ctx, page, func(ctx context.Context, page int) (int, *Response, error) {
+		v, resp, err := fetch(ctx, page)
+		it.items = v
+
+		return len(v), resp, err
+	})
+
+	return it
+}
+
+// Value returns the current offer.
+func (it *OfferIter) Value() *Offer {
+	return it.items[it.idx]
+}
+
+// All collects the remaining offers. If max is positive and there are
+// more than max items, the first max items are returned with ErrMaxItems.
+func (it *OfferIter) All(max int) ([]*Offer, error) {
+	var res []*Offer
+	for it.Next() {
+		if max > 0 && len(res) >= max {
+			return res, ErrMaxItems
+		}
+		res = append(res, it.Value())
+	}
+
+	return res, it.Err()
+}
+
+// AffiliateIter iterates over affiliates page by page.
+type AffiliateIter struct {
+	pager
+	items []*Affiliate
+}
+
+func newAffiliateIter(ctx context.Context, page int,
+	fetch func(ctx context.Context, page int) ([]*Affiliate, *Response, error)) *AffiliateIter {
+	it := &AffiliateIter{}
+	it.pager = newPager(ctx, page, func(ctx context.Context, page int) (int, *Response, error) {
+		v, resp, err := fetch(ctx, page)
+		it.items = v
+
+		return len(v), resp, err
+	})
+
+	return it
+}
+
+// Value returns the current affiliate.
+func (it *AffiliateIter) Value() *Affiliate {
+	return it.items[it.idx]
+}
+
+// All collects the remaining affiliates. If max is positive and there are
+// more than max items, the first max items are returned with ErrMaxItems.
+func (it *AffiliateIter) All(max int) ([]*Affiliate, error) {
+	var res []*Affiliate
+	for it.Next() {
+		if max > 0 && len(res) >= max {
+			return res, ErrMaxItems
+		}
+		res = append(res, it.Value())
+	}
+
+	return res, it.Err()
+}
+
+// PostbackIter iterates over affiliate postbacks page by page.
+type PostbackIter struct {
+	pager
+	items []*Postback
+}
+
+func newPostbackIter(ctx context.Context, page int,
+	fetch func(ctx context.Context, page int) ([]*Postback, *Response, error)) *PostbackIter {
+	it := &PostbackIter{}
+	it.pager = newPager(ctx, page, func(ctx context.Context, page int) (int, *Response, error) {
+		v, resp, err := fetch(ctx, page)
+		it.items = v
+
+		return len(v), resp, err
+	})
+
+	return it
+}
+
+// Value returns the current postback.
+func (it *PostbackIter) Value() *Postback {
+	return it.items[it.idx]
+}
+
+// All collects the remaining postbacks. If max is positive and there are
+// more than max items, the first max items are returned with ErrMaxItems.
+func (it *PostbackIter) All(max int) ([]*Postback, error) {
+	var res []*Postback
+	for it.Next() {
+		if max > 0 && len(res) >= max {
+			return res, ErrMaxItems
+		}
+		res = append(res, it.Value())
+	}
+
+	return res, it.Err()
+}
+
+// AdvertiserIter iterates over advertisers page by page.
+type AdvertiserIter struct {
+	pager
+	items []*Advertiser
+}
+
+func newAdvertiserIter(ctx context.Context, page int,
+	fetch func(ctx context.Context, page int) ([]*Advertiser, *Response, error)) *AdvertiserIter {
+	it := &AdvertiserIter{}
+	it.pager = newPager(ctx, page, func(ctx context.Context, page int) (int, *Response, error) {
+		v, resp, err := fetch(ctx, page)
+		it.items = v
+
+		return len(v), resp, err
+	})
+
+	return it
+}
+
+// Value returns the current advertiser.
+func (it *AdvertiserIter) Value() *Advertiser {
+	return it.items[it.idx]
+}
+
+// All collects the remaining advertisers. If max is positive and there are
+// more than max items, the first max items are returned with ErrMaxItems.
+func (it *AdvertiserIter) All(max int) ([]*Advertiser, error) {
+	var res []*Advertiser
+	for it.Next() {
+		if max > 0 && len(res) >= max {
+			return res, ErrMaxItems
+		}
+		res = append(res, it.Value())
+	}
+
+	return res, it.Err()
+}
+
+// MessageIter iterates over advertiser invoices page by page.
+type MessageIter struct {
+	pager
+	items []*Message
+}
+
+func newMessageIter(ctx context.Context, page int,
+	fetch func(ctx context.Context, page int) ([]*Message, *Response, error)) *MessageIter {
+	it := &MessageIter{}
+	it.pager = newPager(ctx, page, func(ctx context.Context, page int) (int, *Response, error) {
+		v, resp, err := fetch(ctx, page)
+		it.items = v
+
+		return len(v), resp, err
+	})
+
+	return it
+}
+
+// Value returns the current invoice.
+func (it *MessageIter) Value() *Message {
+	return it.items[it.idx]
+}
+
+// All collects the remaining invoices. If max is positive and there are
+// more than max items, the first max items are returned with ErrMaxItems.
+func (it *MessageIter) All(max int) ([]*Message, error) {
+	var res []*Message
+	for it.Next() {
+		if max > 0 && len(res) >= max {
+			return res, ErrMaxItems
+		}
+		res = append(res, it.Value())
+	}
+
+	return res, it.Err()
+}
+
+// UserIter iterates over users page by page.
+type UserIter struct {
+	pager
+	items []*User
+}
+
+func newUserIter(ctx context.Context, page int,
+	fetch func(ctx context.Context, page int) ([]*User, *Response, error)) *UserIter {
+	it := &UserIter{}
+	it.pager = newPager(ctx, page, func(ctx context.Context, page int) (int, *Response, error) {
+		v, resp, err := fetch(ctx, page)
+		it.items = v
+
+		return len(v), resp, err
+	})
+
+	return it
+}
+
+// Value returns the current user.
+func (it *UserIter) Value() *User {
+	return it.items[it.idx]
+}
+
+// All collects the remaining users. If max is positive and there are
+// more than max items, the first max items are returned with ErrMaxItems.
+func (it *UserIter) All(max int) ([]*User, error) {
+	var res []*User
+	for it.Next() {
+		if max > 0 && len(res) >= max {
+			return res, ErrMaxItems
+		}
+		res = append(res, it.Value())
+	}
+
+	return res, it.Err()
+}
+
+// TicketIter iterates over tickets page by page.
+type TicketIter struct {
+	pager
+	items []*Ticket
+}
+
+func newTicketIter(ctx context.Context, page int,
+	fetch func(ctx context.Context, page int) ([]*Ticket, *Response, error)) *TicketIter {
+	it := &TicketIter{}
+	it.pager = newPager(ctx, page, func(ctx context.Context, page int) (int, *Response, error) {
+		v, resp, err := fetch(ctx, page)
+		it.items = v
+
+		return len(v), resp, err
+	})
+
+	return it
+}
+
+// Value returns the current ticket.
+func (it *TicketIter) Value() *Ticket {
+	return it.items[it.idx]
+}
+
+// All collects the remaining tickets. If max is positive and there are
+// more than max items, the first max items are returned with ErrMaxItems.
+func (it *TicketIter) All(max int) ([]*Ticket, error) {
+	var res []*Ticket
+	for it.Next() {
+		if max > 0 && len(res) >= max {
+			return res, ErrMaxItems
+		}
+		res = append(res, it.Value())
+	}
+
+	return res, it.Err()
+}
+
+// PresetIter iterates over presets page by page.
+type PresetIter struct {
+	pager
+	items []*Preset
+}
+
+func newPresetIter(ctx context.Context, page int,
+	fetch func(ctx context.Context, page int) ([]*Preset, *Response, error)) *PresetIter {
+	it := &PresetIter{}
+	it.pager = newPager(ctx, page, func(ctx context.Context, page int) (int, *Response, error) {
+		v, resp, err := fetch(ctx, page)
+		it.items = v
+
+		return len(v), resp, err
+	})
+
+	return it
+}
+
+// Value returns the current preset.
+func (it *PresetIter) Value() *Preset {
+	return it.items[it.idx]
+}
+
+// All collects the remaining presets. If max is positive and there are
+// more than max items, the first max items are returned with ErrMaxItems.
+func (it *PresetIter) All(max int) ([]*Preset, error) {
+	var res []*Preset
+	for it.Next() {
+		if max > 0 && len(res) >= max {
+			return res, ErrMaxItems
+		}
+		res = append(res, it.Value())
+	}
+
+	return res, it.Err()
+}
+
+// CategoryIter iterates over categories page by page.
+type CategoryIter struct {
+	pager
+	items []*Category
+}
+
+func newCategoryIter(ctx context.Context, page int,
+	fetch func(ctx context.Context, page int) ([]*Category, *Response, error)) *CategoryIter {
+	it := &CategoryIter{}
+	it.pager = newPager(ctx, page, func(ctx context.Context, page int) (int, *Response, error) {
+		v, resp, err := fetch(ctx, page)
+		it.items = v
+
+		return len(v), resp, err
+	})
+
+	return it
+}
+
+// Value returns the current category.
+func (it *CategoryIter) Value() *Category {
+	return it.items[it.idx]
+}
+
+// All collects the remaining categories. If max is positive and there are
+// more than max items, the first max items are returned with ErrMaxItems.
+func (it *CategoryIter) All(max int) ([]*Category, error) {
+	var res []*Category
+	for it.Next() {
+		if max > 0 && len(res) >= max {
+			return res, ErrMaxItems
+		}
+		res = append(res, it.Value())
+	}
+
+	return res, it.Err()
+}
+
+// RefPaymentIter iterates over referral payments page by page.
+type RefPaymentIter struct {
+	pager
+	items []*RefPayment
+}
+
+func newRefPaymentIter(ctx context.Context, page int,
+	fetch func(ctx context.Context, page int) ([]*RefPayment, *Response, error)) *RefPaymentIter {
+	it := &RefPaymentIter{}
+	it.pager = newPager(ctx, page, func(ctx context.Context, page int) (int, *Response, error) {
+		v, resp, err := fetch(ctx, page)
+		it.items = v
+
+		return len(v), resp, err
+	})
+
+	return it
+}
+
+// Value returns the current referral payment.
+func (it *RefPaymentIter) Value() *RefPayment {
+	return it.items[it.idx]
+}
+
+// All collects the remaining referral payments. If max is positive and there are
+// more than max items, the first max items are returned with ErrMaxItems.
+func (it *RefPaymentIter) All(max int) ([]*RefPayment, error) {
+	var res []*RefPayment
+	for it.Next() {
+		if max > 0 && len(res) >= max {
+			return res, ErrMaxItems
+		}
+		res = append(res, it.Value())
+	}
+
+	return res, it.Err()
+}
+
+// SubIter iterates over subs page by page.
+type SubIter struct {
+	pager
+	items []*Sub
+}
+
+func newSubIter(ctx context.Context, page int,
+	fetch func(ctx context.Context, page int) ([]*Sub, *Response, error)) *SubIter {
+	it := &SubIter{}
+	it.pager = newPager(ctx, page, func(ctx context.Context, page int) (int, *Response, error) {
+		v, resp, err := fetch(ctx, page)
+		it.items = v
+
+		return len(v), resp, err
+	})
+
+	return it
+}
+
+// Value returns the current sub.
+func (it *SubIter) Value() *Sub {
+	return it.items[it.idx]
+}
+
+// All collects the remaining subs. If max is positive and there are
+// more than max items, the first max items are returned with ErrMaxItems.
+func (it *SubIter) All(max int) ([]*Sub, error) {
+	var res []*Sub
+	for it.Next() {
+		if max > 0 && len(res) >= max {
+			return res, ErrMaxItems
+		}
+		res = append(res, it.Value())
+	}
+
+	return res, it.Err()
+}
@@ -0,0 +1,183 @@
+package affise_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/clobucks/go-sdk/affise"
+)
+
+// pagedHandle serves total items split into pages of perPage using the given key.
+func (env *testEnv) pagedHandle(t *testing.T, path, key string, total, perPage int, nextPage bool) {
+	t.Helper()
+
+	env.Mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 1 {
+			page = 1
+		}
+
+		items := ""
+		for i := (page - 1) * perPage; i < page*perPage && i < total; i++ {
+			if items != "" {
+				items += ","
+			}
+			items += fmt.Sprintf(`{"id":"%d"}`, i)
+		}
+
+		next := ""
+		if nextPage && page*perPage < total {
+			next = fmt.Sprintf(`,"next_page":%d`, page+1)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"status":1,"%s":[%s],"pagination":{"page":%d,"per_page":%d,"total_count":%d%s}}`,
+			key, items, page, perPage, total, next)
+	})
+}
+
+func TestIterator(t *testing.T) {
+	t.Parallel()
+	t.Run("NextPage", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		defer env.teardown()
+
+		env.pagedHandle(t, "/3.0/stats/conversions", "conversions", 7, 3, true)
+
+		it := env.Client.Statistic.ConversionsIter(env.Ctx, &affise.StatisticConversionsOpts{Limit: 3})
+		var ids []string
+		for it.Next() {
+			ids = append(ids, it.Value().ID)
+		}
+		require.NoError(t, it.Err())
+		require.Equal(t, []string{"0", "1", "2", "3", "4", "5", "6"}, ids)
+		require.Equal(t, 3, it.Response().Meta.Pagination.Page)
+	})
+
+	t.Run("TotalCount", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		defer env.teardown()
+
+		env.pagedHandle(t, "/3.0/admin/users", "users", 5, 2, false)
+
+		v, err := env.Client.AdminUser.ListIter(env.Ctx, nil).All(0)
+		require.NoError(t, err)
+		require.Len(t, v, 5)
+		require.Equal(t, "4", v[4].ID)
+	})
+
+	t.Run("StartPage", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		defer env.teardown()
+
+		env.pagedHandle(t, "/3.0/stats/clicks", "clicks", 6, 2, true)
+
+		opts := &affise.StatisticClicksOpts{Page: 2}
+		v, err := env.Client.Statistic.ClicksIter(env.Ctx, opts).All(0)
+		require.NoError(t, err)
+		require.Len(t, v, 4)
+		require.Equal(t, "2", v[0].ID)
+		require.Equal(t, 2, opts.Page)
+	})
+
+	t.Run("MaxItems", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		defer env.teardown()
+
+		env.pagedHandle(t, "/3.0/stats/clicks", "clicks", 10, 3, true)
+
+		v, err := env.Client.Statistic.ClicksIter(env.Ctx, nil).All(4)
+		require.True(t, errors.Is(err, affise.ErrMaxItems))
+		require.Len(t, v, 4)
+	})
+
+	t.Run("Tickets", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		defer env.teardown()
+
+		env.pagedHandle(t, "/3.0/admin/tickets", "tickets", 5, 2, true)
+
+		v, err := env.Client.AdminOther.ListTicketsIter(env.Ctx, nil).All(0)
+		require.NoError(t, err)
+		require.Len(t, v, 5)
+		require.Equal(t, "4", v[4].ID)
+	})
+
+	t.Run("Presets", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		defer env.teardown()
+
+		env.pagedHandle(t, "/3.1/presets", "presets", 4, 3, true)
+
+		v, err := env.Client.AdminPreset.ListIter(env.Ctx, nil).All(0)
+		require.NoError(t, err)
+		require.Len(t, v, 4)
+		require.Equal(t, "3", v[3].ID)
+	})
+
+	t.Run("Categories", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		defer env.teardown()
+
+		env.pagedHandle(t, "/3.0/offer/categories", "categories", 3, 2, false)
+
+		v, err := env.Client.Offer.ListCategoriesIter(env.Ctx, nil).All(0)
+		require.NoError(t, err)
+		require.Len(t, v, 3)
+		require.Equal(t, "2", v[2].ID)
+	})
+
+	t.Run("RefPayments", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		defer env.teardown()
+
+		env.pagedHandle(t, "/3.0/stats/getreferralpayments", "ref_payments", 7, 3, true)
+
+		opts := &affise.StatisticGetByReferralPaymentsOpts{Page: 2}
+		v, err := env.Client.Statistic.GetByReferralPaymentsIter(env.Ctx, opts).All(0)
+		require.NoError(t, err)
+		require.Len(t, v, 4)
+	})
+
+	t.Run("Subs", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		defer env.teardown()
+
+		env.pagedHandle(t, "/3.0/stats/find-subs", "subs", 5, 2, true)
+
+		it := env.Client.Statistic.FindSubsIter(env.Ctx, nil)
+		var ids []string
+		for it.Next() {
+			ids = append(ids, (*it.Value())["id"])
+		}
+		require.NoError(t, it.Err())
+		require.Equal(t, []string{"0", "1", "2", "3", "4"}, ids)
+	})
+
+	t.Run("Err", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		defer env.teardown()
+
+		env.mockHandle(t, "3.0.stats.getbydate@get.json", "GET", "/3.0/stats/getbydate", 500)
+
+		it := env.Client.Statistic.GetByDateIter(env.Ctx, nil)
+		require.False(t, it.Next())
+		require.Error(t, it.Err())
+		require.False(t, it.Next())
+	})
+}
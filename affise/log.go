@@ -0,0 +1,193 @@
+package affise
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Redacted replaces sensitive values in logs.
+const Redacted = "[REDACTED]"
+
+// Logger is a structured logger. It is satisfied by *slog.Logger.
+type Logger interface {
+	InfoContext(ctx context.Context, msg string, args ...interface{})
+	ErrorContext(ctx context.Context, msg string, args ...interface{})
+}
+
+func (c *Client) log(ctx context.Context, cl *CallInfo) {
+	if c.logger == nil {
+		return
+	}
+
+	args := []interface{}{
+		"service", cl.Service,
+		"method", cl.Method,
+		"path", cl.Path,
+		"query", RedactValues(cl.Query).Encode(),
+		"page", cl.Page,
+		"status", cl.Status,
+		"duration", cl.Duration,
+		"bytes", cl.Bytes,
+		"attempts", cl.Attempts,
+	}
+	if cl.Meta != nil {
+		args = append(args, "meta_status", cl.Meta.Status)
+	}
+	if cl.RateLimitWait > 0 {
+		args = append(args, "rate_limit_wait", cl.RateLimitWait)
+	}
+
+	if cl.Err != nil {
+		args = append(args, "err", redactError(cl.Err).Error())
+		c.logger.ErrorContext(ctx, "affise api call failed", args...)
+
+		return
+	}
+
+	c.logger.InfoContext(ctx, "affise api call", args...)
+}
+
+// redactedError wraps an error with the message redacted.
+type redactedError struct {
+	err error
+	msg string
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// redactError returns err with the request URLs of ResponseErr and url.Error
+// redacted in its message, so failed calls don't leak passwords sent in the
+// query to logs and traces. The original error is available with errors.As.
+func redactError(err error) error {
+	msg := err.Error()
+	replace := func(raw string) {
+		u, perr := url.Parse(raw)
+		if perr != nil {
+			msg = strings.ReplaceAll(msg, raw, Redacted)
+
+			return
+		}
+		redacted := redactURL(u)
+		msg = strings.ReplaceAll(msg, strconv.Quote(raw), strconv.Quote(redacted))
+		msg = strings.ReplaceAll(msg, raw, redacted)
+	}
+
+	var rerr *ResponseErr
+	if errors.As(err, &rerr) && rerr.URL != nil {
+		replace(rerr.URL.String())
+	}
+	var uerr *url.Error
+	if errors.As(err, &uerr) {
+		replace(uerr.URL)
+	}
+
+	return &redactedError{err: err, msg: msg}
+}
+
+// redactURL returns the URL with sensitive query parameters and user info redacted.
+func redactURL(u *url.URL) string {
+	c := *u
+	c.User = nil
+	if c.RawQuery != "" {
+		c.RawQuery = RedactValues(c.Query()).Encode()
+	}
+
+	return c.String()
+}
+
+// isSensitiveKey reports whether values of the parameter or field must be
+// redacted. For bracket parameters like list[0][ip] the last key is checked.
+func isSensitiveKey(key string) bool {
+	key = strings.TrimSuffix(key, "]")
+	if i := strings.LastIndex(key, "["); i >= 0 {
+		key = key[i+1:]
+	}
+	key = strings.ToLower(key)
+
+	switch key {
+	case "api_key", "api-key", "apikey", "ip", "token", "secret":
+		return true
+	}
+
+	return strings.Contains(key, "password")
+}
+
+// RedactHeader returns a copy of h with the API-Key and other credentials redacted.
+func RedactHeader(h http.Header) http.Header {
+	res := h.Clone()
+	for k := range res {
+		if isSensitiveKey(k) || strings.EqualFold(k, "Authorization") || strings.EqualFold(k, "Cookie") {
+			res[k] = []string{Redacted}
+		}
+	}
+
+	return res
+}
+
+// RedactValues returns a copy of values with sensitive parameters such as
+// passwords, API keys and IPs redacted.
+func RedactValues(values url.Values) url.Values {
+	res := make(url.Values, len(values))
+	for k, v := range values {
+		if isSensitiveKey(k) {
+			res[k] = []string{Redacted}
+
+			continue
+		}
+		res[k] = append([]string(nil), v...)
+	}
+
+	return res
+}
+
+// RedactJSON returns a copy of the JSON document with sensitive fields such as
+// passwords, API keys and IPs redacted. Numbers are kept as is. Invalid JSON
+// is returned as Redacted.
+func RedactJSON(data []byte) []byte {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	var v interface{}
+	if err := d.Decode(&v); err != nil || d.More() {
+		return []byte(Redacted)
+	}
+
+	res, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return []byte(Redacted)
+	}
+
+	return res
+}
+
+func redactValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if isSensitiveKey(k) {
+				t[k] = Redacted
+
+				continue
+			}
+			t[k] = redactValue(val)
+		}
+	case []interface{}:
+		for i, val := range t {
+			t[i] = redactValue(val)
+		}
+	}
+
+	return v
+}
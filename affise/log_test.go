@@ -0,0 +1,182 @@
+package affise_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/clobucks/go-sdk/affise"
+)
+
+type testLogRecord struct {
+	Level string
+	Msg   string
+	Attrs map[string]interface{}
+}
+
+type testLogger struct {
+	mu      sync.Mutex
+	records []testLogRecord
+}
+
+func (l *testLogger) add(level, msg string, args []interface{}) {
+	attrs := make(map[string]interface{})
+	for i := 0; i+1 < len(args); i += 2 {
+		attrs[fmt.Sprint(args[i])] = args[i+1]
+	}
+
+	l.mu.Lock()
+	l.records = append(l.records, testLogRecord{Level: level, Msg: msg, Attrs: attrs})
+	l.mu.Unlock()
+}
+
+func (l *testLogger) InfoContext(_ context.Context, msg string, args ...interface{}) {
+	l.add("info", msg, args)
+}
+
+func (l *testLogger) ErrorContext(_ context.Context, msg string, args ...interface{}) {
+	l.add("error", msg, args)
+}
+
+func TestClient_WithLogger(t *testing.T) {
+	t.Parallel()
+	t.Run("Info", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		defer env.teardown()
+
+		env.mockHandle(t, "3.0.admin.user@post.json", http.MethodPost, "/3.0/admin/user", http.StatusOK)
+		logger := &testLogger{}
+		client, err := affise.NewClient(
+			affise.WithAdminURL(env.Server.URL),
+			affise.WithAPIKey("secret-key"),
+			affise.WithLogger(logger),
+		)
+		require.NoError(t, err)
+
+		opts := &affise.AdminUserCreateOpts{
+			Email:     "john@example.com",
+			Password:  "qwerty123",
+			FirstName: "John",
+		}
+		_, _, err = client.AdminUser.Create(env.Ctx, opts)
+		require.NoError(t, err)
+
+		require.Len(t, logger.records, 1)
+		rec := logger.records[0]
+		require.Equal(t, "info", rec.Level)
+		require.Equal(t, "AdminUser.Create", rec.Attrs["service"])
+		require.Equal(t, http.MethodPost, rec.Attrs["method"])
+		require.Equal(t, "/3.0/admin/user", rec.Attrs["path"])
+		require.Equal(t, http.StatusOK, rec.Attrs["status"])
+		require.Equal(t, 1, rec.Attrs["meta_status"])
+		require.NotZero(t, rec.Attrs["bytes"])
+
+		query, err := url.ParseQuery(rec.Attrs["query"].(string))
+		require.NoError(t, err)
+		require.Equal(t, affise.Redacted, query.Get("password"))
+		require.Equal(t, "John", query.Get("first_name"))
+
+		for _, v := range rec.Attrs {
+			require.NotContains(t, fmt.Sprint(v), "qwerty123")
+			require.NotContains(t, fmt.Sprint(v), "secret-key")
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		defer env.teardown()
+
+		env.mockHandle(t, "3.0.stats.getbydate@get.json", http.MethodGet, "/3.0/stats/getbydate", http.StatusBadGateway)
+		logger := &testLogger{}
+		client, err := affise.NewClient(
+			affise.WithBaseURL(env.Server.URL),
+			affise.WithLogger(logger),
+		)
+		require.NoError(t, err)
+
+		_, err = client.Statistic.GetByDateIter(env.Ctx, &affise.StatisticGetByDateOpts{Page: 3}).All(0)
+		require.Error(t, err)
+
+		require.Len(t, logger.records, 1)
+		rec := logger.records[0]
+		require.Equal(t, "error", rec.Level)
+		require.Equal(t, "Statistic.GetByDate", rec.Attrs["service"])
+		require.Equal(t, "3", rec.Attrs["page"])
+		require.Equal(t, http.StatusBadGateway, rec.Attrs["status"])
+		require.NotEmpty(t, rec.Attrs["err"])
+	})
+
+	t.Run("ErrorRedacted", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		defer env.teardown()
+
+		env.mockHandle(t, "3.0.admin.user@post.json", http.MethodPost, "/3.0/admin/user", http.StatusBadRequest)
+		logger := &testLogger{}
+		client, err := affise.NewClient(
+			affise.WithAdminURL(env.Server.URL),
+			affise.WithLogger(logger),
+		)
+		require.NoError(t, err)
+
+		_, _, err = client.AdminUser.Create(env.Ctx, &affise.AdminUserCreateOpts{Email: "john@example.com", Password: "qwerty123"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "qwerty123", "the returned error is not redacted")
+
+		closed, err := affise.NewClient(
+			affise.WithAdminURL("http://127.0.0.1:1"),
+			affise.WithLogger(logger),
+		)
+		require.NoError(t, err)
+		_, _, err = closed.AdminUser.Create(env.Ctx, &affise.AdminUserCreateOpts{Email: "john@example.com", Password: "qwerty123"})
+		require.Error(t, err)
+
+		require.Len(t, logger.records, 2)
+		for _, rec := range logger.records {
+			require.Equal(t, "error", rec.Level)
+			require.Contains(t, rec.Attrs["err"], "password="+url.QueryEscape(affise.Redacted))
+			for _, v := range rec.Attrs {
+				require.NotContains(t, fmt.Sprint(v), "qwerty123")
+			}
+		}
+	})
+}
+
+func TestRedact(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{"partner":{"id":1,"api_key":"k","password":"p"},"conversions":[{"ip":"1.2.3.4","hash_password":"h","city":"Rome"}]}`)
+	var v map[string]interface{}
+	require.NoError(t, json.Unmarshal(affise.RedactJSON(data), &v))
+
+	partner := v["partner"].(map[string]interface{})
+	require.Equal(t, affise.Redacted, partner["api_key"])
+	require.Equal(t, affise.Redacted, partner["password"])
+	require.EqualValues(t, 1, partner["id"])
+
+	conversion := v["conversions"].([]interface{})[0].(map[string]interface{})
+	require.Equal(t, affise.Redacted, conversion["ip"])
+	require.Equal(t, affise.Redacted, conversion["hash_password"])
+	require.Equal(t, "Rome", conversion["city"])
+	require.Equal(t, `{"id":12345678901234567}`, string(affise.RedactJSON([]byte(`{"id":12345678901234567}`))))
+
+	header := http.Header{"Api-Key": []string{"k"}, "User-Agent": []string{"go-sdk"}}
+	redacted := affise.RedactHeader(header)
+	require.Equal(t, affise.Redacted, redacted.Get("API-Key"))
+	require.Equal(t, "go-sdk", redacted.Get("User-Agent"))
+	require.Equal(t, "k", header.Get("API-Key"))
+
+	values := affise.RedactValues(url.Values{"list[0][ip]": {"1.2.3.4"}, "list[0][offer]": {"1"}})
+	require.Equal(t, affise.Redacted, values.Get("list[0][ip]"))
+	require.Equal(t, "1", values.Get("list[0][offer]"))
+	require.False(t, strings.Contains(values.Encode(), "1.2.3.4"))
+}
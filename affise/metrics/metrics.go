@@ -0,0 +1,240 @@
+// Package metrics collects usage metrics of the Affise API client and exposes
+// them in the Prometheus text format.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/clobucks/go-sdk/affise"
+)
+
+// DefaultBuckets are the default latency histogram buckets in seconds.
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// An Option is used to configure a Collector.
+type Option func(*Collector)
+
+// WithNamespace sets the prefix of metric names (Default: affise).
+func WithNamespace(ns string) Option {
+	return func(c *Collector) {
+		c.namespace = ns
+	}
+}
+
+// WithBuckets sets the latency histogram buckets in seconds.
+func WithBuckets(buckets ...float64) Option {
+	return func(c *Collector) {
+		c.buckets = append([]float64(nil), buckets...)
+		sort.Float64s(c.buckets)
+	}
+}
+
+// Collector collects API call metrics. It implements affise.Observer and http.Handler.
+type Collector struct {
+	namespace string
+	buckets   []float64
+
+	mu           sync.Mutex
+	requests     *counterVec
+	retries      *counterVec
+	waits        *counterVec
+	bytes        *counterVec
+	metaFailures *counterVec
+	durations    map[string]*histogram
+}
+
+// NewCollector creates a new collector.
+func NewCollector(options ...Option) *Collector {
+	c := &Collector{
+		namespace: "affise",
+		buckets:   DefaultBuckets,
+		durations: make(map[string]*histogram),
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	c.requests = newCounterVec(c.name("requests_total"), "Number of API calls.", "service", "code")
+	c.retries = newCounterVec(c.name("retries_total"), "Number of retried attempts.", "service")
+	c.waits = newCounterVec(c.name("rate_limit_wait_seconds_total"), "Time spent waiting for the client rate limiter.", "service")
+	c.bytes = newCounterVec(c.name("response_bytes_total"), "Size of received response bodies.", "service")
+	c.metaFailures = newCounterVec(c.name("meta_failures_total"), "Number of responses with meta status other than 1.", "service", "meta_status")
+
+	return c
+}
+
+func (c *Collector) name(s string) string {
+	if c.namespace == "" {
+		return s
+	}
+
+	return c.namespace + "_" + s
+}
+
+// ObserveCall implements affise.Observer.
+func (c *Collector) ObserveCall(_ context.Context, call *affise.CallInfo) {
+	service := call.Service
+	if service == "" {
+		service = "unknown"
+	}
+	code := "error"
+	if call.Status != 0 {
+		code = strconv.Itoa(call.Status)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests.add(1, service, code)
+	if call.Attempts > 1 {
+		c.retries.add(float64(call.Attempts-1), service)
+	}
+	if call.RateLimitWait > 0 {
+		c.waits.add(call.RateLimitWait.Seconds(), service)
+	}
+	c.bytes.add(float64(call.Bytes), service)
+	if call.Meta != nil && call.Meta.Status != 1 {
+		c.metaFailures.add(1, service, strconv.Itoa(call.Meta.Status))
+	}
+
+	h, ok := c.durations[service]
+	if !ok {
+		h = newHistogram(c.buckets)
+		c.durations[service] = h
+	}
+	h.observe(call.Duration.Seconds())
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b := &strings.Builder{}
+	c.requests.write(b)
+	c.writeDurations(b)
+	c.retries.write(b)
+	c.waits.write(b)
+	c.bytes.write(b)
+	c.metaFailures.write(b)
+
+	n, err := io.WriteString(w, b.String())
+
+	return int64(n), err
+}
+
+// ServeHTTP implements http.Handler to be mounted as a metrics endpoint.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = c.WriteTo(w)
+}
+
+func (c *Collector) writeDurations(b *strings.Builder) {
+	name := c.name("request_duration_seconds")
+	fmt.Fprintf(b, "# HELP %s Duration of API calls in seconds.\n", name)
+	fmt.Fprintf(b, "# TYPE %s histogram\n", name)
+
+	services := make([]string, 0, len(c.durations))
+	for s := range c.durations {
+		services = append(services, s)
+	}
+	sort.Strings(services)
+
+	for _, s := range services {
+		h := c.durations[s]
+		for i, le := range h.buckets {
+			fmt.Fprintf(b, "%s_bucket{service=%q,le=%q} %d\n", name, s, formatFloat(le), h.counts[i])
+		}
+		fmt.Fprintf(b, "%s_bucket{service=%q,le=\"+Inf\"} %d\n", name, s, h.count)
+		fmt.Fprintf(b, "%s_sum{service=%q} %s\n", name, s, formatFloat(h.sum))
+		fmt.Fprintf(b, "%s_count{service=%q} %d\n", name, s, h.count)
+	}
+}
+
+// counterVec is a counter partitioned by label values.
+type counterVec struct {
+	name   string
+	help   string
+	labels []string
+	values map[string]float64
+}
+
+func newCounterVec(name, help string, labels ...string) *counterVec {
+	return &counterVec{
+		name:   name,
+		help:   help,
+		labels: labels,
+		values: make(map[string]float64),
+	}
+}
+
+const labelSep = "\xff"
+
+func (v *counterVec) add(delta float64, labelValues ...string) {
+	v.values[strings.Join(labelValues, labelSep)] += delta
+}
+
+func (v *counterVec) write(b *strings.Builder) {
+	fmt.Fprintf(b, "# HELP %s %s\n", v.name, v.help)
+	fmt.Fprintf(b, "# TYPE %s counter\n", v.name)
+
+	keys := make([]string, 0, len(v.values))
+	for k := range v.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		values := strings.Split(k, labelSep)
+		pairs := make([]string, 0, len(values))
+		for i, lv := range values {
+			pairs = append(pairs, fmt.Sprintf("%s=%q", v.labels[i], lv))
+		}
+		fmt.Fprintf(b, "%s{%s} %s\n", v.name, strings.Join(pairs, ","), formatFloat(v.values[k]))
+	}
+}
+
+// histogram counts observations in cumulative buckets.
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	}
+}
+
+func (h *histogram) observe(v float64) {
+	for i, le := range h.buckets {
+		if v <= le {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
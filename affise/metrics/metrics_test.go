@@ -0,0 +1,72 @@
+package metrics_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/clobucks/go-sdk/affise"
+	"github.com/clobucks/go-sdk/affise/metrics"
+)
+
+func TestCollector(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/3.0/admin/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":1,"users":[]}`))
+	})
+	mux.HandleFunc("/3.0/offer/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":2,"message":"Offer not found"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	collector := metrics.NewCollector(metrics.WithBuckets(1, 0.001))
+	client, err := affise.NewClient(
+		affise.WithBaseURL(server.URL),
+		affise.WithAdminURL(server.URL),
+		affise.WithObserver(collector),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, _, err = client.AdminUser.List(ctx, &affise.AdminUserListOpts{})
+		require.NoError(t, err)
+	}
+	_, _, err = client.Offer.Get(ctx, 1)
+	require.Error(t, err)
+
+	collector.ObserveCall(ctx, &affise.CallInfo{
+		Service:       "Statistic.GetByDate",
+		Attempts:      3,
+		RateLimitWait: 1500 * time.Millisecond,
+		Duration:      2 * time.Second,
+	})
+
+	rec := httptest.NewRecorder()
+	collector.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+
+	body := rec.Body.String()
+	require.Contains(t, body, "# TYPE affise_requests_total counter\n")
+	require.Contains(t, body, `affise_requests_total{service="AdminUser.List",code="200"} 2`+"\n")
+	require.Contains(t, body, `affise_requests_total{service="Offer.Get",code="200"} 1`+"\n")
+	require.Contains(t, body, `affise_requests_total{service="Statistic.GetByDate",code="error"} 1`+"\n")
+	require.Contains(t, body, `affise_meta_failures_total{service="Offer.Get",meta_status="2"} 1`+"\n")
+	require.Contains(t, body, `affise_retries_total{service="Statistic.GetByDate"} 2`+"\n")
+	require.Contains(t, body, `affise_rate_limit_wait_seconds_total{service="Statistic.GetByDate"} 1.5`+"\n")
+	require.Contains(t, body, "# TYPE affise_request_duration_seconds histogram\n")
+	require.Contains(t, body, `affise_request_duration_seconds_bucket{service="Statistic.GetByDate",le="1"} 0`+"\n")
+	require.Contains(t, body, `affise_request_duration_seconds_bucket{service="Statistic.GetByDate",le="+Inf"} 1`+"\n")
+	require.Contains(t, body, `affise_request_duration_seconds_sum{service="Statistic.GetByDate"} 2`+"\n")
+	require.Contains(t, body, `affise_request_duration_seconds_count{service="AdminUser.List"} 2`+"\n")
+	require.Contains(t, body, `affise_response_bytes_total{service="AdminUser.List"} 46`+"\n")
+}
@@ -0,0 +1,155 @@
+package affise
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+	"sync"
+)
+
+// File is a file uploaded with multipart/form-data. The content is streamed
+// from Reader without buffering it in memory. A request with files is
+// retried only if all readers implement io.Seeker.
+type File struct {
+	Name        string    // File name, e.g. logo.png
+	ContentType string    // Content type (Default: application/octet-stream)
+	Reader      io.Reader // Content
+}
+
+// formFile is a file of a multipart form field.
+type formFile struct {
+	field string
+	file  *File
+}
+
+// filer is implemented by options with files to upload.
+type filer interface {
+	files() []formFile
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// multipartBody is a request body streaming files as multipart/form-data.
+// Files are written by a goroutine started on the first Read.
+type multipartBody struct {
+	files    []formFile
+	boundary string
+
+	once sync.Once
+	pr   *io.PipeReader
+	done chan struct{}
+}
+
+func newMultipartBody(files []formFile) *multipartBody {
+	return &multipartBody{
+		files:    files,
+		boundary: multipart.NewWriter(ioutil.Discard).Boundary(),
+	}
+}
+
+func (b *multipartBody) contentType() string {
+	return "multipart/form-data; boundary=" + b.boundary
+}
+
+func (b *multipartBody) Read(p []byte) (int, error) {
+	b.once.Do(b.start)
+	if b.pr == nil {
+		return 0, io.ErrClosedPipe
+	}
+
+	return b.pr.Read(p)
+}
+
+// Close stops the writing goroutine and waits for it to finish, so the
+// readers are not used after Close returns. A body closed before the first
+// Read never starts it.
+func (b *multipartBody) Close() error {
+	b.once.Do(func() {})
+	if b.pr == nil {
+		return nil
+	}
+
+	err := b.pr.Close()
+	<-b.done
+
+	return err
+}
+
+func (b *multipartBody) start() {
+	pr, pw := io.Pipe()
+	b.pr = pr
+	b.done = make(chan struct{})
+
+	go func() {
+		defer close(b.done)
+		_ = pw.CloseWithError(b.write(pw))
+	}()
+}
+
+func (b *multipartBody) write(w io.Writer) error {
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(b.boundary); err != nil {
+		return err
+	}
+
+	for _, f := range b.files {
+		contentType := f.file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			quoteEscaper.Replace(f.field), quoteEscaper.Replace(f.file.Name)))
+		h.Set("Content-Type", contentType)
+
+		part, err := mw.CreatePart(h)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, f.file.Reader); err != nil {
+			return fmt.Errorf("read file %q err: %w", f.file.Name, err)
+		}
+	}
+
+	return mw.Close()
+}
+
+// getBody returns a function creating a new body from the current positions
+// of the readers. It is nil if some reader is not an io.Seeker.
+func (b *multipartBody) getBody() func() (io.ReadCloser, error) {
+	offsets := make([]int64, len(b.files))
+	for i, f := range b.files {
+		s, ok := f.file.Reader.(io.Seeker)
+		if !ok {
+			return nil
+		}
+		offset, err := s.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil
+		}
+		offsets[i] = offset
+	}
+
+	var mu sync.Mutex
+	last := b
+
+	return func() (io.ReadCloser, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// the previous body must not read the files while they are rewound
+		_ = last.Close()
+		for i, f := range b.files {
+			if _, err := f.file.Reader.(io.Seeker).Seek(offsets[i], io.SeekStart); err != nil {
+				return nil, fmt.Errorf("seek file %q err: %w", f.file.Name, err)
+			}
+		}
+		last = &multipartBody{files: b.files, boundary: b.boundary}
+
+		return last, nil
+	}
+}
@@ -0,0 +1,76 @@
+package affise
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// CallInfo describes a completed API call.
+type CallInfo struct {
+	Service       string        // Service method, e.g. "Statistic.GetByDate"
+	Method        string        // HTTP method
+	Path          string        // URL path
+	Query         url.Values    // URL query, not redacted
+	Page          string        // Requested page
+	Status        int           // HTTP status code, zero if no response was received
+	Meta          *Meta         // Response meta, nil if no response was received
+	Bytes         int           // Size of the response body
+	Attempts      int           // Number of attempts made
+	RateLimitWait time.Duration // Time spent waiting for the client rate limiter
+	Duration      time.Duration // Total duration of the call
+	Err           error         // Error returned to the caller
+}
+
+// Observer is notified about every completed API call, e.g. to collect metrics.
+type Observer interface {
+	ObserveCall(ctx context.Context, call *CallInfo)
+}
+
+func newCall(r *http.Request, resp *Response, size int, d time.Duration, err error) *CallInfo {
+	query := r.URL.Query()
+	c := &CallInfo{
+		Service:  ServiceMethod(r.Context()),
+		Method:   r.Method,
+		Path:     r.URL.Path,
+		Query:    query,
+		Page:     query.Get("page"),
+		Bytes:    size,
+		Duration: d,
+		Err:      err,
+	}
+
+	if resp != nil {
+		c.Attempts = resp.Attempts
+		c.RateLimitWait = resp.RateLimitWait
+		if resp.Response != nil {
+			c.Status = resp.StatusCode
+			c.Meta = &resp.Meta
+		}
+	}
+
+	return c
+}
+
+func (c *Client) observe(ctx context.Context, call *CallInfo) {
+	for _, o := range c.observers {
+		o.ObserveCall(ctx, call)
+	}
+}
+
+type serviceMethodKey struct{}
+
+// ServiceMethod returns the name of the service method which created the request
+// with ctx, e.g. "Statistic.GetByDate". It returns an empty string when unknown.
+func ServiceMethod(ctx context.Context) string {
+	s, _ := ctx.Value(serviceMethodKey{}).(string)
+
+	return s
+}
+
+// withServiceMethod returns a copy of ctx carrying the name of the service
+// method creating the request. Service methods call it before NewRequest.
+func withServiceMethod(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, serviceMethodKey{}, name)
+}
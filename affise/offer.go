@@ -36,6 +36,8 @@ type offerListResponse struct {
 
 // Offers list.
 func (s *OfferService) List(ctx context.Context, opts *OfferListOpts) ([]*Offer, *Response, error) {
+	ctx = withServiceMethod(ctx, "Offer.List")
+
 	path := "/3.0/offers"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -52,6 +54,20 @@ func (s *OfferService) List(ctx context.Context, opts *OfferListOpts) ([]*Offer,
 	return body.Offers, resp, nil
 }
 
+// ListIter returns an iterator over all pages of List starting from opts.Page.
+func (s *OfferService) ListIter(ctx context.Context, opts *OfferListOpts) *OfferIter {
+	o := OfferListOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newOfferIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Offer, *Response, error) {
+		o.Page = page
+
+		return s.List(ctx, &o)
+	})
+}
+
 // offerGetResponse specifies response for Get.
 type offerGetResponse struct {
 	Offer *Offer `json:"offer"`
@@ -59,6 +75,8 @@ type offerGetResponse struct {
 
 // Offer by id.
 func (s *OfferService) Get(ctx context.Context, id int) (*Offer, *Response, error) {
+	ctx = withServiceMethod(ctx, "Offer.Get")
+
 	path := fmt.Sprintf("/3.0/offer/%d", id)
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, false)
@@ -88,6 +106,8 @@ type offerListCategoriesResponse struct {
 
 // Categories.
 func (s *OfferService) ListCategories(ctx context.Context, opts *OfferListCategoriesOpts) ([]*Category, *Response, error) {
+	ctx = withServiceMethod(ctx, "Offer.ListCategories")
+
 	path := "/3.0/offer/categories"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -103,3 +123,17 @@ func (s *OfferService) ListCategories(ctx context.Context, opts *OfferListCatego
 
 	return body.Categories, resp, nil
 }
+
+// ListCategoriesIter returns an iterator over all pages of ListCategories starting from opts.Page.
+func (s *OfferService) ListCategoriesIter(ctx context.Context, opts *OfferListCategoriesOpts) *CategoryIter {
+	o := OfferListCategoriesOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newCategoryIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Category, *Response, error) {
+		o.Page = page
+
+		return s.ListCategories(ctx, &o)
+	})
+}
@@ -29,6 +29,7 @@ func TestOfferService_Mock(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, 1, resp.Meta.Status)
 		require.True(t, len(v) == 1)
+		require.Len(t, v[0].Targeting, 1)
 	})
 
 	t.Run("Get", func(t *testing.T) {
@@ -0,0 +1,203 @@
+package offersync
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/clobucks/go-sdk/affise"
+	"github.com/clobucks/go-sdk/affise/internal/form"
+)
+
+// Change is a difference of an offer field.
+type Change struct {
+	Field string `json:"field"` // Field path, e.g. title, payments[0].total or payments[2] for a whole item
+	From  string `json:"from"`  // Current value, empty for added values
+	To    string `json:"to"`    // Desired value, empty for removed items
+}
+
+func (c Change) String() string {
+	switch {
+	case c.From == "":
+		return fmt.Sprintf("%s: + %s", c.Field, c.To)
+	case c.To == "":
+		return fmt.Sprintf("%s: - %s", c.Field, c.From)
+	default:
+		return fmt.Sprintf("%s: %s -> %s", c.Field, c.From, c.To)
+	}
+}
+
+// diffSpec returns the changes needed to make the current offer match the
+// spec. current is nil for offers to create.
+func diffSpec(spec *Spec, current *affise.Offer) []Change {
+	if current == nil {
+		current = &affise.Offer{}
+	}
+
+	var changes []Change
+	scalar := func(field, want, got string) {
+		if want != "" && want != got {
+			changes = append(changes, Change{Field: field, From: got, To: want})
+		}
+	}
+	scalar("title", spec.Title, current.Title)
+	scalar("advertiser", spec.Advertiser, current.Advertiser)
+	scalar("url", spec.URL, current.URL)
+	scalar("url_preview", spec.URLPreview, current.URLPreview)
+	scalar("status", spec.Status, current.Status)
+	scalar("privacy", spec.Privacy, current.Privacy)
+
+	if len(spec.Categories) != 0 {
+		scalar("categories", joinSorted(spec.Categories), joinSorted(current.Categories))
+	}
+	if len(spec.Sources) != 0 {
+		ids := make([]string, 0, len(current.Sources))
+		for _, s := range current.Sources {
+			ids = append(ids, s.ID)
+		}
+		scalar("sources", joinSorted(spec.Sources), joinSorted(ids))
+	}
+
+	changes = append(changes, diffList("payments", spec.Payments, current.Payments)...)
+	changes = append(changes, diffList("caps", spec.Caps, current.Caps)...)
+	changes = append(changes, diffList("targeting", spec.Targeting, current.Targeting)...)
+	changes = append(changes, diffList("landings", spec.Landings, current.Landings)...)
+
+	return changes
+}
+
+// diffList compares the desired list with the current one. Only fields sent
+// for the desired items are compared, so values filled in by Affise do not
+// produce changes. An empty desired list is not compared.
+func diffList(field string, desired, current interface{}) []Change {
+	d := reflect.ValueOf(desired)
+	if d.Len() == 0 {
+		return nil
+	}
+
+	return diffItems(field, reflect.ValueOf(current), d, true)
+}
+
+// diffItems compares lists item by item. Added and removed items are
+// reported as a whole. If onlySet is true, only fields sent for the items
+// of to are compared.
+func diffItems(field string, from, to reflect.Value, onlySet bool) []Change {
+	var changes []Change
+	for i := 0; i < from.Len() || i < to.Len(); i++ {
+		item := fmt.Sprintf("%s[%d]", field, i)
+		switch {
+		case i >= from.Len():
+			changes = append(changes, Change{Field: item, To: summary(flatten(to.Index(i)))})
+		case i >= to.Len():
+			changes = append(changes, Change{Field: item, From: summary(flatten(from.Index(i)))})
+		default:
+			changes = append(changes, diffFlat(item+".", flatten(from.Index(i)), flatten(to.Index(i)), onlySet)...)
+		}
+	}
+
+	return changes
+}
+
+// diffFlat compares flattened values. If onlySet is true, only keys of to are compared.
+func diffFlat(prefix string, from, to map[string]string, onlySet bool) []Change {
+	keys := sortedKeys(to)
+	if !onlySet {
+		for k := range from {
+			if _, ok := to[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+	}
+
+	var changes []Change
+	for _, k := range keys {
+		if from[k] != to[k] {
+			changes = append(changes, Change{Field: strings.TrimSuffix(prefix+k, "."), From: from[k], To: to[k]})
+		}
+	}
+
+	return changes
+}
+
+// flatten returns the fields of the item sent with the bracket notation by
+// their paths like "countries" or "region.allow.US". Scalar lists are joined
+// with commas.
+func flatten(v reflect.Value) map[string]string {
+	ret := make(map[string]string)
+	if !v.IsValid() {
+		return ret
+	}
+	for key, values := range encoder.Encode("", v.Interface()) {
+		ret[flatPath(key)] = strings.Join(values, ",")
+	}
+
+	return ret
+}
+
+// flatPath converts the bracket key like "[region][allow][US][]" to "region.allow.US".
+func flatPath(key string) string {
+	key = strings.TrimSuffix(strings.TrimPrefix(key, "["), "[]")
+	key = strings.TrimSuffix(key, "]")
+
+	return strings.ReplaceAll(key, "][", ".")
+}
+
+// cityType is the type of cities, which are sent as their IDs.
+var cityType = reflect.TypeOf(affise.City{})
+
+// encoder encodes offer fields as CreateOffer and UpdateOffer send them.
+var encoder = form.Encoder{Scalar: func(v reflect.Value) (string, bool) {
+	if v.Type() != cityType {
+		return "", false
+	}
+
+	return strconv.Itoa(v.Interface().(affise.City).ID), true
+}}
+
+// fieldName returns the name of the offer field as it is encoded.
+func fieldName(f reflect.StructField) string {
+	name, _ := form.FieldName(f)
+
+	return name
+}
+
+func joinPath(path, name string) string {
+	switch {
+	case path == "":
+		return name
+	case name == "":
+		return path
+	}
+
+	return path + "." + name
+}
+
+// summary formats the flattened item as "key=value" pairs.
+func summary(item map[string]string) string {
+	pairs := make([]string, 0, len(item))
+	for _, k := range sortedKeys(item) {
+		pairs = append(pairs, k+"="+item[k])
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func joinSorted(s []string) string {
+	s = append([]string(nil), s...)
+	sort.Strings(s)
+
+	return strings.Join(s, ",")
+}
@@ -0,0 +1,164 @@
+package offersync
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/clobucks/go-sdk/affise"
+)
+
+// ignoredOfferFields change without edits of the offer.
+var ignoredOfferFields = map[string]bool{
+	"updated_at": true,
+	"cr":         true,
+	"epc":        true,
+}
+
+// DiffOffers returns the changes from offer a to offer b, e.g. between two
+// snapshots of the same offer. Items of nested lists like payments, caps,
+// commission tiers, landings and targeting are compared field by field;
+// added and removed items are reported as a whole.
+func DiffOffers(a, b *affise.Offer) []Change {
+	if a == nil {
+		a = &affise.Offer{}
+	}
+	if b == nil {
+		b = &affise.Offer{}
+	}
+
+	av, bv := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+	t := av.Type()
+
+	var changes []Change
+	for i := 0; i < t.NumField(); i++ {
+		name := fieldName(t.Field(i))
+		if name == "" || ignoredOfferFields[name] {
+			continue
+		}
+
+		from, to := av.Field(i), bv.Field(i)
+		if from.Kind() == reflect.Slice && from.Type().Elem().Kind() == reflect.Struct && from.Type().Elem() != cityType {
+			changes = append(changes, diffItems(name, from, to, false)...)
+
+			continue
+		}
+		changes = append(changes, diffFlat(name+".", flatten(from), flatten(to), false)...)
+	}
+
+	return changes
+}
+
+// Revision is a change of an offer between two snapshots.
+type Revision struct {
+	OfferID int      `json:"offer_id"`
+	From    string   `json:"from"` // UpdatedAt of the previous snapshot, empty for the first one
+	To      string   `json:"to"`   // UpdatedAt of the snapshot
+	Changes []Change `json:"changes"`
+}
+
+// History stores offer snapshots to keep an audit trail of offer edits.
+type History struct {
+	client *affise.Client
+	store  SnapshotStore
+}
+
+// NewHistory creates a new history kept in the store.
+func NewHistory(client *affise.Client, store SnapshotStore) *History {
+	return &History{client: client, store: store}
+}
+
+// Snapshot fetches the offers with Offer.Get and stores the versions not
+// stored yet. It returns the revisions made since the previous snapshots.
+func (h *History) Snapshot(ctx context.Context, ids ...int) ([]*Revision, error) {
+	var revisions []*Revision
+	for _, id := range ids {
+		offer, _, err := h.client.Offer.Get(ctx, id)
+		if err != nil {
+			return revisions, fmt.Errorf("offersync: get offer %d err: %w", id, err)
+		}
+
+		prev, err := latest(ctx, h.store, id)
+		if err != nil {
+			return revisions, err
+		}
+
+		s := &Snapshot{OfferID: id, UpdatedAt: offer.UpdatedAt, TakenAt: time.Now(), Offer: offer}
+		saved, err := h.store.Save(ctx, s)
+		if err != nil {
+			return revisions, fmt.Errorf("offersync: save snapshot of offer %d err: %w", id, err)
+		}
+		if saved {
+			revisions = append(revisions, revision(prev, s))
+		}
+	}
+
+	return revisions, nil
+}
+
+// Watch takes snapshots of the offers every interval until the context is
+// done and calls onChange for every new revision. Errors of a round are
+// passed to onError if it is not nil and do not stop watching.
+func (h *History) Watch(ctx context.Context, interval time.Duration, ids []int, onChange func(*Revision), onError func(error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		revisions, err := h.Snapshot(ctx, ids...)
+		for _, r := range revisions {
+			onChange(r)
+		}
+		if err != nil && onError != nil && ctx.Err() == nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Revisions returns the revisions of the offer from the stored snapshots,
+// oldest first.
+func (h *History) Revisions(ctx context.Context, offerID int) ([]*Revision, error) {
+	snapshots, err := h.store.List(ctx, offerID)
+	if err != nil {
+		return nil, fmt.Errorf("offersync: list snapshots of offer %d err: %w", offerID, err)
+	}
+
+	revisions := make([]*Revision, 0, len(snapshots))
+	var prev *Snapshot
+	for _, s := range snapshots {
+		revisions = append(revisions, revision(prev, s))
+		prev = s
+	}
+
+	return revisions, nil
+}
+
+func revision(prev, s *Snapshot) *Revision {
+	r := &Revision{OfferID: s.OfferID, To: s.UpdatedAt}
+	var from *affise.Offer
+	if prev != nil {
+		r.From = prev.UpdatedAt
+		from = prev.Offer
+	}
+	r.Changes = DiffOffers(from, s.Offer)
+
+	return r
+}
+
+func latest(ctx context.Context, store SnapshotStore, offerID int) (*Snapshot, error) {
+	snapshots, err := store.List(ctx, offerID)
+	if err != nil {
+		return nil, fmt.Errorf("offersync: list snapshots of offer %d err: %w", offerID, err)
+	}
+	if len(snapshots) == 0 {
+		return nil, nil
+	}
+
+	return snapshots[len(snapshots)-1], nil
+}
@@ -0,0 +1,138 @@
+package offersync_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/clobucks/go-sdk/affise"
+	"github.com/clobucks/go-sdk/affise/affisetest"
+	"github.com/clobucks/go-sdk/affise/offersync"
+)
+
+func testOffer() *affise.Offer {
+	return &affise.Offer{
+		ID:        1,
+		Title:     "Offer",
+		UpdatedAt: "2021-01-04 10:00:00",
+		Payments: []affise.Payment{
+			{Countries: []string{"US"}, Cities: []affise.City{{ID: 5, Name: "Boston"}}, Total: 10, Revenue: 8, Currency: "usd", Type: "fixed"},
+		},
+		Caps:            []affise.Cap{{Period: "day", Type: "conversions", Value: "100", GoalType: "all"}},
+		CommissionTiers: []affise.CommissionTier{{Timeframe: "day", Type: "conversions", Value: "10", ModifierValue: 1.5, ModifierType: "by_fix"}},
+		Landings:        []affise.Landing{{Title: "Main", URL: "https://example.com/l"}},
+		Targeting:       []affise.TargetingGroup{{CountryAllow: []string{"US"}}},
+		KPI:             map[string]string{"en": "No fraud"},
+	}
+}
+
+func TestDiffOffers(t *testing.T) {
+	t.Parallel()
+
+	a, b := testOffer(), testOffer()
+	b.UpdatedAt = "2021-01-05 10:00:00"
+	b.CR = 0.5
+	b.Title = "Renamed"
+	b.Payments[0].Revenue = 9
+	b.Payments[0].Cities = append(b.Payments[0].Cities, affise.City{ID: 6})
+	b.Caps = nil
+	b.CommissionTiers[0].ModifierValue = 2
+	b.Landings = append(b.Landings, affise.Landing{Title: "Second", URL: "https://example.com/2"})
+	b.Targeting[0].CountryAllow = []string{"US", "CA"}
+	b.KPI = map[string]string{"en": "No fraud", "es": "Sin fraude"}
+
+	require.Equal(t, []offersync.Change{
+		{Field: "title", From: "Offer", To: "Renamed"},
+		{Field: "payments[0].cities", From: "5", To: "5,6"},
+		{Field: "payments[0].revenue", From: "8", To: "9"},
+		{Field: "landings[1]", To: "title=Second url=https://example.com/2"},
+		{Field: "kpi.es", To: "Sin fraude"},
+		{Field: "caps[0]", From: "goal_type=all period=day type=conversions value=100"},
+		{Field: "targeting[0].country.allow", From: "US", To: "US,CA"},
+		{Field: "commission_tiers[0].modifier_value", From: "1.5", To: "2"},
+	}, offersync.DiffOffers(a, b))
+
+	require.Empty(t, offersync.DiffOffers(a, testOffer()))
+}
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu    sync.Mutex
+		offer = testOffer()
+	)
+	setOffer := func(f func(o *affise.Offer)) {
+		mu.Lock()
+		defer mu.Unlock()
+		f(offer)
+	}
+
+	server := affisetest.NewServer(t)
+	server.HandleFunc(http.MethodGet, "/3.0/offer/{id}", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		affisetest.WriteJSON(w, http.StatusOK, map[string]interface{}{"status": 1, "offer": offer})
+	})
+
+	ctx := context.Background()
+	store := offersync.NewFileStore(t.TempDir())
+	history := offersync.NewHistory(server.Client, store)
+
+	revisions, err := history.Snapshot(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	require.Equal(t, "", revisions[0].From)
+	require.Equal(t, "2021-01-04 10:00:00", revisions[0].To)
+
+	revisions, err = history.Snapshot(ctx, 1)
+	require.NoError(t, err)
+	require.Empty(t, revisions, "the version is stored")
+
+	setOffer(func(o *affise.Offer) {
+		o.Payments[0].Total = 12
+		o.UpdatedAt = "2021-01-05 10:00:00"
+	})
+	revisions, err = history.Snapshot(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, []*offersync.Revision{{
+		OfferID: 1,
+		From:    "2021-01-04 10:00:00",
+		To:      "2021-01-05 10:00:00",
+		Changes: []offersync.Change{{Field: "payments[0].total", From: "10", To: "12"}},
+	}}, revisions)
+
+	snapshots, err := store.List(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	require.Equal(t, 12.0, snapshots[1].Offer.Payments[0].Total)
+	require.Equal(t, []string{"US"}, snapshots[1].Offer.Targeting[0].CountryAllow)
+
+	revisions, err = history.Revisions(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	require.Equal(t, "2021-01-05 10:00:00", revisions[1].To)
+
+	t.Run("Watch", func(t *testing.T) {
+		setOffer(func(o *affise.Offer) {
+			o.Title = "Renamed"
+			o.UpdatedAt = "2021-01-06 10:00:00"
+		})
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var got []*offersync.Revision
+		err := history.Watch(ctx, time.Millisecond, []int{1}, func(r *offersync.Revision) {
+			got = append(got, r)
+			cancel()
+		}, nil)
+		require.Equal(t, context.Canceled, err)
+		require.Len(t, got, 1)
+		require.Equal(t, []offersync.Change{{Field: "title", From: "Offer", To: "Renamed"}}, got[0].Changes)
+	})
+}
@@ -0,0 +1,312 @@
+// Package offersync reconciles offers in Affise with desired offer specs.
+//
+// Offers are matched by ExternalOfferID. Plan fetches the current offers and
+// computes field-level changes without modifying anything, so it serves as a
+// dry run and a drift report. Apply sends only the CreateOffer and
+// UpdateOffer calls needed to carry out the plan.
+//
+// History stores offer snapshots to keep an audit trail of edits made
+// outside of the specs, and DiffOffers reports changes between them.
+package offersync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/clobucks/go-sdk/affise"
+)
+
+var (
+	// ErrNoExternalID is returned for specs without ExternalOfferID.
+	ErrNoExternalID = errors.New("offersync: external offer ID is empty")
+	// ErrDuplicateExternalID is returned when specs or offers in Affise share ExternalOfferID.
+	ErrDuplicateExternalID = errors.New("offersync: duplicate external offer ID")
+)
+
+// Spec is the desired state of an offer. Empty fields are not managed and
+// are left as they are in Affise. Lists replace the current lists; their
+// items are compared on the fields CreateOffer and UpdateOffer send for them:
+// fields set in the spec, and false and 0 of fields not tagged omitempty,
+// e.g. Payment.CountryExclude.
+type Spec struct {
+	ExternalOfferID string                  `json:"external_offer_id"` // REQUIRED Key matching the offer in Affise
+	Title           string                  `json:"title"`             // Title (REQUIRED to create)
+	Advertiser      string                  `json:"advertiser"`        // Advertiser ID (REQUIRED to create)
+	URL             string                  `json:"url"`               // Tracking URL (REQUIRED to create)
+	URLPreview      string                  `json:"url_preview"`       // View URL
+	Status          string                  `json:"status"`            // Offer status (Available: stopped, active, suspended)
+	Privacy         string                  `json:"privacy"`           // Privacy level (Available: public, protected, private)
+	Categories      []string                `json:"categories"`        // Category IDs, compared as a set
+	Sources         []string                `json:"sources"`           // Traffic source IDs, compared as a set
+	Payments        []affise.Payment        `json:"payments"`          // Payments
+	Caps            []affise.Cap            `json:"caps"`              // Caps
+	Targeting       []affise.TargetingGroup `json:"targeting"`         // Targeting groups
+	Landings        []affise.Landing        `json:"landings"`          // Landings
+}
+
+// Action is an action planned for an offer.
+type Action string
+
+const (
+	Create Action = "create" // the offer does not exist
+	Update Action = "update" // the offer differs from the spec
+	Noop   Action = "noop"   // the offer matches the spec
+)
+
+// OfferPlan is the planned action for a spec.
+type OfferPlan struct {
+	ExternalOfferID string   `json:"external_offer_id"`
+	OfferID         int      `json:"offer_id,omitempty"` // ID of the existing offer
+	Action          Action   `json:"action"`
+	Changes         []Change `json:"changes,omitempty"`
+
+	spec *Spec
+}
+
+// Plan is the result of comparing specs with offers in Affise.
+type Plan struct {
+	Offers    []*OfferPlan    `json:"offers"`    // Actions in the order of specs
+	Unmanaged []*affise.Offer `json:"unmanaged"` // Fetched offers not described by specs. They are never changed.
+}
+
+// HasChanges reports whether Apply would send any request.
+func (p *Plan) HasChanges() bool {
+	for _, o := range p.Offers {
+		if o.Action != Noop {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Count returns the number of offers planned for the action.
+func (p *Plan) Count(action Action) int {
+	n := 0
+	for _, o := range p.Offers {
+		if o.Action == action {
+			n++
+		}
+	}
+
+	return n
+}
+
+// WriteTo writes the plan in a human readable form.
+func (p *Plan) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	for _, o := range p.Offers {
+		switch o.Action {
+		case Create:
+			fmt.Fprintf(&b, "+ create %s\n", o.ExternalOfferID)
+		case Update:
+			fmt.Fprintf(&b, "~ update %s (offer %d)\n", o.ExternalOfferID, o.OfferID)
+		default:
+			fmt.Fprintf(&b, "= %s (offer %d) is up to date\n", o.ExternalOfferID, o.OfferID)
+		}
+		for _, c := range o.Changes {
+			fmt.Fprintf(&b, "    %s\n", c)
+		}
+	}
+	for _, o := range p.Unmanaged {
+		fmt.Fprintf(&b, "? unmanaged offer %d %q external ID %q\n", o.ID, o.Title, o.ExternalOfferID)
+	}
+	fmt.Fprintf(&b, "Plan: %d to create, %d to update, %d up to date, %d unmanaged.\n",
+		p.Count(Create), p.Count(Update), p.Count(Noop), len(p.Unmanaged))
+
+	n, err := io.WriteString(w, b.String())
+
+	return int64(n), err
+}
+
+// An Option is used to configure a Syncer.
+type Option func(*Syncer)
+
+// WithListOpts sets the filter of current offers, e.g. by advertiser. Offers
+// outside of the filter are treated as missing (Default: all statuses).
+func WithListOpts(opts affise.OfferListOpts) Option {
+	return func(s *Syncer) {
+		s.listOpts = opts
+	}
+}
+
+// Syncer plans and applies offer changes.
+type Syncer struct {
+	client   *affise.Client
+	listOpts affise.OfferListOpts
+}
+
+// New creates a new syncer.
+func New(client *affise.Client, options ...Option) *Syncer {
+	s := &Syncer{
+		client: client,
+		listOpts: affise.OfferListOpts{
+			Status: []string{"active", "stopped", "suspended"},
+			Limit:  100,
+		},
+	}
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+// Plan fetches the current offers and compares them with the specs. Nothing
+// is changed in Affise.
+func (s *Syncer) Plan(ctx context.Context, specs []*Spec) (*Plan, error) {
+	seen := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		if spec.ExternalOfferID == "" {
+			return nil, fmt.Errorf("%w: spec %q", ErrNoExternalID, spec.Title)
+		}
+		if seen[spec.ExternalOfferID] {
+			return nil, fmt.Errorf("%w: spec %q", ErrDuplicateExternalID, spec.ExternalOfferID)
+		}
+		seen[spec.ExternalOfferID] = true
+	}
+
+	opts := s.listOpts
+	offers, err := s.client.Offer.ListIter(ctx, &opts).All(0)
+	if err != nil {
+		return nil, fmt.Errorf("offersync: list offers err: %w", err)
+	}
+
+	current := make(map[string]*affise.Offer, len(offers))
+	plan := &Plan{}
+	for _, o := range offers {
+		if !seen[o.ExternalOfferID] {
+			plan.Unmanaged = append(plan.Unmanaged, o)
+
+			continue
+		}
+		if prev, ok := current[o.ExternalOfferID]; ok {
+			return nil, fmt.Errorf("%w: %q is used by offers %d and %d",
+				ErrDuplicateExternalID, o.ExternalOfferID, prev.ID, o.ID)
+		}
+		current[o.ExternalOfferID] = o
+	}
+
+	for _, spec := range specs {
+		o := &OfferPlan{ExternalOfferID: spec.ExternalOfferID, spec: spec}
+		cur, ok := current[spec.ExternalOfferID]
+		o.Changes = diffSpec(spec, cur)
+		switch {
+		case !ok:
+			o.Action = Create
+		case len(o.Changes) != 0:
+			o.Action = Update
+			o.OfferID = cur.ID
+		default:
+			o.Action = Noop
+			o.OfferID = cur.ID
+		}
+		plan.Offers = append(plan.Offers, o)
+	}
+
+	return plan, nil
+}
+
+// Result is the outcome of an applied offer plan.
+type Result struct {
+	ExternalOfferID string
+	OfferID         int // ID of the created or updated offer
+	Action          Action
+	Err             error
+}
+
+// Apply carries out the plan returned by Plan. Created offers get all
+// fields of the spec; updated offers get only the changed fields. Apply
+// continues after failures; the returned error is the first one and every
+// failure is reported in the results.
+func (s *Syncer) Apply(ctx context.Context, plan *Plan) ([]*Result, error) {
+	var (
+		results  []*Result
+		firstErr error
+	)
+	for _, o := range plan.Offers {
+		if o.Action == Noop {
+			continue
+		}
+
+		r := &Result{ExternalOfferID: o.ExternalOfferID, OfferID: o.OfferID, Action: o.Action}
+		var offer *affise.Offer
+		if o.Action == Create {
+			offer, _, r.Err = s.client.AdminOffer.CreateOffer(ctx, createOpts(o.spec))
+		} else {
+			offer, _, r.Err = s.client.AdminOffer.UpdateOffer(ctx, o.OfferID, updateOpts(o.spec, o.Changes))
+		}
+		if r.Err != nil {
+			r.Err = fmt.Errorf("offersync: %s %q err: %w", o.Action, o.ExternalOfferID, r.Err)
+			if firstErr == nil {
+				firstErr = r.Err
+			}
+		} else if offer != nil {
+			r.OfferID = offer.ID
+		}
+		results = append(results, r)
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return results, firstErr
+}
+
+func createOpts(spec *Spec) *affise.AdminOfferCreateOfferOpts {
+	return &affise.AdminOfferCreateOfferOpts{
+		ExternalOfferID: spec.ExternalOfferID,
+		Title:           spec.Title,
+		Advertiser:      spec.Advertiser,
+		URL:             spec.URL,
+		URLPreview:      spec.URLPreview,
+		Status:          spec.Status,
+		Privacy:         spec.Privacy,
+		Categories:      spec.Categories,
+		Sources:         spec.Sources,
+		Payments:        spec.Payments,
+		Caps:            spec.Caps,
+		Targeting:       spec.Targeting,
+		Landings:        spec.Landings,
+	}
+}
+
+// updateOpts returns options with the changed fields. A changed list is sent
+// as a whole because the API replaces lists.
+func updateOpts(spec *Spec, changes []Change) *affise.AdminOfferUpdateOfferOpts {
+	opts := &affise.AdminOfferUpdateOfferOpts{}
+	for _, c := range changes {
+		switch field := strings.SplitN(c.Field, "[", 2)[0]; field {
+		case "title":
+			opts.Title = spec.Title
+		case "advertiser":
+			opts.Advertiser = spec.Advertiser
+		case "url":
+			opts.URL = spec.URL
+		case "url_preview":
+			opts.URLPreview = spec.URLPreview
+		case "status":
+			opts.Status = spec.Status
+		case "privacy":
+			opts.Privacy = spec.Privacy
+		case "categories":
+			opts.Categories = spec.Categories
+		case "sources":
+			opts.Sources = spec.Sources
+		case "payments":
+			opts.Payments = spec.Payments
+		case "caps":
+			opts.Caps = spec.Caps
+		case "targeting":
+			opts.Targeting = spec.Targeting
+		case "landings":
+			opts.Landings = spec.Landings
+		}
+	}
+
+	return opts
+}
@@ -0,0 +1,149 @@
+package offersync_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/clobucks/go-sdk/affise"
+	"github.com/clobucks/go-sdk/affise/affisetest"
+	"github.com/clobucks/go-sdk/affise/offersync"
+)
+
+func TestSyncer(t *testing.T) {
+	t.Parallel()
+
+	backend := affisetest.NewBackend()
+	backend.AddOffer(affise.Offer{
+		ExternalOfferID: "ext-1",
+		Title:           "First",
+		Status:          "active",
+		Payments:        []affise.Payment{{Countries: []string{"US"}, Total: 10, Revenue: 8, Currency: "usd", Type: "fixed", Title: "set by Affise"}},
+		Caps:            []affise.Cap{{Period: "day", Type: "conversions", Value: "100", GoalType: "all"}},
+	})
+	backend.AddOffer(affise.Offer{ExternalOfferID: "ext-2", Title: "Second", Status: "active", Categories: []string{"b", "a"}})
+	backend.AddOffer(affise.Offer{ExternalOfferID: "manual", Title: "Manual", Status: "stopped"})
+	server := affisetest.NewServer(t, affisetest.WithBackend(backend))
+	ctx := context.Background()
+
+	specs := []*offersync.Spec{
+		{
+			ExternalOfferID: "ext-1",
+			Title:           "First",
+			Payments:        []affise.Payment{{Countries: []string{"US"}, Total: 12, Revenue: 8, Currency: "usd", Type: "fixed"}},
+			Targeting:       []affise.TargetingGroup{{CountryAllow: []string{"US"}}},
+		},
+		{ExternalOfferID: "ext-2", Title: "Second", Categories: []string{"a", "b"}},
+		{
+			ExternalOfferID: "ext-3",
+			Title:           "Third",
+			Advertiser:      "5bc9d7c16d73e41c008b4567",
+			URL:             "https://example.com",
+			Landings:        []affise.Landing{{Title: "Main", URL: "https://example.com/l"}},
+		},
+	}
+	syncer := offersync.New(server.Client)
+
+	plan, err := syncer.Plan(ctx, specs)
+	require.NoError(t, err)
+	require.True(t, plan.HasChanges())
+	require.Len(t, plan.Offers, 3)
+
+	require.Equal(t, offersync.Update, plan.Offers[0].Action)
+	require.Equal(t, 1, plan.Offers[0].OfferID)
+	require.Equal(t, []offersync.Change{
+		{Field: "payments[0].total", From: "10", To: "12"},
+		{Field: "targeting[0]", To: "block_proxy=0 country.allow=US"},
+	}, plan.Offers[0].Changes)
+	require.Equal(t, offersync.Noop, plan.Offers[1].Action)
+	require.Equal(t, offersync.Create, plan.Offers[2].Action)
+	require.Contains(t, plan.Offers[2].Changes, offersync.Change{Field: "title", To: "Third"})
+	require.Len(t, plan.Unmanaged, 1)
+	require.Equal(t, "manual", plan.Unmanaged[0].ExternalOfferID)
+
+	var b strings.Builder
+	_, err = plan.WriteTo(&b)
+	require.NoError(t, err)
+	require.Contains(t, b.String(), "~ update ext-1 (offer 1)\n    payments[0].total: 10 -> 12\n")
+	require.Contains(t, b.String(), "Plan: 1 to create, 1 to update, 1 up to date, 1 unmanaged.\n")
+
+	t.Run("Apply", func(t *testing.T) {
+		results, err := syncer.Apply(ctx, plan)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.Equal(t, offersync.Update, results[0].Action)
+		require.Equal(t, offersync.Create, results[1].Action)
+		require.Equal(t, 4, results[1].OfferID)
+
+		offers := backend.Offers()
+		require.Len(t, offers, 4)
+		require.Equal(t, 12.0, offers[0].Payments[0].Total)
+		require.Len(t, offers[0].Caps, 1, "caps are not managed by the spec")
+		require.Equal(t, "ext-3", offers[3].ExternalOfferID)
+
+		plan, err := syncer.Plan(ctx, specs)
+		require.NoError(t, err)
+		require.False(t, plan.HasChanges())
+	})
+
+	t.Run("ApplyError", func(t *testing.T) {
+		plan, err := syncer.Plan(ctx, []*offersync.Spec{{ExternalOfferID: "ext-4", Title: "Invalid"}})
+		require.NoError(t, err)
+
+		results, err := syncer.Apply(ctx, plan)
+		var vErr *affise.ValidationError
+		require.True(t, errors.As(err, &vErr))
+		require.Len(t, results, 1)
+		require.Equal(t, err, results[0].Err)
+	})
+
+	t.Run("InvalidSpecs", func(t *testing.T) {
+		_, err := syncer.Plan(ctx, []*offersync.Spec{{Title: "No ID"}})
+		require.True(t, errors.Is(err, offersync.ErrNoExternalID))
+
+		_, err = syncer.Plan(ctx, []*offersync.Spec{{ExternalOfferID: "ext-1"}, {ExternalOfferID: "ext-1"}})
+		require.True(t, errors.Is(err, offersync.ErrDuplicateExternalID))
+	})
+}
+
+func TestSyncer_ZeroValues(t *testing.T) {
+	t.Parallel()
+
+	backend := affisetest.NewBackend()
+	backend.AddOffer(affise.Offer{
+		ExternalOfferID: "ext-1",
+		Title:           "First",
+		Status:          "active",
+		Payments:        []affise.Payment{{Countries: []string{"US"}, CountryExclude: true, Total: 10, Currency: "usd", Type: "fixed"}},
+		Targeting:       []affise.TargetingGroup{{CountryAllow: []string{"US"}, BlockProxy: 1}},
+	})
+	server := affisetest.NewServer(t, affisetest.WithBackend(backend))
+	ctx := context.Background()
+
+	specs := []*offersync.Spec{{
+		ExternalOfferID: "ext-1",
+		Payments:        []affise.Payment{{Countries: []string{"US"}, Total: 10, Currency: "usd", Type: "fixed"}},
+		Targeting:       []affise.TargetingGroup{{CountryAllow: []string{"US"}}},
+	}}
+	syncer := offersync.New(server.Client)
+
+	plan, err := syncer.Plan(ctx, specs)
+	require.NoError(t, err)
+	require.Equal(t, []offersync.Change{
+		{Field: "payments[0].country_exclude", From: "1", To: "0"},
+		{Field: "targeting[0].block_proxy", From: "1", To: "0"},
+	}, plan.Offers[0].Changes)
+
+	_, err = syncer.Apply(ctx, plan)
+	require.NoError(t, err)
+	offer := backend.Offers()[0]
+	require.False(t, offer.Payments[0].CountryExclude)
+	require.Zero(t, offer.Targeting[0].BlockProxy)
+
+	plan, err = syncer.Plan(ctx, specs)
+	require.NoError(t, err)
+	require.False(t, plan.HasChanges())
+}
@@ -0,0 +1,133 @@
+package offersync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/clobucks/go-sdk/affise"
+)
+
+const updatedAtLayout = "2006-01-02 15:04:05"
+
+// Snapshot is the state of an offer at its UpdatedAt.
+type Snapshot struct {
+	OfferID   int           `json:"offer_id"`
+	UpdatedAt string        `json:"updated_at"` // Offer.UpdatedAt
+	TakenAt   time.Time     `json:"taken_at"`
+	Offer     *affise.Offer `json:"offer"`
+}
+
+// key returns the version of the snapshot. Offers without UpdatedAt are
+// versioned by the time the snapshot was taken.
+func (s *Snapshot) key() string {
+	if s.UpdatedAt != "" {
+		return s.UpdatedAt
+	}
+
+	return s.TakenAt.UTC().Format(updatedAtLayout)
+}
+
+// SnapshotStore keeps offer snapshots keyed by offer ID and UpdatedAt.
+type SnapshotStore interface {
+	// Save stores the snapshot. It returns false if the snapshot of the offer
+	// with the same UpdatedAt is already stored.
+	Save(ctx context.Context, s *Snapshot) (bool, error)
+	// List returns the snapshots of the offer ordered by UpdatedAt.
+	List(ctx context.Context, offerID int) ([]*Snapshot, error)
+}
+
+// FileStore is a SnapshotStore keeping every snapshot in a JSON file like
+// "{dir}/{offer ID}/2021-01-04T10-00-00.json".
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates a new store in the directory.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+// Save implements SnapshotStore.
+func (fs *FileStore) Save(_ context.Context, s *Snapshot) (bool, error) {
+	dir := filepath.Join(fs.dir, strconv.Itoa(s.OfferID))
+	name := filepath.Join(dir, fileName(s.key()))
+	if _, err := os.Stat(name); err == nil {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return false, err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return false, err
+	}
+
+	// write and rename to not leave partial snapshots
+	tmp, err := ioutil.TempFile(dir, ".snapshot-*")
+	if err != nil {
+		return false, err
+	}
+	_, err = tmp.Write(append(data, '\n'))
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), name)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// List implements SnapshotStore.
+func (fs *FileStore) List(_ context.Context, offerID int) ([]*Snapshot, error) {
+	dir := filepath.Join(fs.dir, strconv.Itoa(offerID))
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	snapshots := make([]*Snapshot, 0, len(files))
+	for _, f := range files {
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") || filepath.Ext(f.Name()) != ".json" {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		s := new(Snapshot)
+		if err := json.Unmarshal(data, s); err != nil {
+			return nil, fmt.Errorf("read snapshot %s err: %w", f.Name(), err)
+		}
+		snapshots = append(snapshots, s)
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].key() < snapshots[j].key()
+	})
+
+	return snapshots, nil
+}
+
+// fileName returns the file name for the UpdatedAt value.
+func fileName(updatedAt string) string {
+	return strings.NewReplacer(" ", "T", ":", "-", "/", "-").Replace(updatedAt) + ".json"
+}
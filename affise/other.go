@@ -34,6 +34,8 @@ type otherListISPResponse struct {
 
 // ListISP gets ISP list.
 func (s *OtherService) ListISP(ctx context.Context, opts *OtherListISPOpts) ([]*ISP, *Response, error) {
+	ctx = withServiceMethod(ctx, "Other.ListISP")
+
 	path := "/3.1/isp"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -57,6 +59,8 @@ type otherListCountriesResponse struct {
 
 // ListCountries gets countries list.
 func (s *OtherService) ListCountries(ctx context.Context) ([]*Country, *Response, error) {
+	ctx = withServiceMethod(ctx, "Other.ListCountries")
+
 	path := "/3.1/countries"
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, false)
@@ -85,6 +89,8 @@ type otherListRegionsResponse struct {
 
 // ListRegions gets region list.
 func (s *OtherService) ListRegions(ctx context.Context, opts *OtherListRegionsOpts) ([]*Region, *Response, error) {
+	ctx = withServiceMethod(ctx, "Other.ListRegions")
+
 	path := "/3.1/regions"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -108,6 +114,8 @@ type otherListConnectionTypesResponse struct {
 
 // ListConnectionTypes gets connection types list.
 func (s *OtherService) ListConnectionTypes(ctx context.Context) ([]string, *Response, error) {
+	ctx = withServiceMethod(ctx, "Other.ListConnectionTypes")
+
 	path := "/3.1/connection-types"
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, false)
@@ -136,6 +144,8 @@ type otherListVendorsResponse struct {
 
 // ListVendors gets vendors list.
 func (s *OtherService) ListVendors(ctx context.Context, opts *OtherListVendorsOpts) ([]string, *Response, error) {
+	ctx = withServiceMethod(ctx, "Other.ListVendors")
+
 	path := "/3.1/vendors"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -159,6 +169,8 @@ type otherListOSResponse struct {
 
 // ListOS gets oses list.
 func (s *OtherService) ListOS(ctx context.Context) (map[string]string, *Response, error) {
+	ctx = withServiceMethod(ctx, "Other.ListOS")
+
 	path := "/3.1/oses"
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, false)
@@ -182,6 +194,8 @@ type otherListOSVersionsResponse struct {
 
 // ListOSVersions gets os versions list.
 func (s *OtherService) ListOSVersions(ctx context.Context, os string) ([]string, *Response, error) {
+	ctx = withServiceMethod(ctx, "Other.ListOSVersions")
+
 	path := fmt.Sprintf("/3.1/oses/%s", os)
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil, false)
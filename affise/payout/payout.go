@@ -0,0 +1,304 @@
+// Package payout calculates expected conversion revenue and payouts locally
+// from offer payments and commission tiers.
+//
+// Payment amounts follow the payment type: fixed amounts are used as is,
+// percent amounts are percents of the conversion sum, and mixed payments
+// have a fixed revenue (Payment.Total) and a payout (Payment.Revenue) in
+// percents of the sum.
+//
+// A reached commission tier modifies the payout, the revenue or both
+// depending on its modifier payment type (payout, total, payout_and_total)
+// after the payment type is applied, so percent amounts are modified as
+// money amounts:
+//
+//	by_fix      amount + value
+//	by_percent  amount increased by value percents
+//	to_fix      value
+//	to_percent  value percents of the conversion sum, whatever the payment type
+package payout
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/clobucks/go-sdk/affise"
+)
+
+// ErrNoPayment is returned when no payment of the offer matches the conversion.
+var ErrNoPayment = errors.New("payout: no matching payment")
+
+// Conversion describes a conversion to calculate.
+type Conversion struct {
+	Country   string   // Country ISO code
+	City      int      // City ID
+	Device    string   // Device type, e.g. mobile
+	OS        string   // OS name
+	Goal      string   // Goal value, e.g. 1
+	PartnerID int      // Partner ID
+	Status    string   // Conversion status (confirmed, pending, declined, not_found, hold)
+	Sum       float64  // Conversion sum for percent payments
+	Progress  Progress // Progress of commission tiers
+}
+
+// Progress is the progress of commission tiers in their timeframe.
+type Progress struct {
+	Conversions int     // Conversions made, for tiers of the conversions type
+	Budget      float64 // Budget spent, for tiers of the budget type
+}
+
+// FromConversion returns the calculation input of the conversion returned by Affise.
+func FromConversion(c *affise.Conversion, progress Progress) *Conversion {
+	return &Conversion{
+		Country:   c.Country,
+		City:      c.CityID,
+		Device:    c.DeviceType,
+		OS:        c.OS,
+		Goal:      c.Goal,
+		PartnerID: int(c.AffiliateID),
+		Status:    c.Status,
+		Sum:       float64(c.Sum),
+		Progress:  progress,
+	}
+}
+
+// Result is a calculated conversion amount.
+type Result struct {
+	Revenue  float64                // Amount charged to the advertiser (Payment.Total)
+	Payouts  float64                // Amount paid to the partner (Payment.Revenue)
+	Currency string                 // Currency of the payment
+	Payment  *affise.Payment        // Matched payment
+	Personal bool                   // The payment is one of the partner payments
+	Tier     *affise.CommissionTier // Applied commission tier, nil if none
+}
+
+// Matches reports whether the amounts of the conversion returned by Affise
+// equal the result within the tolerance.
+func (r *Result) Matches(c *affise.Conversion, tolerance float64) bool {
+	return math.Abs(float64(c.Revenue)-r.Revenue) <= tolerance &&
+		math.Abs(float64(c.Payouts)-r.Payouts) <= tolerance
+}
+
+// Calculate returns the expected amounts of the conversion. The most specific
+// matching payment is used: partner payments go before general payments,
+// then payments with cities, countries, devices and OSes. The commission
+// tier with the highest reached threshold modifies the amounts.
+func Calculate(offer *affise.Offer, c *Conversion) (*Result, error) {
+	p, personal := matchPayment(offer.PartnerPayments, c, true), true
+	if p == nil {
+		p, personal = matchPayment(offer.Payments, c, false), false
+	}
+	if p == nil {
+		return nil, ErrNoPayment
+	}
+
+	r := &Result{Currency: p.Currency, Payment: p, Personal: personal}
+	r.Revenue, r.Payouts = amounts(p, c.Sum)
+
+	if tier := matchTier(offer.CommissionTiers, c); tier != nil {
+		r.Tier = tier
+		modifyPayouts, modifyRevenue := true, false
+		switch tier.ModifierPaymentType {
+		case "total":
+			modifyPayouts, modifyRevenue = false, true
+		case "payout_and_total":
+			modifyRevenue = true
+		}
+		if modifyPayouts {
+			r.Payouts = modify(tier, r.Payouts, c.Sum)
+		}
+		if modifyRevenue {
+			r.Revenue = modify(tier, r.Revenue, c.Sum)
+		}
+	}
+
+	return r, nil
+}
+
+// amounts returns revenue and payouts of the payment type.
+func amounts(p *affise.Payment, sum float64) (revenue, payouts float64) {
+	revenue, payouts = p.Total, p.Revenue
+	switch p.Type {
+	case "percent":
+		revenue, payouts = sum*revenue/100, sum*payouts/100
+	case "mixed":
+		payouts = sum * payouts / 100
+	}
+
+	return revenue, payouts
+}
+
+// matchPayment returns the most specific payment matching the conversion.
+// Partner payments must list the partner.
+func matchPayment(payments []affise.Payment, c *Conversion, personal bool) *affise.Payment {
+	var (
+		best  *affise.Payment
+		score = -1
+	)
+	for i := range payments {
+		p := &payments[i]
+		if personal && !containsInt(p.Partners, c.PartnerID) {
+			continue
+		}
+		if !matchesPayment(p, c) {
+			continue
+		}
+		if s := specificity(p); s > score {
+			best, score = p, s
+		}
+	}
+
+	return best
+}
+
+func matchesPayment(p *affise.Payment, c *Conversion) bool {
+	if countries := nonEmpty(p.Countries); len(countries) != 0 && containsFold(countries, c.Country) == p.CountryExclude {
+		return false
+	}
+	if len(p.Cities) != 0 && !containsCity(p.Cities, c.City) {
+		return false
+	}
+	if devices := nonEmpty(p.Devices); len(devices) != 0 && !containsFold(devices, c.Device) {
+		return false
+	}
+	if oses := nonEmpty(p.OS); len(oses) != 0 && !containsFold(oses, c.OS) {
+		return false
+	}
+
+	return p.Goal == "" || p.Goal == c.Goal
+}
+
+func specificity(p *affise.Payment) int {
+	score := 0
+	if len(p.Cities) != 0 {
+		score += 16
+	}
+	if len(nonEmpty(p.Countries)) != 0 {
+		if p.CountryExclude {
+			score++
+		} else {
+			score += 8
+		}
+	}
+	if len(nonEmpty(p.Devices)) != 0 {
+		score += 4
+	}
+	if len(nonEmpty(p.OS)) != 0 {
+		score += 4
+	}
+	if p.Goal != "" {
+		score += 2
+	}
+
+	return score
+}
+
+// matchTier returns the reached tier applying to the conversion with the
+// highest threshold.
+func matchTier(tiers []affise.CommissionTier, c *Conversion) *affise.CommissionTier {
+	var (
+		best      *affise.CommissionTier
+		threshold float64
+	)
+	for i := range tiers {
+		t := &tiers[i]
+		goals := t.TargetGoals
+		if len(goals) == 0 {
+			goals = t.Goals
+		}
+		switch {
+		case len(nonEmpty(goals)) != 0 && !contains(goals, c.Goal):
+			continue
+		case t.AffiliateType == "exact" && !containsInt(t.Affiliates, c.PartnerID):
+			continue
+		case len(t.ConversionStatus) != 0 && !containsFold(t.ConversionStatus, c.Status):
+			continue
+		}
+
+		value, err := strconv.ParseFloat(t.Value.String(), 64)
+		if err != nil {
+			continue
+		}
+		progress := float64(c.Progress.Conversions)
+		if t.Type == "budget" {
+			progress = c.Progress.Budget
+		}
+		if progress < value {
+			continue
+		}
+		if best == nil || value > threshold {
+			best, threshold = t, value
+		}
+	}
+
+	return best
+}
+
+// modify applies the tier modifier to the amount.
+func modify(t *affise.CommissionTier, amount, sum float64) float64 {
+	switch t.ModifierType {
+	case "by_fix":
+		return amount + t.ModifierValue
+	case "by_percent":
+		return amount * (1 + t.ModifierValue/100)
+	case "to_fix":
+		return t.ModifierValue
+	case "to_percent":
+		return sum * t.ModifierValue / 100
+	}
+
+	return amount
+}
+
+// nonEmpty drops empty items used to clear lists.
+func nonEmpty(list []string) []string {
+	ret := list[:0:0]
+	for _, v := range list {
+		if v != "" {
+			ret = append(ret, v)
+		}
+	}
+
+	return ret
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsInt(list []int, n int) bool {
+	for _, v := range list {
+		if v == n {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsCity(list []affise.City, id int) bool {
+	for _, c := range list {
+		if c.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,179 @@
+package payout_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/clobucks/go-sdk/affise"
+	"github.com/clobucks/go-sdk/affise/payout"
+)
+
+func TestCalculate(t *testing.T) {
+	t.Parallel()
+
+	offer := &affise.Offer{
+		Payments: []affise.Payment{
+			{Total: 10, Revenue: 8, Currency: "usd", Type: "fixed"},
+			{Countries: []string{"US"}, Goal: "1", Total: 12, Revenue: 10, Currency: "usd", Type: "fixed"},
+			{Countries: []string{"US"}, Cities: []affise.City{{ID: 5}}, Goal: "1", Total: 15, Revenue: 12, Currency: "usd", Type: "fixed"},
+			{Countries: []string{"US", "CA"}, CountryExclude: true, Goal: "2", Total: 20, Revenue: 10, Currency: "eur", Type: "percent"},
+			{Devices: []string{"desktop"}, Goal: "3", Total: 3, Revenue: 50, Currency: "usd", Type: "mixed"},
+			{Devices: []string{"tablet"}, Goal: "4", Total: 1.5, Revenue: 0.75, Currency: "usd", Type: "fixed"},
+		},
+		PartnerPayments: []affise.Payment{
+			{Partners: []int{7}, Countries: []string{"US"}, Total: 12, Revenue: 11, Currency: "usd", Type: "fixed"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		conv     *payout.Conversion
+		revenue  float64
+		payouts  float64
+		personal bool
+	}{
+		{"Default", &payout.Conversion{Country: "DE", Goal: "1"}, 10, 8, false},
+		{"Country", &payout.Conversion{Country: "us", Goal: "1"}, 12, 10, false},
+		{"City", &payout.Conversion{Country: "US", City: 5, Goal: "1"}, 15, 12, false},
+		{"ExcludedCountry", &payout.Conversion{Country: "US", Goal: "2", Sum: 100}, 10, 8, false},
+		{"Percent", &payout.Conversion{Country: "DE", Goal: "2", Sum: 50}, 10, 5, false},
+		{"Mixed", &payout.Conversion{Device: "desktop", Goal: "3", Sum: 40}, 3, 20, false},
+		{"Fraction", &payout.Conversion{Device: "tablet", Goal: "4"}, 1.5, 0.75, false},
+		{"Partner", &payout.Conversion{Country: "US", City: 5, Goal: "1", PartnerID: 7}, 12, 11, true},
+		{"OtherPartner", &payout.Conversion{Country: "US", City: 5, Goal: "1", PartnerID: 8}, 15, 12, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := payout.Calculate(offer, tt.conv)
+			require.NoError(t, err)
+			require.Equal(t, tt.revenue, r.Revenue)
+			require.Equal(t, tt.payouts, r.Payouts)
+			require.Equal(t, tt.personal, r.Personal)
+			require.Nil(t, r.Tier)
+		})
+	}
+
+	t.Run("NoPayment", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := payout.Calculate(&affise.Offer{Payments: offer.Payments[1:2]}, &payout.Conversion{Country: "DE", Goal: "1"})
+		require.True(t, errors.Is(err, payout.ErrNoPayment))
+	})
+}
+
+func TestCalculate_CommissionTiers(t *testing.T) {
+	t.Parallel()
+
+	payments := []affise.Payment{{Total: 10, Revenue: 8, Currency: "usd", Type: "fixed"}}
+	tests := []struct {
+		name    string
+		tiers   []affise.CommissionTier
+		conv    *payout.Conversion
+		revenue float64
+		payouts float64
+	}{
+		{
+			name:    "NotReached",
+			tiers:   []affise.CommissionTier{{Type: "conversions", Value: "100", ModifierType: "by_fix", ModifierValue: 1}},
+			conv:    &payout.Conversion{Progress: payout.Progress{Conversions: 99}},
+			revenue: 10, payouts: 8,
+		},
+		{
+			name: "ByFix",
+			tiers: []affise.CommissionTier{
+				{Type: "conversions", Value: "100", ModifierType: "by_fix", ModifierValue: 1},
+				{Type: "conversions", Value: "200", ModifierType: "by_fix", ModifierValue: 2},
+			},
+			conv:    &payout.Conversion{Progress: payout.Progress{Conversions: 250}},
+			revenue: 10, payouts: 10,
+		},
+		{
+			name:    "ByPercentOfTotal",
+			tiers:   []affise.CommissionTier{{Type: "budget", Value: "1000.5", ModifierType: "by_percent", ModifierValue: 50, ModifierPaymentType: "total"}},
+			conv:    &payout.Conversion{Progress: payout.Progress{Budget: 2000}},
+			revenue: 15, payouts: 8,
+		},
+		{
+			name:    "ToFix",
+			tiers:   []affise.CommissionTier{{Type: "conversions", Value: "1", ModifierType: "to_fix", ModifierValue: 9, ModifierPaymentType: "payout_and_total"}},
+			conv:    &payout.Conversion{Progress: payout.Progress{Conversions: 1}},
+			revenue: 9, payouts: 9,
+		},
+		{
+			name:    "ToPercent",
+			tiers:   []affise.CommissionTier{{Type: "conversions", Value: "1", ModifierType: "to_percent", ModifierValue: 10}},
+			conv:    &payout.Conversion{Sum: 200, Progress: payout.Progress{Conversions: 1}},
+			revenue: 10, payouts: 20,
+		},
+		{
+			name: "Filters",
+			tiers: []affise.CommissionTier{
+				{Type: "conversions", Value: "1", TargetGoals: []string{"2"}, ModifierType: "to_fix", ModifierValue: 1},
+				{Type: "conversions", Value: "1", AffiliateType: "exact", Affiliates: []int{8}, ModifierType: "to_fix", ModifierValue: 2},
+				{Type: "conversions", Value: "1", ConversionStatus: []string{"confirmed"}, ModifierType: "to_fix", ModifierValue: 3},
+			},
+			conv:    &payout.Conversion{Goal: "1", PartnerID: 7, Status: "pending", Progress: payout.Progress{Conversions: 1}},
+			revenue: 10, payouts: 8,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := payout.Calculate(&affise.Offer{Payments: payments, CommissionTiers: tt.tiers}, tt.conv)
+			require.NoError(t, err)
+			require.Equal(t, tt.revenue, r.Revenue)
+			require.Equal(t, tt.payouts, r.Payouts)
+		})
+	}
+}
+
+func TestCalculate_CommissionTiersMixed(t *testing.T) {
+	t.Parallel()
+
+	// revenue 3, payout 50% of the sum
+	payments := []affise.Payment{{Total: 3, Revenue: 50, Currency: "usd", Type: "mixed"}}
+	conv := &payout.Conversion{Sum: 40, Progress: payout.Progress{Conversions: 1}}
+	tests := []struct {
+		name    string
+		tier    affise.CommissionTier
+		revenue float64
+		payouts float64
+	}{
+		{"ByFix", affise.CommissionTier{ModifierType: "by_fix", ModifierValue: 2}, 3, 22},
+		{"ByPercent", affise.CommissionTier{ModifierType: "by_percent", ModifierValue: 10}, 3, 22},
+		{"ToFix", affise.CommissionTier{ModifierType: "to_fix", ModifierValue: 5}, 3, 5},
+		{"ToPercent", affise.CommissionTier{ModifierType: "to_percent", ModifierValue: 60}, 3, 24},
+		{"ToPercentOfTotal", affise.CommissionTier{ModifierType: "to_percent", ModifierValue: 10, ModifierPaymentType: "total"}, 4, 20},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.tier.Type, tt.tier.Value = "conversions", "1"
+			r, err := payout.Calculate(&affise.Offer{Payments: payments, CommissionTiers: []affise.CommissionTier{tt.tier}}, conv)
+			require.NoError(t, err)
+			require.InDelta(t, tt.revenue, r.Revenue, 1e-9)
+			require.InDelta(t, tt.payouts, r.Payouts, 1e-9)
+		})
+	}
+}
+
+func TestResult_Matches(t *testing.T) {
+	t.Parallel()
+
+	conv := &affise.Conversion{Country: "US", Goal: "1", Status: "confirmed", AffiliateID: 7, Revenue: 12, Payouts: 10.004}
+	r, err := payout.Calculate(&affise.Offer{
+		Payments: []affise.Payment{{Countries: []string{"US"}, Total: 12, Revenue: 10, Currency: "usd", Type: "fixed"}},
+	}, payout.FromConversion(conv, payout.Progress{}))
+	require.NoError(t, err)
+	require.True(t, r.Matches(conv, 0.01))
+	require.False(t, r.Matches(conv, 0.001))
+}
@@ -0,0 +1,369 @@
+package postback
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/clobucks/go-sdk/affise"
+)
+
+// maxErrorLen limits the response bodies used to group failures.
+const maxErrorLen = 200
+
+// DiagnoseOpts specifies the postback logs analysed by Diagnose.
+type DiagnoseOpts struct {
+	DateFrom string   // REQUIRED (Available: YYYY-MM-DD)
+	DateTo   string   // REQUIRED (Available: YYYY-MM-DD)
+	Offer    []int    // Offers ID’s
+	Partner  []int    // Partners ID’s
+	Supplier []string // Advertiser ID’s of server postbacks
+	Timezone string   // Timezone name. Example: “Europe/Berlin” (Default: Timezone of your platform)
+	Limit    int      // Page size (Default: 100)
+}
+
+// Diagnose pages through the affiliate postbacks sent in the period and the
+// server postbacks they were triggered by, and returns the delivery report.
+func Diagnose(ctx context.Context, client *affise.Client, opts *DiagnoseOpts) (*Report, error) {
+	if opts == nil {
+		opts = &DiagnoseOpts{}
+	}
+
+	sent, err := client.Statistic.AffiliatePostbacksIter(ctx, &affise.StatisticAffiliatePostbacksOpts{
+		DateFrom: opts.DateFrom,
+		DateTo:   opts.DateTo,
+		Offer:    opts.Offer,
+		Partner:  opts.Partner,
+		Timezone: opts.Timezone,
+		Limit:    opts.Limit,
+	}).All(0)
+	if err != nil {
+		return nil, fmt.Errorf("postback: affiliate postbacks: %w", err)
+	}
+
+	received, err := client.Statistic.ServerPostbacksIter(ctx, &affise.StatisticServerPostbacksOpts{
+		DateFrom: opts.DateFrom,
+		DateTo:   opts.DateTo,
+		Offer:    opts.Offer,
+		Partner:  opts.Partner,
+		Supplier: opts.Supplier,
+		Timezone: opts.Timezone,
+		Limit:    opts.Limit,
+	}).All(0)
+	if err != nil {
+		return nil, fmt.Errorf("postback: server postbacks: %w", err)
+	}
+
+	r := Analyze(sent, received)
+	r.DateFrom, r.DateTo = opts.DateFrom, opts.DateTo
+
+	return r, nil
+}
+
+// Report describes the delivery of affiliate postbacks.
+type Report struct {
+	DateFrom   string            `json:"date_from,omitempty"`
+	DateTo     string            `json:"date_to,omitempty"`
+	Total      int               `json:"total"`
+	Failed     int               `json:"failed"`
+	Affiliates []*AffiliateStats `json:"affiliates"` // Sorted by success rate, lowest first
+	Failures   []*FailureGroup   `json:"failures"`   // Sorted by count, highest first
+}
+
+// AffiliateStats describes the delivery of postbacks to an affiliate.
+//
+// Latency is the time from the server postback of the conversion to the
+// affiliate postback; it is known only for postbacks matched by conversion ID.
+type AffiliateStats struct {
+	AffiliateID   uint64        `json:"affiliate_id"`
+	Total         int           `json:"total"`
+	Delivered     int           `json:"delivered"`
+	Failed        int           `json:"failed"`
+	SuccessRate   float64       `json:"success_rate"` // 0..1
+	Matched       int           `json:"matched"`      // Postbacks with known latency
+	MedianLatency time.Duration `json:"median_latency_ns"`
+	P95Latency    time.Duration `json:"p95_latency_ns"`
+	MaxLatency    time.Duration `json:"max_latency_ns"`
+	LastFailure   time.Time     `json:"last_failure"`
+}
+
+// FailureGroup is a set of failed postbacks to the same host with the same
+// HTTP code and response.
+type FailureGroup struct {
+	AffiliateID uint64    `json:"affiliate_id"`
+	Host        string    `json:"host"`
+	HTTPCode    int       `json:"http_code"` // 0 if the host did not respond
+	Error       string    `json:"error"`     // Response body, shortened
+	Count       int       `json:"count"`
+	First       time.Time `json:"first"`
+	Last        time.Time `json:"last"`
+	ExampleURL  string    `json:"example_url"`
+}
+
+// Delivered reports whether the host accepted the postback.
+func Delivered(p *affise.StatPostback) bool {
+	return p.HTTPCode >= 200 && p.HTTPCode < 300
+}
+
+// Analyze returns the report of the affiliate postbacks. Server postbacks
+// are used to compute the latency and may be nil.
+func Analyze(sent, received []*affise.StatPostback) *Report {
+	receivedAt := make(map[string][]time.Time)
+	for _, p := range received {
+		if id := conversionID(p); id != "" {
+			receivedAt[id] = append(receivedAt[id], postbackTime(p))
+		}
+	}
+	for _, times := range receivedAt {
+		sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	}
+
+	r := &Report{}
+	affiliates := make(map[uint64]*AffiliateStats)
+	latencies := make(map[uint64][]time.Duration)
+	failures := make(map[failureKey]*FailureGroup)
+	for _, p := range sent {
+		a, ok := affiliates[p.AffiliateID]
+		if !ok {
+			a = &AffiliateStats{AffiliateID: p.AffiliateID}
+			affiliates[p.AffiliateID] = a
+		}
+
+		at := postbackTime(p)
+		r.Total++
+		a.Total++
+		if lat, ok := latency(receivedAt[p.LeadID], at); ok {
+			a.Matched++
+			latencies[p.AffiliateID] = append(latencies[p.AffiliateID], lat)
+		}
+		if Delivered(p) {
+			a.Delivered++
+
+			continue
+		}
+
+		r.Failed++
+		a.Failed++
+		if at.After(a.LastFailure) {
+			a.LastFailure = at
+		}
+
+		key := failureKey{affiliateID: p.AffiliateID, host: host(p.PostbackURL), httpCode: p.HTTPCode, err: shorten(p.Response)}
+		g, ok := failures[key]
+		if !ok {
+			g = &FailureGroup{
+				AffiliateID: key.affiliateID,
+				Host:        key.host,
+				HTTPCode:    key.httpCode,
+				Error:       key.err,
+				First:       at,
+				Last:        at,
+				ExampleURL:  p.PostbackURL,
+			}
+			failures[key] = g
+		}
+		g.Count++
+		if at.Before(g.First) {
+			g.First = at
+		}
+		if at.After(g.Last) {
+			g.Last = at
+		}
+	}
+
+	for id, a := range affiliates {
+		a.SuccessRate = float64(a.Delivered) / float64(a.Total)
+		if l := latencies[id]; len(l) != 0 {
+			sort.Slice(l, func(i, j int) bool { return l[i] < l[j] })
+			a.MedianLatency = percentile(l, 50)
+			a.P95Latency = percentile(l, 95)
+			a.MaxLatency = l[len(l)-1]
+		}
+		r.Affiliates = append(r.Affiliates, a)
+	}
+	sort.Slice(r.Affiliates, func(i, j int) bool {
+		a, b := r.Affiliates[i], r.Affiliates[j]
+		if a.SuccessRate != b.SuccessRate {
+			return a.SuccessRate < b.SuccessRate
+		}
+
+		return a.AffiliateID < b.AffiliateID
+	})
+
+	for _, g := range failures {
+		r.Failures = append(r.Failures, g)
+	}
+	sort.Slice(r.Failures, func(i, j int) bool {
+		a, b := r.Failures[i], r.Failures[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.AffiliateID != b.AffiliateID {
+			return a.AffiliateID < b.AffiliateID
+		}
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		if a.HTTPCode != b.HTTPCode {
+			return a.HTTPCode < b.HTTPCode
+		}
+
+		return a.Error < b.Error
+	})
+
+	return r
+}
+
+// Broken returns the affiliates with at least minTotal postbacks and a
+// success rate below minRate.
+func (r *Report) Broken(minRate float64, minTotal int) []*AffiliateStats {
+	var ret []*AffiliateStats
+	for _, a := range r.Affiliates {
+		if a.Total >= minTotal && a.SuccessRate < minRate {
+			ret = append(ret, a)
+		}
+	}
+
+	return ret
+}
+
+// WriteJSON writes the report as JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(r)
+}
+
+// WriteCSV writes a row per affiliate.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{
+		"affiliate_id", "total", "delivered", "failed", "success_rate",
+		"matched", "median_latency", "p95_latency", "max_latency", "last_failure",
+	})
+	for _, a := range r.Affiliates {
+		_ = cw.Write([]string{
+			strconv.FormatUint(a.AffiliateID, 10),
+			strconv.Itoa(a.Total),
+			strconv.Itoa(a.Delivered),
+			strconv.Itoa(a.Failed),
+			strconv.FormatFloat(a.SuccessRate, 'f', 4, 64),
+			strconv.Itoa(a.Matched),
+			a.MedianLatency.String(),
+			a.P95Latency.String(),
+			a.MaxLatency.String(),
+			formatTime(a.LastFailure),
+		})
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// WriteFailuresCSV writes a row per failure group.
+func (r *Report) WriteFailuresCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"affiliate_id", "host", "http_code", "error", "count", "first", "last", "example_url"})
+	for _, g := range r.Failures {
+		_ = cw.Write([]string{
+			strconv.FormatUint(g.AffiliateID, 10),
+			g.Host,
+			strconv.Itoa(g.HTTPCode),
+			g.Error,
+			strconv.Itoa(g.Count),
+			formatTime(g.First),
+			formatTime(g.Last),
+			g.ExampleURL,
+		})
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+type failureKey struct {
+	affiliateID uint64
+	host        string
+	httpCode    int
+	err         string
+}
+
+func postbackTime(p *affise.StatPostback) time.Time {
+	return time.Unix(int64(p.Date.Sec), int64(p.Date.Usec)*int64(time.Microsecond)).UTC()
+}
+
+// conversionID returns the ID of the conversion created by the server postback.
+func conversionID(p *affise.StatPostback) string {
+	if p.Track == nil {
+		return ""
+	}
+
+	switch id := p.Track.ConversionID.(type) {
+	case nil:
+		return ""
+	case string:
+		return id
+	case json.Number:
+		return id.String()
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64)
+	default:
+		return fmt.Sprint(id)
+	}
+}
+
+// latency returns the time since the last server postback received before sentAt.
+func latency(received []time.Time, sentAt time.Time) (time.Duration, bool) {
+	i := sort.Search(len(received), func(i int) bool { return received[i].After(sentAt) })
+	if i == 0 {
+		return 0, false
+	}
+
+	return sentAt.Sub(received[i-1]), true
+}
+
+func percentile(sorted []time.Duration, p int) time.Duration {
+	i := (len(sorted)*p+99)/100 - 1
+	if i < 0 {
+		i = 0
+	}
+
+	return sorted[i]
+}
+
+func host(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+
+	return u.Hostname()
+}
+
+// shorten collapses whitespace of the response so similar errors are grouped.
+func shorten(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	for i := range s {
+		if i >= maxErrorLen {
+			return s[:i] + "..."
+		}
+	}
+
+	return s
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
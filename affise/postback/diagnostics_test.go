@@ -0,0 +1,163 @@
+package postback_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/clobucks/go-sdk/affise"
+	"github.com/clobucks/go-sdk/affise/affisetest"
+	"github.com/clobucks/go-sdk/affise/postback"
+)
+
+type logEntry map[string]interface{}
+
+func sentLog(pid int, lead string, sec int, code int, postbackURL, response string) logEntry {
+	return logEntry{
+		"pid":          pid,
+		"lead_id":      lead,
+		"date":         map[string]int{"sec": sec},
+		"http_code":    code,
+		"postback_url": postbackURL,
+		"response":     response,
+	}
+}
+
+func receivedLog(conversionID string, sec int) logEntry {
+	return logEntry{
+		"date":  map[string]int{"sec": sec},
+		"track": map[string]interface{}{"conversion_id": conversionID},
+	}
+}
+
+func TestDiagnose(t *testing.T) {
+	t.Parallel()
+
+	sent := []logEntry{
+		sentLog(1, "c1", 1000, 200, "https://a.example.com/pb?cid=1", "ok"),
+		sentLog(1, "c2", 1010, 200, "https://a.example.com/pb?cid=2", "ok"),
+		sentLog(2, "c3", 1020, 500, "https://b.example.com/pb?cid=3", "Internal\n  error"),
+		sentLog(2, "c4", 1030, 500, "https://b.example.com/pb?cid=4", "Internal error"),
+		sentLog(2, "c5", 1040, 0, "https://b.example.com/pb?cid=5", ""),
+		sentLog(2, "c6", 1050, 204, "https://b.example.com/pb?cid=6", ""),
+	}
+	received := []logEntry{
+		receivedLog("c1", 998),
+		receivedLog("c2", 1000),
+		receivedLog("c2", 1011),
+		receivedLog("c6", 1049),
+	}
+
+	var pages []string
+	server := affisetest.NewServer(t)
+	server.HandleFunc(http.MethodGet, "/3.0/stats/affiliatepostbacks", func(w http.ResponseWriter, r *http.Request) {
+		pages = append(pages, r.URL.Query().Get("page"))
+		v, page := sent[:4], 1
+		if r.URL.Query().Get("page") == "2" {
+			v, page = sent[4:], 2
+		}
+		affisetest.WriteJSON(w, http.StatusOK, map[string]interface{}{
+			"status":     1,
+			"postbacks":  v,
+			"pagination": map[string]int{"page": page, "per_page": 4, "total_count": len(sent)},
+		})
+	})
+	server.HandleJSON(http.MethodGet, "/3.0/stats/serverpostbacks", http.StatusOK, map[string]interface{}{
+		"status":    1,
+		"postbacks": received,
+	})
+
+	r, err := postback.Diagnose(context.Background(), server.Client, &postback.DiagnoseOpts{
+		DateFrom: "2021-01-01",
+		DateTo:   "2021-01-02",
+		Limit:    4,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"1", "2"}, pages)
+	require.Equal(t, 6, r.Total)
+	require.Equal(t, 3, r.Failed)
+
+	require.Len(t, r.Affiliates, 2)
+	bad, good := r.Affiliates[0], r.Affiliates[1]
+	require.Equal(t, uint64(2), bad.AffiliateID)
+	require.Equal(t, 4, bad.Total)
+	require.Equal(t, 1, bad.Delivered)
+	require.Equal(t, 0.25, bad.SuccessRate)
+	require.Equal(t, 1, bad.Matched)
+	require.Equal(t, time.Second, bad.MaxLatency)
+	require.Equal(t, time.Unix(1040, 0).UTC(), bad.LastFailure)
+
+	require.Equal(t, 1.0, good.SuccessRate)
+	require.Equal(t, 2, good.Matched)
+	require.Equal(t, 2*time.Second, good.MedianLatency)
+	require.Equal(t, 10*time.Second, good.P95Latency)
+
+	require.Len(t, r.Failures, 2)
+	require.Equal(t, &postback.FailureGroup{
+		AffiliateID: 2,
+		Host:        "b.example.com",
+		HTTPCode:    500,
+		Error:       "Internal error",
+		Count:       2,
+		First:       time.Unix(1020, 0).UTC(),
+		Last:        time.Unix(1030, 0).UTC(),
+		ExampleURL:  "https://b.example.com/pb?cid=3",
+	}, r.Failures[0])
+	require.Equal(t, 0, r.Failures[1].HTTPCode)
+
+	require.Equal(t, []*postback.AffiliateStats{bad}, r.Broken(0.9, 2))
+	require.Empty(t, r.Broken(0.9, 5))
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteCSV(&buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	require.Equal(t, "2,4,1,3,0.2500,1,1s,1s,1s,1970-01-01T00:17:20Z", lines[1])
+
+	buf.Reset()
+	require.NoError(t, r.WriteFailuresCSV(&buf))
+	require.Contains(t, buf.String(), "2,b.example.com,500,Internal error,2,")
+
+	buf.Reset()
+	require.NoError(t, r.WriteJSON(&buf))
+	var decoded postback.Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, "2021-01-01", decoded.DateFrom)
+	require.Equal(t, r.Failures, decoded.Failures)
+}
+
+func TestAnalyze_NumericConversionID(t *testing.T) {
+	t.Parallel()
+
+	at := func(p *affise.StatPostback, sec int) *affise.StatPostback {
+		p.Date.Sec = sec
+
+		return p
+	}
+	sent := []*affise.StatPostback{
+		at(&affise.StatPostback{AffiliateID: 1, LeadID: "1000000", HTTPCode: 200}, 1010),
+		at(&affise.StatPostback{AffiliateID: 1, LeadID: "12345678901234567", HTTPCode: 200}, 1010),
+	}
+	received := []*affise.StatPostback{
+		at(&affise.StatPostback{Track: &affise.Track{ConversionID: float64(1000000)}}, 1000),
+		at(&affise.StatPostback{Track: &affise.Track{ConversionID: json.Number("12345678901234567")}}, 1005),
+	}
+
+	r := postback.Analyze(sent, received)
+	require.Equal(t, 2, r.Affiliates[0].Matched)
+	require.Equal(t, 10*time.Second, r.Affiliates[0].MaxLatency)
+}
+
+func TestDiagnose_NilOpts(t *testing.T) {
+	t.Parallel()
+
+	server := affisetest.NewServer(t)
+	_, err := postback.Diagnose(context.Background(), server.Client, nil)
+	require.NoError(t, err)
+}
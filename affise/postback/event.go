@@ -0,0 +1,179 @@
+// Package postback receives Affise server postbacks, checks postback URLs
+// and reports and replays postbacks delivered to affiliates.
+//
+// Handler parses postback requests into typed events, verifies them,
+// drops repeated deliveries and passes the events to a callback.
+// Template validates postback URLs with macros before they are saved and
+// previews them for a conversion. Diagnose groups failed affiliate
+// postbacks and computes the delivery success rate and latency per affiliate.
+// Replayer sends failed affiliate postbacks again.
+package postback
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidPostback is returned for postbacks that cannot be parsed.
+var ErrInvalidPostback = errors.New("postback: invalid postback")
+
+// Field is an event field filled from a postback parameter.
+type Field string
+
+// Event fields named after the Affise postback macros.
+const (
+	FieldClickID      Field = "clickid"
+	FieldActionID     Field = "action_id"
+	FieldConversionID Field = "conversion_id"
+	FieldOfferID      Field = "offer_id"
+	FieldAffiliateID  Field = "pid"
+	FieldGoal         Field = "goal"
+	FieldStatus       Field = "status"
+	FieldSum          Field = "sum"
+	FieldRevenue      Field = "revenue"
+	FieldPayouts      Field = "payouts"
+	FieldCurrency     Field = "currency"
+	FieldIP           Field = "ip"
+	FieldCountry      Field = "country"
+)
+
+// SubField returns the field of the sub parameter n (1..8), e.g. "sub1".
+func SubField(n int) Field {
+	return Field("sub" + strconv.Itoa(n))
+}
+
+// CustomField returns the field of the custom field n (1..7), e.g. "custom_field1".
+func CustomField(n int) Field {
+	return Field("custom_field" + strconv.Itoa(n))
+}
+
+// Macros maps event fields to the names of postback parameters carrying
+// them. Fields without a parameter are left empty.
+type Macros map[Field]string
+
+// DefaultMacros returns macros where every parameter is named after its
+// field, as in "https://example.com/postback?clickid={clickid}&status={status}".
+func DefaultMacros() Macros {
+	m := Macros{}
+	for _, f := range []Field{
+		FieldClickID, FieldActionID, FieldConversionID, FieldOfferID, FieldAffiliateID, FieldGoal,
+		FieldStatus, FieldSum, FieldRevenue, FieldPayouts, FieldCurrency, FieldIP, FieldCountry,
+	} {
+		m[f] = string(f)
+	}
+	for i := 1; i <= 8; i++ {
+		m[SubField(i)] = string(SubField(i))
+	}
+	for i := 1; i <= 7; i++ {
+		m[CustomField(i)] = string(CustomField(i))
+	}
+
+	return m
+}
+
+// Event is a conversion reported by a postback. Fields mirror affise.Conversion.
+type Event struct {
+	ClickID      string
+	ActionID     string
+	ConversionID string
+	OfferID      uint64
+	AffiliateID  uint64
+	Goal         string
+	Status       string // confirmed, pending, declined, not_found or hold
+	Sum          float64
+	Revenue      float64
+	Payouts      float64
+	Currency     string
+	IP           string
+	Country      string
+	Subs         [8]string  // sub1..sub8
+	CustomFields [7]string  // custom_field1..custom_field7
+	Params       url.Values // All received parameters
+	ReceivedAt   time.Time
+}
+
+// Sub returns the value of the sub parameter n (1..8).
+func (e *Event) Sub(n int) string {
+	if n < 1 || n > len(e.Subs) {
+		return ""
+	}
+
+	return e.Subs[n-1]
+}
+
+var statuses = map[string]string{
+	"1": "confirmed",
+	"2": "pending",
+	"3": "declined",
+	"4": "not_found",
+	"5": "hold",
+}
+
+// NormalizeStatus returns the status name for numeric statuses like "1".
+func NormalizeStatus(status string) string {
+	if s, ok := statuses[status]; ok {
+		return s
+	}
+
+	return strings.ToLower(status)
+}
+
+// Parse returns the event from postback parameters named by the macros.
+// Unfilled macros like "{sub1}" are treated as empty values.
+func (m Macros) Parse(params url.Values) (*Event, error) {
+	get := func(f Field) string {
+		name, ok := m[f]
+		if !ok {
+			return ""
+		}
+		v := strings.TrimSpace(params.Get(name))
+		if strings.HasPrefix(v, "{") && strings.HasSuffix(v, "}") {
+			return ""
+		}
+
+		return v
+	}
+
+	e := &Event{
+		ClickID:      get(FieldClickID),
+		ActionID:     get(FieldActionID),
+		ConversionID: get(FieldConversionID),
+		Goal:         get(FieldGoal),
+		Status:       NormalizeStatus(get(FieldStatus)),
+		Currency:     get(FieldCurrency),
+		IP:           get(FieldIP),
+		Country:      get(FieldCountry),
+		Params:       params,
+	}
+	for i := range e.Subs {
+		e.Subs[i] = get(SubField(i + 1))
+	}
+	for i := range e.CustomFields {
+		e.CustomFields[i] = get(CustomField(i + 1))
+	}
+
+	var err error
+	for f, dst := range map[Field]*uint64{FieldOfferID: &e.OfferID, FieldAffiliateID: &e.AffiliateID} {
+		if v := get(f); v != "" {
+			if *dst, err = strconv.ParseUint(v, 10, 64); err != nil {
+				return nil, fmt.Errorf("%w: %s %q is not an ID", ErrInvalidPostback, m[f], v)
+			}
+		}
+	}
+	for f, dst := range map[Field]*float64{FieldSum: &e.Sum, FieldRevenue: &e.Revenue, FieldPayouts: &e.Payouts} {
+		if v := get(f); v != "" {
+			if *dst, err = strconv.ParseFloat(v, 64); err != nil {
+				return nil, fmt.Errorf("%w: %s %q is not a number", ErrInvalidPostback, m[f], v)
+			}
+		}
+	}
+	if e.ClickID == "" && e.ActionID == "" {
+		return nil, fmt.Errorf("%w: no click ID and action ID", ErrInvalidPostback)
+	}
+
+	return e, nil
+}
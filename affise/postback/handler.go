@@ -0,0 +1,391 @@
+package postback
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxBodySize limits postback bodies.
+const maxBodySize = 1 << 20
+
+// HandlerFunc processes an event. An error makes the handler answer with
+// status 500 so the postback is sent again.
+type HandlerFunc func(ctx context.Context, e *Event) error
+
+// An Option is used to configure a Handler.
+type Option func(*Handler) error
+
+// WithMacros sets the parameter names of the event fields (Default: DefaultMacros).
+func WithMacros(m Macros) Option {
+	return func(h *Handler) error {
+		h.macros = m
+
+		return nil
+	}
+}
+
+// WithSecret requires the parameter to carry the shared secret, e.g. a
+// postback URL like "https://example.com/postback?clickid={clickid}&secret=s3cr3t".
+func WithSecret(param, secret string) Option {
+	return func(h *Handler) error {
+		if param == "" || secret == "" {
+			return errors.New("postback: empty secret")
+		}
+		h.secretParam, h.secret = param, secret
+
+		return nil
+	}
+}
+
+// WithAllowedIPs accepts postbacks only from the addresses and networks
+// like "10.0.0.0/8".
+func WithAllowedIPs(ips ...string) Option {
+	return func(h *Handler) error {
+		for _, ip := range ips {
+			if !strings.Contains(ip, "/") {
+				if strings.Contains(ip, ":") {
+					ip += "/128"
+				} else {
+					ip += "/32"
+				}
+			}
+			_, network, err := net.ParseCIDR(ip)
+			if err != nil {
+				return fmt.Errorf("postback: invalid allowed IP %q: %w", ip, err)
+			}
+			h.allowed = append(h.allowed, network)
+		}
+
+		return nil
+	}
+}
+
+// WithRealIPHeader takes the client IP from the first address of the header,
+// e.g. X-Forwarded-For, when the handler is behind a trusted proxy.
+func WithRealIPHeader(name string) Option {
+	return func(h *Handler) error {
+		h.realIPHeader = name
+
+		return nil
+	}
+}
+
+// WithStore drops events with the same idempotency key as already processed
+// events. The key is the action ID and the status, so status changes of a
+// conversion are delivered. Events without action ID are not deduplicated.
+// The key is recorded before the event is processed and removed if the
+// callback fails. Repeated events arriving while the first one is processed
+// are answered with 409, so they are sent again instead of being dropped.
+func WithStore(s Store) Option {
+	return func(h *Handler) error {
+		h.store = s
+
+		return nil
+	}
+}
+
+// WithIdempotencyKey replaces the idempotency key of WithStore. Events with
+// an empty key are not deduplicated.
+func WithIdempotencyKey(key func(e *Event) string) Option {
+	return func(h *Handler) error {
+		h.key = key
+
+		return nil
+	}
+}
+
+// WithErrorLog sets the function logging rejected postbacks and failures.
+func WithErrorLog(log func(r *http.Request, err error)) Option {
+	return func(h *Handler) error {
+		h.errorLog = log
+
+		return nil
+	}
+}
+
+// Handler is an http.Handler receiving postbacks sent with GET query
+// parameters, POST forms or POST JSON objects.
+//
+// It answers 200 for processed and repeated events, 400 for invalid
+// postbacks, 403 for rejected senders, 409 for repeated events still being
+// processed and 500 if the callback fails.
+type Handler struct {
+	fn           HandlerFunc
+	macros       Macros
+	secretParam  string
+	secret       string
+	allowed      []*net.IPNet
+	realIPHeader string
+	store        Store
+	key          func(e *Event) string
+	errorLog     func(r *http.Request, err error)
+
+	mu       sync.Mutex
+	inflight map[string]struct{} // keys of events being processed
+}
+
+// NewHandler creates a new handler passing events to fn.
+func NewHandler(fn HandlerFunc, options ...Option) (*Handler, error) {
+	h := &Handler{
+		fn:       fn,
+		macros:   DefaultMacros(),
+		key:      DefaultKey,
+		inflight: make(map[string]struct{}),
+	}
+	for _, option := range options {
+		if err := option(h); err != nil {
+			return nil, err
+		}
+	}
+
+	return h, nil
+}
+
+// DefaultKey returns the action ID and the status of the event.
+func DefaultKey(e *Event) string {
+	if e.ActionID == "" {
+		return ""
+	}
+
+	return e.ActionID + "/" + e.Status
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		h.fail(w, r, http.StatusMethodNotAllowed, fmt.Errorf("postback: method %s is not allowed", r.Method))
+
+		return
+	}
+	if !h.allowedIP(r) {
+		h.fail(w, r, http.StatusForbidden, fmt.Errorf("postback: IP %q is not allowed", h.clientIP(r)))
+
+		return
+	}
+
+	params, err := readParams(w, r)
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, err)
+
+		return
+	}
+	if h.secret != "" && subtle.ConstantTimeCompare([]byte(params.Get(h.secretParam)), []byte(h.secret)) != 1 {
+		h.fail(w, r, http.StatusForbidden, errors.New("postback: invalid secret"))
+
+		return
+	}
+
+	e, err := h.macros.Parse(params)
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, err)
+
+		return
+	}
+	e.ReceivedAt = time.Now()
+	if e.IP == "" {
+		e.IP = h.clientIP(r)
+	}
+
+	ctx := r.Context()
+	key := ""
+	if h.store != nil {
+		key = h.key(e)
+	}
+	if key != "" {
+		if !h.begin(key) {
+			h.fail(w, r, http.StatusConflict, fmt.Errorf("postback: event %q is being processed", key))
+
+			return
+		}
+		defer h.end(key)
+
+		added, err := h.store.Add(ctx, key)
+		if err != nil {
+			h.fail(w, r, http.StatusInternalServerError, fmt.Errorf("postback: store err: %w", err))
+
+			return
+		}
+		if !added {
+			writeText(w, http.StatusOK, "duplicate")
+
+			return
+		}
+	}
+
+	if err := h.fn(ctx, e); err != nil {
+		if key != "" {
+			// let the next delivery process the event
+			_ = h.store.Remove(ctx, key)
+		}
+		h.fail(w, r, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	writeText(w, http.StatusOK, "ok")
+}
+
+// begin marks the key as being processed. It returns false if it already is.
+func (h *Handler) begin(key string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.inflight[key]; ok {
+		return false
+	}
+	h.inflight[key] = struct{}{}
+
+	return true
+}
+
+func (h *Handler) end(key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.inflight, key)
+}
+
+func (h *Handler) fail(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
+	if h.errorLog != nil {
+		h.errorLog(r, err)
+	}
+	writeText(w, statusCode, http.StatusText(statusCode))
+}
+
+func (h *Handler) allowedIP(r *http.Request) bool {
+	if len(h.allowed) == 0 {
+		return true
+	}
+
+	ip := net.ParseIP(h.clientIP(r))
+	if ip == nil {
+		return false
+	}
+	for _, network := range h.allowed {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *Handler) clientIP(r *http.Request) string {
+	if h.realIPHeader != "" {
+		if v := r.Header.Get(h.realIPHeader); v != "" {
+			return strings.TrimSpace(strings.Split(v, ",")[0])
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// readParams returns the query parameters merged with the form or JSON body.
+func readParams(w http.ResponseWriter, r *http.Request) (url.Values, error) {
+	params := r.URL.Query()
+	if r.Method != http.MethodPost {
+		return params, nil
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		dec := json.NewDecoder(r.Body)
+		dec.UseNumber()
+		var body map[string]interface{}
+		if err := dec.Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPostback, err)
+		}
+		for k, v := range body {
+			if v != nil {
+				params.Set(k, fmt.Sprint(v))
+			}
+		}
+
+		return params, nil
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPostback, err)
+	}
+	for k, v := range r.PostForm {
+		params[k] = v
+	}
+
+	return params, nil
+}
+
+func writeText(w http.ResponseWriter, statusCode int, text string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(statusCode)
+	_, _ = io.WriteString(w, text+"\n")
+}
+
+// Store records idempotency keys of processed events.
+type Store interface {
+	// Add records the key. It returns false if the key is already recorded.
+	Add(ctx context.Context, key string) (bool, error)
+	// Remove forgets the key of an event that failed to process.
+	Remove(ctx context.Context, key string) error
+}
+
+// MemoryStore is an in-memory Store. Keys expire after the TTL.
+type MemoryStore struct {
+	ttl time.Duration
+
+	mu    sync.Mutex
+	keys  map[string]time.Time
+	swept time.Time
+}
+
+// NewMemoryStore creates a new store keeping keys for the TTL. Keys are kept
+// forever if ttl is zero.
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{ttl: ttl, keys: make(map[string]time.Time)}
+}
+
+// Add implements Store.
+func (s *MemoryStore) Add(_ context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.ttl > 0 && now.Sub(s.swept) >= s.ttl {
+		for k, added := range s.keys {
+			if now.Sub(added) >= s.ttl {
+				delete(s.keys, k)
+			}
+		}
+		s.swept = now
+	}
+	if added, ok := s.keys[key]; ok && (s.ttl == 0 || now.Sub(added) < s.ttl) {
+		return false, nil
+	}
+	s.keys[key] = now
+
+	return true, nil
+}
+
+// Remove implements Store.
+func (s *MemoryStore) Remove(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.keys, key)
+
+	return nil
+}
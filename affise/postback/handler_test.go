@@ -0,0 +1,313 @@
+package postback_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/clobucks/go-sdk/affise/postback"
+)
+
+type recorder struct {
+	mu     sync.Mutex
+	events []*postback.Event
+	err    error
+}
+
+func (r *recorder) handle(_ context.Context, e *postback.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return r.err
+	}
+	r.events = append(r.events, e)
+
+	return nil
+}
+
+func serve(h http.Handler, req *http.Request) (int, string) {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	return w.Code, strings.TrimSpace(w.Body.String())
+}
+
+func TestMacros_Parse(t *testing.T) {
+	t.Parallel()
+
+	e, err := postback.DefaultMacros().Parse(url.Values{
+		"clickid":       {"5f1a"},
+		"action_id":     {"a1"},
+		"offer_id":      {"902"},
+		"pid":           {"610"},
+		"goal":          {"1"},
+		"status":        {"2"},
+		"sum":           {"10.5"},
+		"payouts":       {"3"},
+		"currency":      {"USD"},
+		"sub1":          {"abc"},
+		"sub2":          {"{sub2}"},
+		"custom_field7": {"x"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "5f1a", e.ClickID)
+	require.Equal(t, "a1", e.ActionID)
+	require.Equal(t, uint64(902), e.OfferID)
+	require.Equal(t, uint64(610), e.AffiliateID)
+	require.Equal(t, "pending", e.Status)
+	require.Equal(t, 10.5, e.Sum)
+	require.Equal(t, 3.0, e.Payouts)
+	require.Equal(t, "abc", e.Sub(1))
+	require.Equal(t, "", e.Sub(2))
+	require.Equal(t, "x", e.CustomFields[6])
+
+	e, err = postback.Macros{postback.FieldClickID: "cid", postback.FieldStatus: "st"}.Parse(url.Values{
+		"cid":      {"5f1a"},
+		"st":       {"Declined"},
+		"clickid":  {"other"},
+		"offer_id": {"not a number"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "5f1a", e.ClickID)
+	require.Equal(t, "declined", e.Status)
+	require.Zero(t, e.OfferID)
+
+	for _, params := range []url.Values{
+		{"sub1": {"abc"}},
+		{"clickid": {"{clickid}"}},
+		{"clickid": {"5f1a"}, "offer_id": {"x"}},
+		{"clickid": {"5f1a"}, "sum": {"1,5"}},
+	} {
+		_, err := postback.DefaultMacros().Parse(params)
+		require.True(t, errors.Is(err, postback.ErrInvalidPostback), params.Encode())
+	}
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Get", func(t *testing.T) {
+		t.Parallel()
+
+		rec := &recorder{}
+		h, err := postback.NewHandler(rec.handle)
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/postback?clickid=5f1a&offer_id=902&status=1&sub3=x", nil)
+		code, body := serve(h, req)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "ok", body)
+		require.Len(t, rec.events, 1)
+		require.Equal(t, "5f1a", rec.events[0].ClickID)
+		require.Equal(t, "confirmed", rec.events[0].Status)
+		require.Equal(t, "x", rec.events[0].Sub(3))
+		require.Equal(t, "192.0.2.1", rec.events[0].IP)
+		require.False(t, rec.events[0].ReceivedAt.IsZero())
+	})
+
+	t.Run("PostForm", func(t *testing.T) {
+		t.Parallel()
+
+		rec := &recorder{}
+		h, err := postback.NewHandler(rec.handle)
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/postback?offer_id=902", strings.NewReader("clickid=5f1a&sum=7"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		code, _ := serve(h, req)
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, rec.events, 1)
+		require.Equal(t, uint64(902), rec.events[0].OfferID)
+		require.Equal(t, 7.0, rec.events[0].Sum)
+	})
+
+	t.Run("PostJSON", func(t *testing.T) {
+		t.Parallel()
+
+		rec := &recorder{}
+		h, err := postback.NewHandler(rec.handle)
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/postback", strings.NewReader(`{"clickid":"5f1a","offer_id":902,"sum":7.5,"sub1":null}`))
+		req.Header.Set("Content-Type", "application/json")
+		code, _ := serve(h, req)
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, rec.events, 1)
+		require.Equal(t, uint64(902), rec.events[0].OfferID)
+		require.Equal(t, 7.5, rec.events[0].Sum)
+
+		req = httptest.NewRequest(http.MethodPost, "/postback", strings.NewReader(`{"clickid":12345678901234567,"offer_id":1000000,"action_id":"a1"}`))
+		req.Header.Set("Content-Type", "application/json")
+		code, _ = serve(h, req)
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, rec.events, 2)
+		require.Equal(t, uint64(1000000), rec.events[1].OfferID)
+		require.Equal(t, "12345678901234567", rec.events[1].ClickID)
+
+		req = httptest.NewRequest(http.MethodPost, "/postback", strings.NewReader(`{"clickid":`))
+		req.Header.Set("Content-Type", "application/json")
+		code, _ = serve(h, req)
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		var logged []error
+		rec := &recorder{}
+		h, err := postback.NewHandler(rec.handle, postback.WithErrorLog(func(_ *http.Request, err error) {
+			logged = append(logged, err)
+		}))
+		require.NoError(t, err)
+
+		code, _ := serve(h, httptest.NewRequest(http.MethodGet, "/postback?sub1=x", nil))
+		require.Equal(t, http.StatusBadRequest, code)
+		code, _ = serve(h, httptest.NewRequest(http.MethodPut, "/postback?clickid=5f1a", nil))
+		require.Equal(t, http.StatusMethodNotAllowed, code)
+		require.Empty(t, rec.events)
+		require.Len(t, logged, 2)
+		require.True(t, errors.Is(logged[0], postback.ErrInvalidPostback))
+	})
+
+	t.Run("Secret", func(t *testing.T) {
+		t.Parallel()
+
+		rec := &recorder{}
+		h, err := postback.NewHandler(rec.handle, postback.WithSecret("secret", "s3cr3t"))
+		require.NoError(t, err)
+
+		code, _ := serve(h, httptest.NewRequest(http.MethodGet, "/postback?clickid=5f1a&secret=wrong", nil))
+		require.Equal(t, http.StatusForbidden, code)
+		code, _ = serve(h, httptest.NewRequest(http.MethodGet, "/postback?clickid=5f1a", nil))
+		require.Equal(t, http.StatusForbidden, code)
+		code, _ = serve(h, httptest.NewRequest(http.MethodGet, "/postback?clickid=5f1a&secret=s3cr3t", nil))
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, rec.events, 1)
+
+		_, err = postback.NewHandler(rec.handle, postback.WithSecret("secret", ""))
+		require.Error(t, err)
+	})
+
+	t.Run("AllowedIPs", func(t *testing.T) {
+		t.Parallel()
+
+		rec := &recorder{}
+		h, err := postback.NewHandler(rec.handle,
+			postback.WithAllowedIPs("10.0.0.0/8", "203.0.113.7"),
+			postback.WithRealIPHeader("X-Forwarded-For"),
+		)
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/postback?clickid=5f1a", nil)
+		code, _ := serve(h, req)
+		require.Equal(t, http.StatusForbidden, code)
+
+		req = httptest.NewRequest(http.MethodGet, "/postback?clickid=5f1a", nil)
+		req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.1.1.1")
+		code, _ = serve(h, req)
+		require.Equal(t, http.StatusOK, code)
+
+		req = httptest.NewRequest(http.MethodGet, "/postback?clickid=5f1a", nil)
+		req.RemoteAddr = "10.2.3.4:5000"
+		code, _ = serve(h, req)
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, rec.events, 2)
+		require.Equal(t, "10.2.3.4", rec.events[1].IP)
+
+		_, err = postback.NewHandler(rec.handle, postback.WithAllowedIPs("10.0.0"))
+		require.Error(t, err)
+	})
+
+	t.Run("Idempotency", func(t *testing.T) {
+		t.Parallel()
+
+		rec := &recorder{}
+		h, err := postback.NewHandler(rec.handle, postback.WithStore(postback.NewMemoryStore(0)))
+		require.NoError(t, err)
+
+		for _, u := range []string{
+			"/postback?clickid=5f1a&action_id=a1&status=2",
+			"/postback?clickid=5f1a&action_id=a1&status=2",
+			"/postback?clickid=5f1a&action_id=a1&status=1",
+			"/postback?clickid=5f1a",
+			"/postback?clickid=5f1a",
+		} {
+			code, _ := serve(h, httptest.NewRequest(http.MethodGet, u, nil))
+			require.Equal(t, http.StatusOK, code)
+		}
+		require.Len(t, rec.events, 4)
+		require.Equal(t, "pending", rec.events[0].Status)
+		require.Equal(t, "confirmed", rec.events[1].Status)
+
+		code, body := serve(h, httptest.NewRequest(http.MethodGet, "/postback?clickid=5f1a&action_id=a1&status=1", nil))
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "duplicate", body)
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		t.Parallel()
+
+		rec := &recorder{err: errors.New("db is down")}
+		h, err := postback.NewHandler(rec.handle, postback.WithStore(postback.NewMemoryStore(0)))
+		require.NoError(t, err)
+
+		u := "/postback?clickid=5f1a&action_id=a1&status=1"
+		code, _ := serve(h, httptest.NewRequest(http.MethodGet, u, nil))
+		require.Equal(t, http.StatusInternalServerError, code)
+
+		rec.err = nil
+		code, body := serve(h, httptest.NewRequest(http.MethodGet, u, nil))
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "ok", body)
+		require.Len(t, rec.events, 1)
+	})
+	t.Run("Concurrent", func(t *testing.T) {
+		t.Parallel()
+
+		started, release := make(chan struct{}), make(chan error)
+		rec := &recorder{}
+		var once sync.Once
+		fn := func(ctx context.Context, e *postback.Event) error {
+			var err error
+			once.Do(func() {
+				close(started)
+				err = <-release
+			})
+			if err != nil {
+				return err
+			}
+
+			return rec.handle(ctx, e)
+		}
+		h, err := postback.NewHandler(fn, postback.WithStore(postback.NewMemoryStore(0)))
+		require.NoError(t, err)
+
+		u := "/postback?clickid=5f1a&action_id=a1&status=1"
+		done := make(chan int)
+		go func() {
+			code, _ := serve(h, httptest.NewRequest(http.MethodGet, u, nil))
+			done <- code
+		}()
+		<-started
+
+		// a redelivery is not acknowledged while the first delivery is processed
+		code, _ := serve(h, httptest.NewRequest(http.MethodGet, u, nil))
+		require.Equal(t, http.StatusConflict, code)
+
+		release <- errors.New("db is down")
+		require.Equal(t, http.StatusInternalServerError, <-done)
+
+		code, body := serve(h, httptest.NewRequest(http.MethodGet, u, nil))
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "ok", body)
+		require.Len(t, rec.events, 1)
+	})
+}
@@ -0,0 +1,377 @@
+package postback
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/clobucks/go-sdk/affise"
+)
+
+// maxResponseSize limits the response bodies kept in replay results.
+const maxResponseSize = 4 << 10
+
+// A ReplayOption is used to configure a Replayer.
+type ReplayOption func(*Replayer)
+
+// WithHTTPClient sets the client sending postbacks (Default: a client with a 30s timeout).
+func WithHTTPClient(c *http.Client) ReplayOption {
+	return func(r *Replayer) {
+		r.httpClient = c
+	}
+}
+
+// WithConcurrency sets the number of postbacks sent at the same time (Default: 4).
+func WithConcurrency(n int) ReplayOption {
+	return func(r *Replayer) {
+		if n > 0 {
+			r.concurrency = n
+		}
+	}
+}
+
+// WithRetryPolicy sets the policy retrying failed deliveries (Default:
+// ExponentialBackoff with 3 attempts, POST included).
+func WithRetryPolicy(policy affise.RetryPolicy) ReplayOption {
+	return func(r *Replayer) {
+		r.retry = policy
+	}
+}
+
+// WithDryRun makes Replay return the requests without sending them.
+func WithDryRun() ReplayOption {
+	return func(r *Replayer) {
+		r.dryRun = true
+	}
+}
+
+// WithRecorder sets the function called with the result of every postback
+// as soon as it is known, e.g. to persist the progress. Calls are serialized.
+func WithRecorder(record func(res *ReplayResult)) ReplayOption {
+	return func(r *Replayer) {
+		r.record = record
+	}
+}
+
+// Replayer sends failed affiliate postbacks again.
+type Replayer struct {
+	httpClient  *http.Client
+	concurrency int
+	retry       affise.RetryPolicy
+	dryRun      bool
+	record      func(res *ReplayResult)
+}
+
+// NewReplayer creates a new replayer.
+func NewReplayer(options ...ReplayOption) *Replayer {
+	r := &Replayer{
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		concurrency: 4,
+		retry:       &affise.ExponentialBackoff{RetryPOST: true},
+	}
+	for _, option := range options {
+		option(r)
+	}
+
+	return r
+}
+
+// ReplayResult is the outcome of a replayed postback.
+type ReplayResult struct {
+	Postback *affise.StatPostback
+	Method   string
+	URL      string
+	Body     string
+	Skipped  string // Reason the postback was not sent, e.g. "already delivered"
+	DryRun   bool
+	HTTPCode int
+	Response string // Response body, truncated
+	Attempts int
+	Err      error
+}
+
+// Delivered reports whether the replayed postback was accepted.
+func (res *ReplayResult) Delivered() bool {
+	return res.Err == nil && res.HTTPCode >= 200 && res.HTTPCode < 300
+}
+
+// ReplayResults are the results of Replay in the order of the postbacks.
+type ReplayResults []*ReplayResult
+
+// Delivered returns the number of delivered postbacks.
+func (rs ReplayResults) Delivered() int {
+	n := 0
+	for _, res := range rs {
+		if res.Delivered() {
+			n++
+		}
+	}
+
+	return n
+}
+
+// WriteCSV writes a row per result. For dry runs it lists the requests that would be sent.
+func (rs ReplayResults) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"affiliate_id", "lead_id", "method", "url", "body", "skipped", "http_code", "attempts", "error"})
+	for _, res := range rs {
+		errText := ""
+		if res.Err != nil {
+			errText = res.Err.Error()
+		}
+		_ = cw.Write([]string{
+			strconv.FormatUint(res.Postback.AffiliateID, 10),
+			res.Postback.LeadID,
+			res.Method,
+			res.URL,
+			res.Body,
+			res.Skipped,
+			strconv.Itoa(res.HTTPCode),
+			strconv.Itoa(res.Attempts),
+			errText,
+		})
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// Replay sends the failed postbacks again. Delivered postbacks and failures
+// of postbacks delivered by another attempt of Affise are skipped. Failures
+// of the same postback retried by Affise are sent once: the latest one is
+// replayed and the others are skipped as duplicates. The error is returned
+// only if the context is done; results of postbacks not sent have Err set.
+func (r *Replayer) Replay(ctx context.Context, postbacks []*affise.StatPostback) (ReplayResults, error) {
+	results := make(ReplayResults, len(postbacks))
+	delivered := make(map[string]bool)
+	latest := make(map[string]int)
+	for i, p := range postbacks {
+		key := replayKey(p)
+		if Delivered(p) {
+			delivered[key] = true
+
+			continue
+		}
+		if j, ok := latest[key]; !ok || !postbackTime(p).Before(postbackTime(postbacks[j])) {
+			latest[key] = i
+		}
+	}
+
+	var (
+		wg       sync.WaitGroup
+		recordMu sync.Mutex
+		sem      = make(chan struct{}, r.concurrency)
+	)
+	done := func(res *ReplayResult) {
+		if r.record == nil {
+			return
+		}
+		recordMu.Lock()
+		defer recordMu.Unlock()
+		r.record(res)
+	}
+	for i, p := range postbacks {
+		res := &ReplayResult{Postback: p}
+		results[i] = res
+
+		switch {
+		case Delivered(p):
+			res.Skipped = "not failed"
+		case delivered[replayKey(p)]:
+			res.Skipped = "already delivered"
+		case latest[replayKey(p)] != i:
+			res.Skipped = "duplicate"
+		}
+		if res.Skipped == "" {
+			req, err := NewReplayRequest(ctx, p)
+			if err != nil {
+				res.Err = err
+			} else {
+				res.Method, res.URL, res.Body = req.Method, req.URL.String(), requestBody(req)
+				res.DryRun = r.dryRun
+			}
+		}
+		if res.Skipped != "" || res.Err != nil || r.dryRun {
+			done(res)
+
+			continue
+		}
+
+		if ctx.Err() == nil {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			res.Err = err
+			done(res)
+
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			r.send(ctx, res)
+			done(res)
+		}()
+	}
+	wg.Wait()
+
+	return results, ctx.Err()
+}
+
+func (r *Replayer) send(ctx context.Context, res *ReplayResult) {
+	for {
+		res.Attempts++
+		req, err := NewReplayRequest(ctx, res.Postback)
+		if err != nil {
+			res.Err = err
+
+			return
+		}
+
+		resp, err := r.httpClient.Do(req)
+		res.Err = err
+		if err == nil {
+			body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+			_ = resp.Body.Close()
+			res.HTTPCode, res.Response = resp.StatusCode, string(body)
+			if res.Delivered() {
+				return
+			}
+		}
+
+		delay, ok := r.retry.Retry(req, resp, err, res.Attempts)
+		if !ok {
+			return
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			res.Err = ctx.Err()
+
+			return
+		}
+	}
+}
+
+// NewReplayRequest reconstructs the request of the postback from its URL and
+// the logged GET and POST parameters. Postbacks with POST parameters are
+// sent as POST forms.
+func NewReplayRequest(ctx context.Context, p *affise.StatPostback) (*http.Request, error) {
+	u, err := url.Parse(p.PostbackURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("postback: invalid postback URL %q", p.PostbackURL)
+	}
+
+	get, err := loggedParams(p.Get)
+	if err != nil {
+		return nil, fmt.Errorf("postback: get parameters: %w", err)
+	}
+	if len(get) != 0 {
+		q := u.Query()
+		for k, v := range get {
+			if _, ok := q[k]; !ok {
+				q[k] = v
+			}
+		}
+		u.RawQuery = q.Encode()
+	}
+
+	post, err := loggedParams(p.Post)
+	if err != nil {
+		// not JSON, the raw body is logged
+		post = nil
+	}
+	method, body := http.MethodGet, ""
+	switch {
+	case len(post) != 0:
+		method, body = http.MethodPost, post.Encode()
+	case post == nil && !isEmptyJSON(p.Post):
+		method, body = http.MethodPost, p.Post
+	}
+
+	var rb io.Reader
+	if body != "" {
+		rb = strings.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), rb)
+	if err != nil {
+		return nil, fmt.Errorf("postback: %w", err)
+	}
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	return req, nil
+}
+
+// loggedParams decodes parameters logged as a JSON object. Empty logs like
+// "[]" have no parameters.
+func loggedParams(s string) (url.Values, error) {
+	if isEmptyJSON(s) {
+		return url.Values{}, nil
+	}
+
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var m map[string]interface{}
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	for k, v := range m {
+		switch v := v.(type) {
+		case nil:
+		case []interface{}:
+			for _, item := range v {
+				params.Add(k, fmt.Sprint(item))
+			}
+		default:
+			params.Set(k, fmt.Sprint(v))
+		}
+	}
+
+	return params, nil
+}
+
+func isEmptyJSON(s string) bool {
+	switch strings.TrimSpace(s) {
+	case "", "[]", "{}", "null":
+		return true
+	default:
+		return false
+	}
+}
+
+// replayKey identifies deliveries of the same conversion event to the same URL.
+func replayKey(p *affise.StatPostback) string {
+	return p.LeadID + "/" + strconv.Itoa(p.Status) + "/" + p.Goal + "/" + p.PostbackURL
+}
+
+func requestBody(req *http.Request) string {
+	if req.GetBody == nil {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	_, _ = buf.ReadFrom(body)
+
+	return buf.String()
+}
@@ -0,0 +1,167 @@
+package postback_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/clobucks/go-sdk/affise"
+	"github.com/clobucks/go-sdk/affise/postback"
+)
+
+func TestNewReplayRequest(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	req, err := postback.NewReplayRequest(ctx, &affise.StatPostback{
+		PostbackURL: "https://tracker.example.com/pb?cid=1",
+		Get:         `{"cid":"2","sum":10.5}`,
+		Post:        "[]",
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.MethodGet, req.Method)
+	require.Equal(t, "https://tracker.example.com/pb?cid=1&sum=10.5", req.URL.String())
+
+	req, err = postback.NewReplayRequest(ctx, &affise.StatPostback{
+		PostbackURL: "https://tracker.example.com/pb",
+		Post:        `{"status":1,"subs":["a","b"]}`,
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.MethodPost, req.Method)
+	body, _ := ioutil.ReadAll(req.Body)
+	require.Equal(t, "status=1&subs=a&subs=b", string(body))
+
+	req, err = postback.NewReplayRequest(ctx, &affise.StatPostback{PostbackURL: "https://tracker.example.com/pb", Post: "cid=1"})
+	require.NoError(t, err)
+	require.Equal(t, http.MethodPost, req.Method)
+	body, _ = ioutil.ReadAll(req.Body)
+	require.Equal(t, "cid=1", string(body))
+
+	_, err = postback.NewReplayRequest(ctx, &affise.StatPostback{PostbackURL: "tracker.example.com/pb"})
+	require.Error(t, err)
+	_, err = postback.NewReplayRequest(ctx, &affise.StatPostback{PostbackURL: "https://tracker.example.com/pb", Get: "cid=1"})
+	require.Error(t, err)
+}
+
+func TestReplayer(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		requests = map[string]int{}
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		cid := r.FormValue("cid")
+		requests[cid]++
+		n := requests[cid]
+		mu.Unlock()
+
+		switch {
+		case cid == "flaky" && n == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case cid == "gone":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			_, _ = w.Write([]byte("ok"))
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	postbacks := []*affise.StatPostback{
+		{LeadID: "1", HTTPCode: 500, PostbackURL: ts.URL + "/pb?cid=flaky"},
+		{LeadID: "2", HTTPCode: 0, PostbackURL: ts.URL + "/pb?cid=gone"},
+		{LeadID: "3", HTTPCode: 200, PostbackURL: ts.URL + "/pb?cid=ok"},
+		{LeadID: "4", HTTPCode: 502, PostbackURL: ts.URL + "/pb?cid=retried"},
+		{LeadID: "4", HTTPCode: 200, PostbackURL: ts.URL + "/pb?cid=retried"},
+		{LeadID: "5", HTTPCode: 500, PostbackURL: ts.URL + "/pb", Post: `{"cid":"posted"}`},
+	}
+
+	t.Run("DryRun", func(t *testing.T) {
+		t.Parallel()
+
+		results, err := postback.NewReplayer(postback.WithDryRun()).Replay(context.Background(), postbacks)
+		require.NoError(t, err)
+		require.Len(t, results, 6)
+		require.True(t, results[0].DryRun)
+		require.Equal(t, ts.URL+"/pb?cid=flaky", results[0].URL)
+		require.Equal(t, "not failed", results[2].Skipped)
+		require.Equal(t, "already delivered", results[3].Skipped)
+		require.Equal(t, http.MethodPost, results[5].Method)
+		require.Equal(t, "cid=posted", results[5].Body)
+		require.Zero(t, results.Delivered())
+
+		var buf bytes.Buffer
+		require.NoError(t, results.WriteCSV(&buf))
+		require.Contains(t, buf.String(), "0,5,POST,"+ts.URL+"/pb,cid=posted,,0,0,\n")
+	})
+
+	t.Run("Replay", func(t *testing.T) {
+		var recorded []string
+		replayer := postback.NewReplayer(
+			postback.WithConcurrency(2),
+			postback.WithRetryPolicy(&affise.ExponentialBackoff{MaxAttempts: 3, MinDelay: time.Millisecond, RetryPOST: true}),
+			postback.WithRecorder(func(res *postback.ReplayResult) {
+				recorded = append(recorded, res.Postback.LeadID)
+			}),
+		)
+		results, err := replayer.Replay(context.Background(), postbacks)
+		require.NoError(t, err)
+		require.Len(t, recorded, 6)
+		require.Equal(t, 2, results.Delivered())
+
+		require.True(t, results[0].Delivered())
+		require.Equal(t, 2, results[0].Attempts)
+		require.Equal(t, "ok", results[0].Response)
+		require.False(t, results[1].Delivered())
+		require.Equal(t, http.StatusNotFound, results[1].HTTPCode)
+		require.Equal(t, 1, results[1].Attempts)
+		require.Zero(t, results[2].Attempts)
+		require.True(t, results[5].Delivered())
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Equal(t, map[string]int{"flaky": 2, "gone": 1, "posted": 1}, requests)
+	})
+
+	t.Run("Duplicates", func(t *testing.T) {
+		at := func(p *affise.StatPostback, sec int) *affise.StatPostback {
+			p.Date.Sec = sec
+
+			return p
+		}
+		failed := []*affise.StatPostback{
+			at(&affise.StatPostback{LeadID: "6", HTTPCode: 500, PostbackURL: ts.URL + "/pb?cid=dup"}, 1000),
+			at(&affise.StatPostback{LeadID: "6", HTTPCode: 502, PostbackURL: ts.URL + "/pb?cid=dup"}, 2000),
+			at(&affise.StatPostback{LeadID: "6", HTTPCode: 0, PostbackURL: ts.URL + "/pb?cid=dup"}, 1500),
+		}
+
+		results, err := postback.NewReplayer().Replay(context.Background(), failed)
+		require.NoError(t, err)
+		require.Equal(t, "duplicate", results[0].Skipped)
+		require.True(t, results[1].Delivered())
+		require.Equal(t, "duplicate", results[2].Skipped)
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Equal(t, 1, requests["dup"])
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		results, err := postback.NewReplayer().Replay(ctx, postbacks[:1])
+		require.True(t, errors.Is(err, context.Canceled))
+		require.True(t, errors.Is(results[0].Err, context.Canceled))
+	})
+}
@@ -0,0 +1,163 @@
+package postback
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/clobucks/go-sdk/affise"
+	"github.com/clobucks/go-sdk/affise/internal/macro"
+)
+
+// ErrInvalidTemplate is returned for postback URLs that Affise cannot fill
+// correctly. It is affise.ErrInvalidPostbackURL, which the postback methods
+// of the client return for the same URLs.
+var ErrInvalidTemplate = affise.ErrInvalidPostbackURL
+
+// Other macros supported in postback URLs besides the event fields.
+const (
+	MacroCity     = "city"
+	MacroDevice   = "device"
+	MacroOS       = "os"
+	MacroBrowser  = "browser"
+	MacroUA       = "ua"
+	MacroReferrer = "referrer"
+)
+
+func formatFloat(f float32) string {
+	return strconv.FormatFloat(float64(f), 'f', -1, 32)
+}
+
+func formatID(id uint64) string {
+	if id == 0 {
+		return ""
+	}
+
+	return strconv.FormatUint(id, 10)
+}
+
+// templateMacros maps the supported macros to the conversion values they are replaced with.
+var templateMacros = map[string]func(c *affise.Conversion) string{
+	string(FieldClickID):      func(c *affise.Conversion) string { return c.Clickid },
+	string(FieldActionID):     func(c *affise.Conversion) string { return c.ActionID },
+	string(FieldConversionID): func(c *affise.Conversion) string { return c.ConversionID },
+	string(FieldOfferID):      func(c *affise.Conversion) string { return formatID(c.OfferID) },
+	string(FieldAffiliateID):  func(c *affise.Conversion) string { return formatID(c.AffiliateID) },
+	string(FieldGoal):         func(c *affise.Conversion) string { return c.Goal },
+	string(FieldStatus):       func(c *affise.Conversion) string { return c.Status },
+	string(FieldSum):          func(c *affise.Conversion) string { return formatFloat(c.Sum) },
+	string(FieldRevenue):      func(c *affise.Conversion) string { return formatFloat(c.Revenue) },
+	string(FieldPayouts):      func(c *affise.Conversion) string { return formatFloat(c.Payouts) },
+	string(FieldCurrency):     func(c *affise.Conversion) string { return c.Currency },
+	string(FieldIP):           func(c *affise.Conversion) string { return c.IP },
+	string(FieldCountry):      func(c *affise.Conversion) string { return c.Country },
+	MacroCity:                 func(c *affise.Conversion) string { return c.City },
+	MacroDevice:               func(c *affise.Conversion) string { return c.Device },
+	MacroOS:                   func(c *affise.Conversion) string { return c.OS },
+	MacroBrowser:              func(c *affise.Conversion) string { return c.Browser },
+	MacroUA:                   func(c *affise.Conversion) string { return c.UA },
+	MacroReferrer:             func(c *affise.Conversion) string { return c.Referrer },
+	"sub1":                    func(c *affise.Conversion) string { return c.Sub1 },
+	"sub2":                    func(c *affise.Conversion) string { return c.Sub2 },
+	"sub3":                    func(c *affise.Conversion) string { return c.Sub3 },
+	"sub4":                    func(c *affise.Conversion) string { return c.Sub4 },
+	"sub5":                    func(c *affise.Conversion) string { return c.Sub5 },
+	"sub6":                    func(c *affise.Conversion) string { return c.Sub6 },
+	"sub7":                    func(c *affise.Conversion) string { return c.Sub7 },
+	"sub8":                    func(c *affise.Conversion) string { return c.Sub8 },
+	"custom_field1":           func(c *affise.Conversion) string { return c.CustomField1 },
+	"custom_field2":           func(c *affise.Conversion) string { return c.CustomField2 },
+	"custom_field3":           func(c *affise.Conversion) string { return c.CustomField3 },
+	"custom_field4":           func(c *affise.Conversion) string { return c.CustomField4 },
+	"custom_field5":           func(c *affise.Conversion) string { return c.CustomField5 },
+	"custom_field6":           func(c *affise.Conversion) string { return c.CustomField6 },
+	"custom_field7":           func(c *affise.Conversion) string { return c.CustomField7 },
+}
+
+// SupportedMacros returns the names of the macros allowed in templates, sorted.
+func SupportedMacros() []string {
+	return macro.PostbackMacros()
+}
+
+// Template is a validated postback URL with macros like "{clickid}".
+type Template struct {
+	raw    string
+	macros []string
+}
+
+// Param is a query parameter of a template built by BuildTemplate.
+type Param struct {
+	Name  string
+	Value string // Value with macros, e.g. "{sum}"
+}
+
+// MacroParam returns the parameter filled with the macro, e.g. MacroParam("cid", "clickid")
+// for "cid={clickid}".
+func MacroParam(name, macro string) Param {
+	return Param{Name: name, Value: "{" + macro + "}"}
+}
+
+// ParseTemplate validates the postback URL. The error lists all problems:
+// schemes other than http and https, unknown or unclosed macros and
+// characters that must be escaped.
+func ParseTemplate(raw string) (*Template, error) {
+	macros, problems := macro.ParsePostbackURL(raw)
+	if len(problems) != 0 {
+		return nil, fmt.Errorf("%w %q: %s", ErrInvalidTemplate, raw, strings.Join(problems, "; "))
+	}
+
+	return &Template{raw: raw, macros: macros}, nil
+}
+
+// BuildTemplate returns the template of the base URL with the parameters
+// added in order. Parameter values keep their macros and have other
+// characters escaped.
+func BuildTemplate(baseURL string, params ...Param) (*Template, error) {
+	var b strings.Builder
+	b.WriteString(baseURL)
+	sep := "?"
+	if strings.Contains(baseURL, "?") {
+		sep = "&"
+	}
+	for _, p := range params {
+		b.WriteString(sep)
+		b.WriteString(url.QueryEscape(p.Name))
+		b.WriteByte('=')
+		b.WriteString(macro.QueryEscape(p.Value))
+		sep = "&"
+	}
+
+	return ParseTemplate(b.String())
+}
+
+// String returns the postback URL, e.g. for AffiliateCreatePostbackOpts.URL.
+func (t *Template) String() string {
+	return t.raw
+}
+
+// Macros returns the macros used in the template in order of appearance.
+func (t *Template) Macros() []string {
+	return append([]string(nil), t.macros...)
+}
+
+// Render returns the URL Affise would call for the conversion. Macros of
+// empty strings and zero IDs are replaced with empty strings, and zero
+// amounts like sum with "0".
+func (t *Template) Render(c *affise.Conversion) string {
+	var b strings.Builder
+	raw := t.raw
+	for {
+		start := strings.IndexByte(raw, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(raw[start:], '}') + start
+		b.WriteString(raw[:start])
+		b.WriteString(url.QueryEscape(templateMacros[raw[start+1:end]](c)))
+		raw = raw[end+1:]
+	}
+	b.WriteString(raw)
+
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package postback_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/clobucks/go-sdk/affise"
+	"github.com/clobucks/go-sdk/affise/postback"
+)
+
+func TestParseTemplate(t *testing.T) {
+	t.Parallel()
+
+	tpl, err := postback.ParseTemplate("https://tracker.example.com/pb?cid={clickid}&sum={sum}&st={status}&s1={sub1}&n=a%20b")
+	require.NoError(t, err)
+	require.Equal(t, []string{"clickid", "sum", "status", "sub1"}, tpl.Macros())
+
+	tests := []struct {
+		raw string
+		err string
+	}{
+		{"ftp://example.com/pb?cid={clickid}", `scheme "ftp" is not http or https`},
+		{"https:///pb", "no host"},
+		{"https://example.com/pb?cid={clikid}", "unknown macro {clikid}, did you mean {clickid}"},
+		{"https://example.com/pb?x={transaction}", "unknown macro {transaction}"},
+		{"https://example.com/pb?cid={clickid", "unclosed macro at 27"},
+		{"https://example.com/pb?cid=clickid}", "unexpected '}' at 34"},
+		{"https://example.com/pb?n=a b", "unescaped character ' ' at 26"},
+		{"https://example.com/pb?n=100%", "invalid escape at 28"},
+	}
+	for _, tt := range tests {
+		_, err := postback.ParseTemplate(tt.raw)
+		require.True(t, errors.Is(err, postback.ErrInvalidTemplate), tt.raw)
+		require.Contains(t, err.Error(), tt.err)
+	}
+
+	_, err = postback.ParseTemplate("http://example.com/pb?a={summ}&b={ip}x}")
+	require.EqualError(t, err, `postback: invalid template "http://example.com/pb?a={summ}&b={ip}x}": `+
+		`unknown macro {summ}, did you mean {sum}; unexpected '}' at 38`)
+}
+
+func TestBuildTemplate(t *testing.T) {
+	t.Parallel()
+
+	tpl, err := postback.BuildTemplate("https://tracker.example.com/pb?src=affise",
+		postback.MacroParam("cid", "clickid"),
+		postback.MacroParam("payout", "payouts"),
+		postback.Param{Name: "note", Value: "goal {goal}"},
+	)
+	require.NoError(t, err)
+	require.Equal(t, "https://tracker.example.com/pb?src=affise&cid={clickid}&payout={payouts}&note=goal+{goal}", tpl.String())
+
+	_, err = postback.BuildTemplate("https://tracker.example.com/pb", postback.MacroParam("cid", "click_id"))
+	require.True(t, errors.Is(err, postback.ErrInvalidTemplate))
+}
+
+func TestTemplate_Render(t *testing.T) {
+	t.Parallel()
+
+	tpl, err := postback.ParseTemplate("https://tracker.example.com/pb?cid={clickid}&o={offer_id}&p={pid}&sum={sum}&s2={sub2}&ua={ua}")
+	require.NoError(t, err)
+
+	got := tpl.Render(&affise.Conversion{
+		Clickid:     "5f1a",
+		OfferID:     902,
+		AffiliateID: 610,
+		Sum:         10.5,
+		Sub2:        "a&b",
+		UA:          "Mozilla/5.0 (X11)",
+	})
+	require.Equal(t, "https://tracker.example.com/pb?cid=5f1a&o=902&p=610&sum=10.5&s2=a%26b&ua=Mozilla%2F5.0+%28X11%29", got)
+
+	require.Equal(t, "https://tracker.example.com/pb?cid=&o=&p=&sum=0&s2=&ua=", tpl.Render(&affise.Conversion{}))
+	require.Contains(t, postback.SupportedMacros(), "custom_field7")
+
+	// every supported macro is rendered
+	params := make([]postback.Param, 0, len(postback.SupportedMacros()))
+	for _, m := range postback.SupportedMacros() {
+		params = append(params, postback.MacroParam(m, m))
+	}
+	tpl, err = postback.BuildTemplate("https://tracker.example.com/pb", params...)
+	require.NoError(t, err)
+	require.NotContains(t, tpl.Render(&affise.Conversion{}), "{")
+}
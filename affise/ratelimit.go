@@ -0,0 +1,114 @@
+package affise
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var errRateLimit = errors.New("invalid rate limit")
+
+// RateLimit describes a budget of requests for an API key.
+type RateLimit struct {
+	Requests int           // Number of requests allowed per Period. Zero means no limit
+	Period   time.Duration // Period of the budget (Default: time.Minute)
+	Burst    int           // Maximum number of requests sent at once (Default: Requests)
+}
+
+// limiter is a token bucket shared by all requests of the same kind.
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(rl RateLimit) (*limiter, error) {
+	if rl.Requests < 0 || rl.Period < 0 || rl.Burst < 0 {
+		return nil, errRateLimit
+	}
+	if rl.Requests == 0 {
+		return nil, nil
+	}
+
+	period := rl.Period
+	if period == 0 {
+		period = time.Minute
+	}
+	burst := rl.Burst
+	if burst == 0 {
+		burst = rl.Requests
+	}
+
+	return &limiter{
+		rate:   float64(rl.Requests) / period.Seconds(),
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}, nil
+}
+
+// wait blocks until a token is available or ctx is done.
+// It returns the time spent waiting.
+func (l *limiter) wait(ctx context.Context) (time.Duration, error) {
+	if l == nil {
+		return 0, nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return 0, nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(delay)) {
+		l.cancel()
+
+		return 0, context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		l.cancel()
+
+		return time.Since(now), ctx.Err()
+	case <-timer.C:
+		return delay, nil
+	}
+}
+
+// cancel returns the reserved token to the bucket.
+func (l *limiter) cancel() {
+	l.mu.Lock()
+	l.tokens++
+	l.mu.Unlock()
+}
+
+type adminRequestKey struct{}
+
+// limiterFor returns the limiter for the request budget.
+func (c *Client) limiterFor(ctx context.Context) *limiter {
+	if isAdmin, _ := ctx.Value(adminRequestKey{}).(bool); isAdmin {
+		return c.adminLimiter
+	}
+
+	return c.baseLimiter
+}
@@ -0,0 +1,88 @@
+package affise_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/clobucks/go-sdk/affise"
+)
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+	t.Run("Wait", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		defer env.teardown()
+
+		env.mockHandle(t, "3.0.admin.users@get.json", http.MethodGet, "/3.0/admin/users", http.StatusOK)
+		client, err := affise.NewClient(
+			affise.WithBaseURL(env.Server.URL),
+			affise.WithAdminURL(env.Server.URL),
+			affise.WithRateLimit(affise.RateLimit{}, affise.RateLimit{Requests: 20, Period: time.Second, Burst: 1}),
+		)
+		require.NoError(t, err)
+
+		var (
+			wg   sync.WaitGroup
+			mu   sync.Mutex
+			wait time.Duration
+		)
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, resp, err := client.AdminUser.List(env.Ctx, &affise.AdminUserListOpts{})
+				require.NoError(t, err)
+
+				mu.Lock()
+				wait += resp.RateLimitWait
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		// 3 of 4 requests wait for 50ms, 100ms and 150ms
+		require.GreaterOrEqual(t, int64(wait), int64(250*time.Millisecond))
+	})
+
+	t.Run("Separate", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		defer env.teardown()
+
+		env.mockHandle(t, "3.0.admin.users@get.json", http.MethodGet, "/3.0/admin/users", http.StatusOK)
+		env.mockHandle(t, "3.0.offers@get.json", http.MethodGet, "/3.0/offers", http.StatusOK)
+		client, err := affise.NewClient(
+			affise.WithBaseURL(env.Server.URL),
+			affise.WithAdminURL(env.Server.URL),
+			affise.WithRateLimit(affise.RateLimit{Requests: 1, Period: time.Hour}, affise.RateLimit{Requests: 1, Period: time.Hour}),
+		)
+		require.NoError(t, err)
+
+		_, resp, err := client.AdminUser.List(env.Ctx, &affise.AdminUserListOpts{})
+		require.NoError(t, err)
+		require.Zero(t, resp.RateLimitWait)
+
+		_, resp, err = client.Offer.List(env.Ctx, &affise.OfferListOpts{})
+		require.NoError(t, err)
+		require.Zero(t, resp.RateLimitWait)
+
+		ctx, cancel := context.WithTimeout(env.Ctx, time.Second)
+		defer cancel()
+
+		_, _, err = client.Offer.List(ctx, &affise.OfferListOpts{})
+		require.Error(t, err)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := affise.NewClient(affise.WithRateLimit(affise.RateLimit{Requests: -1}, affise.RateLimit{}))
+		require.Error(t, err)
+	})
+}
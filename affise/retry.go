@@ -0,0 +1,138 @@
+package affise
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryPolicy decides whether a failed attempt should be retried.
+type RetryPolicy interface {
+	// Retry is called after every attempt with its response or transport error.
+	// A 2xx response whose meta status isn't 1 is passed with its *ResponseErr.
+	// It returns the delay before the next attempt and false if the request
+	// should not be retried.
+	Retry(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool)
+}
+
+// ExponentialBackoff is a RetryPolicy retrying network errors, 429 and 5xx
+// responses and meta errors other than field validation errors with
+// exponential backoff and jitter. The Retry-After header takes
+// precedence over the computed delay.
+type ExponentialBackoff struct {
+	MaxAttempts int           // Total number of attempts including the first one (Default: 3)
+	MinDelay    time.Duration // Delay before the second attempt (Default: 500ms)
+	MaxDelay    time.Duration // Upper bound of the computed delay (Default: 30s)
+	RetryPOST   bool          // Retry non-idempotent requests such as POST
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+const (
+	defaultRetryMaxAttempts = 3
+	defaultRetryMinDelay    = 500 * time.Millisecond
+	defaultRetryMaxDelay    = 30 * time.Second
+)
+
+// Retry implements RetryPolicy.
+func (b *ExponentialBackoff) Retry(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	maxAttempts := b.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = defaultRetryMaxAttempts
+	}
+	if attempt >= maxAttempts {
+		return 0, false
+	}
+
+	if !b.RetryPOST && !isIdempotent(req.Method) {
+		return 0, false
+	}
+
+	if err == nil && !isRetryableStatus(resp.StatusCode) {
+		return 0, false
+	}
+
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		return 0, false
+	}
+
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			return d, true
+		}
+	}
+
+	return b.backoff(attempt), true
+}
+
+func (b *ExponentialBackoff) backoff(attempt int) time.Duration {
+	minDelay, maxDelay := b.MinDelay, b.MaxDelay
+	if minDelay == 0 {
+		minDelay = defaultRetryMinDelay
+	}
+	if maxDelay == 0 {
+		maxDelay = defaultRetryMaxDelay
+	}
+
+	d := minDelay
+	for i := 1; i < attempt && d < maxDelay; i++ {
+		d *= 2
+	}
+	if d > maxDelay {
+		d = maxDelay
+	}
+
+	// equal jitter: half of the delay is fixed, the other half is random
+	b.mu.Lock()
+	if b.rand == nil {
+		b.rand = rand.New(rand.NewSource(time.Now().UnixNano())) //nolint:gosec
+	}
+	jitter := time.Duration(b.rand.Int63n(int64(d/2) + 1))
+	b.mu.Unlock()
+
+	return d/2 + jitter
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || (code >= 500 && code <= 599)
+}
+
+// retryAfter parses the Retry-After header given in seconds or as an HTTP date.
+func retryAfter(s string) (time.Duration, bool) {
+	if s == "" {
+		return 0, false
+	}
+
+	if sec, err := strconv.Atoi(s); err == nil {
+		if sec < 0 {
+			return 0, false
+		}
+
+		return time.Duration(sec) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(s); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+
+		return d, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,137 @@
+package affise_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/clobucks/go-sdk/affise"
+)
+
+func newRetryEnv(t *testing.T, policy affise.RetryPolicy, fail int32, code int, header http.Header) (*affise.Client, *int32, func()) {
+	t.Helper()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&calls, 1) <= fail {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(code)
+			_, _ = w.Write([]byte(`{"status":2,"message":"fail"}`))
+
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":1,"users":[]}`))
+	}))
+
+	client, err := affise.NewClient(
+		affise.WithBaseURL(server.URL),
+		affise.WithAdminURL(server.URL),
+		affise.WithRetryPolicy(policy),
+	)
+	require.NoError(t, err)
+
+	return client, &calls, server.Close
+}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Parallel()
+	t.Run("Retry5xx", func(t *testing.T) {
+		t.Parallel()
+		policy := &affise.ExponentialBackoff{MaxAttempts: 3, MinDelay: time.Millisecond}
+		client, calls, teardown := newRetryEnv(t, policy, 2, http.StatusBadGateway, nil)
+		defer teardown()
+
+		_, resp, err := client.AdminUser.List(context.Background(), &affise.AdminUserListOpts{})
+		require.NoError(t, err)
+		require.Equal(t, 3, resp.Attempts)
+		require.EqualValues(t, 3, atomic.LoadInt32(calls))
+	})
+
+	t.Run("MaxAttempts", func(t *testing.T) {
+		t.Parallel()
+		policy := &affise.ExponentialBackoff{MaxAttempts: 2, MinDelay: time.Millisecond}
+		client, calls, teardown := newRetryEnv(t, policy, 5, http.StatusServiceUnavailable, nil)
+		defer teardown()
+
+		_, _, err := client.AdminUser.List(context.Background(), &affise.AdminUserListOpts{})
+		require.Error(t, err)
+		require.EqualValues(t, 2, atomic.LoadInt32(calls))
+	})
+
+	t.Run("MetaStatus", func(t *testing.T) {
+		t.Parallel()
+		policy := &affise.ExponentialBackoff{MinDelay: time.Millisecond}
+		client, calls, teardown := newRetryEnv(t, policy, 1, http.StatusOK, nil)
+		defer teardown()
+
+		_, resp, err := client.AdminUser.List(context.Background(), &affise.AdminUserListOpts{})
+		require.NoError(t, err)
+		require.Equal(t, 2, resp.Attempts)
+		require.EqualValues(t, 2, atomic.LoadInt32(calls))
+	})
+
+	t.Run("NoRetry4xx", func(t *testing.T) {
+		t.Parallel()
+		policy := &affise.ExponentialBackoff{MinDelay: time.Millisecond}
+		client, calls, teardown := newRetryEnv(t, policy, 1, http.StatusBadRequest, nil)
+		defer teardown()
+
+		_, _, err := client.AdminUser.List(context.Background(), &affise.AdminUserListOpts{})
+		require.Error(t, err)
+		require.EqualValues(t, 1, atomic.LoadInt32(calls))
+	})
+
+	t.Run("POST", func(t *testing.T) {
+		t.Parallel()
+		policy := &affise.ExponentialBackoff{MinDelay: time.Millisecond}
+		client, calls, teardown := newRetryEnv(t, policy, 1, http.StatusInternalServerError, nil)
+		defer teardown()
+
+		_, _, err := client.AdminUser.Create(context.Background(), &affise.AdminUserCreateOpts{})
+		require.Error(t, err)
+		require.EqualValues(t, 1, atomic.LoadInt32(calls))
+
+		policy = &affise.ExponentialBackoff{MinDelay: time.Millisecond, RetryPOST: true}
+		client, calls, teardown = newRetryEnv(t, policy, 1, http.StatusInternalServerError, nil)
+		defer teardown()
+
+		_, resp, err := client.AdminUser.Create(context.Background(), &affise.AdminUserCreateOpts{})
+		require.NoError(t, err)
+		require.Equal(t, 2, resp.Attempts)
+		require.EqualValues(t, 2, atomic.LoadInt32(calls))
+	})
+
+	t.Run("RetryAfter", func(t *testing.T) {
+		t.Parallel()
+		policy := &affise.ExponentialBackoff{MinDelay: time.Hour}
+		header := http.Header{"Retry-After": []string{"0"}}
+		client, _, teardown := newRetryEnv(t, policy, 1, http.StatusTooManyRequests, header)
+		defer teardown()
+
+		_, resp, err := client.AdminUser.List(context.Background(), &affise.AdminUserListOpts{})
+		require.NoError(t, err)
+		require.Equal(t, 2, resp.Attempts)
+	})
+
+	t.Run("Deadline", func(t *testing.T) {
+		t.Parallel()
+		policy := &affise.ExponentialBackoff{MinDelay: time.Hour}
+		client, calls, teardown := newRetryEnv(t, policy, 1, http.StatusBadGateway, nil)
+		defer teardown()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		_, _, err := client.AdminUser.List(ctx, &affise.AdminUserListOpts{})
+		require.Error(t, err)
+		require.EqualValues(t, 1, atomic.LoadInt32(calls))
+	})
+}
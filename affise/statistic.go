@@ -335,6 +335,8 @@ type statisticCustomResponse struct {
 
 // Custom gets custom statistics.
 func (s *StatisticService) Custom(ctx context.Context, opts *StatisticCustomOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.Custom")
+
 	path := "/3.0/stats/custom"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -351,6 +353,20 @@ func (s *StatisticService) Custom(ctx context.Context, opts *StatisticCustomOpts
 	return body.Stats, resp, nil
 }
 
+// CustomIter returns an iterator over all pages of Custom starting from opts.Page.
+func (s *StatisticService) CustomIter(ctx context.Context, opts *StatisticCustomOpts) *StatIter {
+	o := StatisticCustomOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.Custom(ctx, &o)
+	})
+}
+
 // ConversionsByIDOpts specifies options for ConversionsByID.
 type ConversionsByIDOpts struct {
 	ID string `json:"id"`
@@ -364,6 +380,8 @@ type statisticConversionsByIDResponse struct {
 // ConversionsByID gets conversion
 // NOTE: Available only for admin API-Key.
 func (s *StatisticService) ConversionsByID(ctx context.Context, id string) (*Conversion, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.ConversionsByID")
+
 	path := "/3.0/stats/conversionsbyid"
 	opts := ConversionsByIDOpts{ID: id}
 
@@ -427,6 +445,8 @@ type statisticConversionsResponse struct {
 
 // Conversions gets conversions.
 func (s *StatisticService) Conversions(ctx context.Context, opts *StatisticConversionsOpts) ([]*Conversion, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.Conversions")
+
 	path := "/3.0/stats/conversions"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -443,6 +463,20 @@ func (s *StatisticService) Conversions(ctx context.Context, opts *StatisticConve
 	return body.Conversions, resp, nil
 }
 
+// ConversionsIter returns an iterator over all pages of Conversions starting from opts.Page.
+func (s *StatisticService) ConversionsIter(ctx context.Context, opts *StatisticConversionsOpts) *ConversionIter {
+	o := StatisticConversionsOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newConversionIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Conversion, *Response, error) {
+		o.Page = page
+
+		return s.Conversions(ctx, &o)
+	})
+}
+
 // StatisticClicksOpts specifies options for Clicks.
 type StatisticClicksOpts struct {
 	DateFrom    string   `schema:"date_from"`             // REQUIRED (Available: YYYY-MM-DD)
@@ -465,6 +499,8 @@ type statisticClicksResponse struct {
 // Clicks gets clicks
 // NOTE: Available only for admin API-Key.
 func (s *StatisticService) Clicks(ctx context.Context, opts *StatisticClicksOpts) ([]*Click, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.Clicks")
+
 	path := "/3.0/stats/clicks"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -481,6 +517,20 @@ func (s *StatisticService) Clicks(ctx context.Context, opts *StatisticClicksOpts
 	return body.Clicks, resp, nil
 }
 
+// ClicksIter returns an iterator over all pages of Clicks starting from opts.Page.
+func (s *StatisticService) ClicksIter(ctx context.Context, opts *StatisticClicksOpts) *ClickIter {
+	o := StatisticClicksOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newClickIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Click, *Response, error) {
+		o.Page = page
+
+		return s.Clicks(ctx, &o)
+	})
+}
+
 // StatisticGetByDateOpts specifies options for GetByDate.
 type StatisticGetByDateOpts struct {
 	StatFilter
@@ -498,6 +548,8 @@ type statisticGetByDateResponse struct {
 
 // GetByDate gets statistics by date.
 func (s *StatisticService) GetByDate(ctx context.Context, opts *StatisticGetByDateOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByDate")
+
 	path := "/3.0/stats/getbydate"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -514,6 +566,20 @@ func (s *StatisticService) GetByDate(ctx context.Context, opts *StatisticGetByDa
 	return body.Stats, resp, nil
 }
 
+// GetByDateIter returns an iterator over all pages of GetByDate starting from opts.Page.
+func (s *StatisticService) GetByDateIter(ctx context.Context, opts *StatisticGetByDateOpts) *StatIter {
+	o := StatisticGetByDateOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetByDate(ctx, &o)
+	})
+}
+
 // StatisticGetByHourOpts specifies options for GetByHour.
 type StatisticGetByHourOpts struct {
 	StatFilter
@@ -531,6 +597,8 @@ type statisticGetByHourResponse struct {
 
 // GetByHour gets statistics by hour.
 func (s *StatisticService) GetByHour(ctx context.Context, opts *StatisticGetByHourOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByHour")
+
 	path := "/3.0/stats/getbyhour"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -547,6 +615,20 @@ func (s *StatisticService) GetByHour(ctx context.Context, opts *StatisticGetByHo
 	return body.Stats, resp, nil
 }
 
+// GetByHourIter returns an iterator over all pages of GetByHour starting from opts.Page.
+func (s *StatisticService) GetByHourIter(ctx context.Context, opts *StatisticGetByHourOpts) *StatIter {
+	o := StatisticGetByHourOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetByHour(ctx, &o)
+	})
+}
+
 // StatisticGetBySubOpts specifies options for GetBySub.
 type StatisticGetBySubOpts struct {
 	StatFilter
@@ -565,6 +647,8 @@ type statisticGetBySubResponse struct {
 // GetBySub gets statistics by sub
 // NOTE: Available only for partner API-Key.
 func (s *StatisticService) GetBySub(ctx context.Context, opts *StatisticGetBySubOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetBySub")
+
 	path := "/3.0/stats/getbysub"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -581,6 +665,20 @@ func (s *StatisticService) GetBySub(ctx context.Context, opts *StatisticGetBySub
 	return body.Stats, resp, nil
 }
 
+// GetBySubIter returns an iterator over all pages of GetBySub starting from opts.Page.
+func (s *StatisticService) GetBySubIter(ctx context.Context, opts *StatisticGetBySubOpts) *StatIter {
+	o := StatisticGetBySubOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetBySub(ctx, &o)
+	})
+}
+
 // StatisticGetByOfferOpts specifies options for GetByOffer.
 type StatisticGetByOfferOpts struct {
 	StatFilter
@@ -598,6 +696,8 @@ type statisticGetByOfferResponse struct {
 
 // GetByOffer gets statistics by offer.
 func (s *StatisticService) GetByOffer(ctx context.Context, opts *StatisticGetByOfferOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByOffer")
+
 	path := "/3.0/stats/getbyprogram"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -614,6 +714,20 @@ func (s *StatisticService) GetByOffer(ctx context.Context, opts *StatisticGetByO
 	return body.Stats, resp, nil
 }
 
+// GetByOfferIter returns an iterator over all pages of GetByOffer starting from opts.Page.
+func (s *StatisticService) GetByOfferIter(ctx context.Context, opts *StatisticGetByOfferOpts) *StatIter {
+	o := StatisticGetByOfferOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetByOffer(ctx, &o)
+	})
+}
+
 // StatisticGetByAdvertiserOpts specifies options for GetByAdvertiser.
 type StatisticGetByAdvertiserOpts struct {
 	StatFilter
@@ -632,6 +746,8 @@ type statisticGetByAdvertiserResponse struct {
 // GetByAdvertiser gets statistics by advertiser
 // NOTE: Available only for admin API-Key.
 func (s *StatisticService) GetByAdvertiser(ctx context.Context, opts *StatisticGetByAdvertiserOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByAdvertiser")
+
 	path := "/3.0/stats/getbyadvertiser"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -648,6 +764,20 @@ func (s *StatisticService) GetByAdvertiser(ctx context.Context, opts *StatisticG
 	return body.Stats, resp, nil
 }
 
+// GetByAdvertiserIter returns an iterator over all pages of GetByAdvertiser starting from opts.Page.
+func (s *StatisticService) GetByAdvertiserIter(ctx context.Context, opts *StatisticGetByAdvertiserOpts) *StatIter {
+	o := StatisticGetByAdvertiserOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetByAdvertiser(ctx, &o)
+	})
+}
+
 // StatisticGetByAccountManagerOpts specifies options for GetByAccountManager.
 type StatisticGetByAccountManagerOpts struct {
 	StatFilter
@@ -666,6 +796,8 @@ type statisticGetByAccountManagerResponse struct {
 // GetByAccountManager gets statistics by accounts managers
 // NOTE: Available only for admin API-Key.
 func (s *StatisticService) GetByAccountManager(ctx context.Context, opts *StatisticGetByAccountManagerOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByAccountManager")
+
 	path := "/3.0/stats/getbyaccountmanager"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -682,6 +814,20 @@ func (s *StatisticService) GetByAccountManager(ctx context.Context, opts *Statis
 	return body.Stats, resp, nil
 }
 
+// GetByAccountManagerIter returns an iterator over all pages of GetByAccountManager starting from opts.Page.
+func (s *StatisticService) GetByAccountManagerIter(ctx context.Context, opts *StatisticGetByAccountManagerOpts) *StatIter {
+	o := StatisticGetByAccountManagerOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetByAccountManager(ctx, &o)
+	})
+}
+
 // StatisticGetByAffiliateManagerOpts specifies options for GetByAffiliateManager.
 type StatisticGetByAffiliateManagerOpts struct {
 	StatFilter
@@ -700,6 +846,8 @@ type statisticGetByAffiliateManagerResponse struct {
 // GetByAffiliateManager gets statistics by affiliates managers
 // NOTE: Available only for admin API-Key.
 func (s *StatisticService) GetByAffiliateManager(ctx context.Context, opts *StatisticGetByAffiliateManagerOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByAffiliateManager")
+
 	path := "/3.0/stats/getbyaffiliatemanager"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -716,6 +864,20 @@ func (s *StatisticService) GetByAffiliateManager(ctx context.Context, opts *Stat
 	return body.Stats, resp, nil
 }
 
+// GetByAffiliateManagerIter returns an iterator over all pages of GetByAffiliateManager starting from opts.Page.
+func (s *StatisticService) GetByAffiliateManagerIter(ctx context.Context, opts *StatisticGetByAffiliateManagerOpts) *StatIter {
+	o := StatisticGetByAffiliateManagerOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetByAffiliateManager(ctx, &o)
+	})
+}
+
 // StatisticGetByPartnerOpts specifies options for GetByAffiliate.
 type StatisticGetByAffiliateOpts struct {
 	StatFilter
@@ -734,6 +896,8 @@ type statisticGetByAffiliateResponse struct {
 // GetByAffiliate gets statistics by affiliate
 // NOTE:  Available only for admin API-Key.
 func (s *StatisticService) GetByAffiliate(ctx context.Context, opts *StatisticGetByAffiliateOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByAffiliate")
+
 	path := "/3.0/stats/getbypartner"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -750,6 +914,20 @@ func (s *StatisticService) GetByAffiliate(ctx context.Context, opts *StatisticGe
 	return body.Stats, resp, nil
 }
 
+// GetByAffiliateIter returns an iterator over all pages of GetByAffiliate starting from opts.Page.
+func (s *StatisticService) GetByAffiliateIter(ctx context.Context, opts *StatisticGetByAffiliateOpts) *StatIter {
+	o := StatisticGetByAffiliateOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetByAffiliate(ctx, &o)
+	})
+}
+
 // StatisticGetByAffiliateByDateOpts specifies options for GetByAffiliateByDate.
 type StatisticGetByAffiliateByDateOpts struct {
 	StatFilter
@@ -768,6 +946,8 @@ type statisticGetByAffiliateByDateResponse struct {
 // GetByAffiliateByDate gets statistics by affiliate and date
 // NOTE: Available only for admin API-Key.
 func (s *StatisticService) GetByAffiliateByDate(ctx context.Context, opts *StatisticGetByAffiliateByDateOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByAffiliateByDate")
+
 	path := "/3.0/stats/getbypartnerbydate"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -784,6 +964,20 @@ func (s *StatisticService) GetByAffiliateByDate(ctx context.Context, opts *Stati
 	return body.Stats, resp, nil
 }
 
+// GetByAffiliateByDateIter returns an iterator over all pages of GetByAffiliateByDate starting from opts.Page.
+func (s *StatisticService) GetByAffiliateByDateIter(ctx context.Context, opts *StatisticGetByAffiliateByDateOpts) *StatIter {
+	o := StatisticGetByAffiliateByDateOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetByAffiliateByDate(ctx, &o)
+	})
+}
+
 // StatisticGetByCountriesOpts specifies options for GetByCountries.
 type StatisticGetByCountriesOpts struct {
 	StatFilter
@@ -801,6 +995,8 @@ type statisticGetByCountriesResponse struct {
 
 // GetByCountries gets statistics by countries.
 func (s *StatisticService) GetByCountries(ctx context.Context, opts *StatisticGetByCountriesOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByCountries")
+
 	path := "/3.0/stats/getbycountries"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -817,6 +1013,20 @@ func (s *StatisticService) GetByCountries(ctx context.Context, opts *StatisticGe
 	return body.Stats, resp, nil
 }
 
+// GetByCountriesIter returns an iterator over all pages of GetByCountries starting from opts.Page.
+func (s *StatisticService) GetByCountriesIter(ctx context.Context, opts *StatisticGetByCountriesOpts) *StatIter {
+	o := StatisticGetByCountriesOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetByCountries(ctx, &o)
+	})
+}
+
 // StatisticGetByBrowsersOpts specifies options for GetByBrowsers.
 type StatisticGetByBrowsersOpts struct {
 	StatFilter
@@ -834,6 +1044,8 @@ type statisticGetByBrowsersResponse struct {
 
 // GetByBrowsers gets statistics by browser.
 func (s *StatisticService) GetByBrowsers(ctx context.Context, opts *StatisticGetByBrowsersOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByBrowsers")
+
 	path := "/3.0/stats/getbybrowsers"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -850,6 +1062,20 @@ func (s *StatisticService) GetByBrowsers(ctx context.Context, opts *StatisticGet
 	return body.Stats, resp, nil
 }
 
+// GetByBrowsersIter returns an iterator over all pages of GetByBrowsers starting from opts.Page.
+func (s *StatisticService) GetByBrowsersIter(ctx context.Context, opts *StatisticGetByBrowsersOpts) *StatIter {
+	o := StatisticGetByBrowsersOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetByBrowsers(ctx, &o)
+	})
+}
+
 // StatisticGetByBrowserVersionOpts specifies options for GetByBrowserVersion.
 type StatisticGetByBrowserVersionOpts struct {
 	StatFilter
@@ -867,6 +1093,8 @@ type statisticGetByBrowserVersionResponse struct {
 
 // GetByBrowserVersion gets statistics by browser version.
 func (s *StatisticService) GetByBrowserVersion(ctx context.Context, opts *StatisticGetByBrowserVersionOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByBrowserVersion")
+
 	path := "/3.0/stats/getbybrowsersversion"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -883,6 +1111,20 @@ func (s *StatisticService) GetByBrowserVersion(ctx context.Context, opts *Statis
 	return body.Stats, resp, nil
 }
 
+// GetByBrowserVersionIter returns an iterator over all pages of GetByBrowserVersion starting from opts.Page.
+func (s *StatisticService) GetByBrowserVersionIter(ctx context.Context, opts *StatisticGetByBrowserVersionOpts) *StatIter {
+	o := StatisticGetByBrowserVersionOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetByBrowserVersion(ctx, &o)
+	})
+}
+
 // StatisticGetByLandingOpts specifies options for GetByLanding.
 type StatisticGetByLandingOpts struct {
 	StatFilter
@@ -900,6 +1142,8 @@ type statisticGetByLandingResponse struct {
 
 // GetByLanding gets statistics by landing.
 func (s *StatisticService) GetByLanding(ctx context.Context, opts *StatisticGetByLandingOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByLanding")
+
 	path := "/3.0/stats/getbylanding"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -916,6 +1160,20 @@ func (s *StatisticService) GetByLanding(ctx context.Context, opts *StatisticGetB
 	return body.Stats, resp, nil
 }
 
+// GetByLandingIter returns an iterator over all pages of GetByLanding starting from opts.Page.
+func (s *StatisticService) GetByLandingIter(ctx context.Context, opts *StatisticGetByLandingOpts) *StatIter {
+	o := StatisticGetByLandingOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetByLanding(ctx, &o)
+	})
+}
+
 // StatisticGetByPrelandingOpts specifies options for GetByPrelanding.
 type StatisticGetByPrelandingOpts struct {
 	StatFilter
@@ -933,6 +1191,8 @@ type statisticGetByPrelandingResponse struct {
 
 // GetByPrelanding gets statistics by prelanding.
 func (s *StatisticService) GetByPrelanding(ctx context.Context, opts *StatisticGetByPrelandingOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByPrelanding")
+
 	path := "/3.0/stats/getbyprelanding"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -949,6 +1209,20 @@ func (s *StatisticService) GetByPrelanding(ctx context.Context, opts *StatisticG
 	return body.Stats, resp, nil
 }
 
+// GetByPrelandingIter returns an iterator over all pages of GetByPrelanding starting from opts.Page.
+func (s *StatisticService) GetByPrelandingIter(ctx context.Context, opts *StatisticGetByPrelandingOpts) *StatIter {
+	o := StatisticGetByPrelandingOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetByPrelanding(ctx, &o)
+	})
+}
+
 // StatisticGetByMobileCarrierOpts specifies options for GetByMobileCarrier.
 type StatisticGetByMobileCarrierOpts struct {
 	StatFilter
@@ -966,6 +1240,8 @@ type statisticGetByMobileCarrierResponse struct {
 
 // GetByMobileCarrier gets statistics by mobile carrier.
 func (s *StatisticService) GetByMobileCarrier(ctx context.Context, opts *StatisticGetByMobileCarrierOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByMobileCarrier")
+
 	path := "/3.0/stats/getbymobilecarrier"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -982,6 +1258,20 @@ func (s *StatisticService) GetByMobileCarrier(ctx context.Context, opts *Statist
 	return body.Stats, resp, nil
 }
 
+// GetByMobileCarrierIter returns an iterator over all pages of GetByMobileCarrier starting from opts.Page.
+func (s *StatisticService) GetByMobileCarrierIter(ctx context.Context, opts *StatisticGetByMobileCarrierOpts) *StatIter {
+	o := StatisticGetByMobileCarrierOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetByMobileCarrier(ctx, &o)
+	})
+}
+
 // StatisticGetByConnectionTypeOpts specifies options for GetByConnectionType.
 type StatisticGetByConnectionTypeOpts struct {
 	StatFilter
@@ -999,6 +1289,8 @@ type statisticGetByConnectionTypeResponse struct {
 
 // GetByConnectionType gets statistics by connection type.
 func (s *StatisticService) GetByConnectionType(ctx context.Context, opts *StatisticGetByConnectionTypeOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByConnectionType")
+
 	path := "/3.0/stats/getbyconnectiontype"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -1015,6 +1307,20 @@ func (s *StatisticService) GetByConnectionType(ctx context.Context, opts *Statis
 	return body.Stats, resp, nil
 }
 
+// GetByConnectionTypeIter returns an iterator over all pages of GetByConnectionType starting from opts.Page.
+func (s *StatisticService) GetByConnectionTypeIter(ctx context.Context, opts *StatisticGetByConnectionTypeOpts) *StatIter {
+	o := StatisticGetByConnectionTypeOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetByConnectionType(ctx, &o)
+	})
+}
+
 // StatisticGetByOSOpts specifies options for GetByOS.
 type StatisticGetByOSOpts struct {
 	StatFilter
@@ -1032,6 +1338,8 @@ type statisticGetByOSResponse struct {
 
 // GetByOS gets statistics by OS.
 func (s *StatisticService) GetByOS(ctx context.Context, opts *StatisticGetByOSOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByOS")
+
 	path := "/3.0/stats/getbyos"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -1048,6 +1356,20 @@ func (s *StatisticService) GetByOS(ctx context.Context, opts *StatisticGetByOSOp
 	return body.Stats, resp, nil
 }
 
+// GetByOSIter returns an iterator over all pages of GetByOS starting from opts.Page.
+func (s *StatisticService) GetByOSIter(ctx context.Context, opts *StatisticGetByOSOpts) *StatIter {
+	o := StatisticGetByOSOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetByOS(ctx, &o)
+	})
+}
+
 // StatisticGetByVersionsOpts specifies options for GetByVersions.
 type StatisticGetByVersionsOpts struct {
 	StatFilter
@@ -1065,6 +1387,8 @@ type statisticGetByVersionsResponse struct {
 
 // GetByVersions gets statistics by OS version.
 func (s *StatisticService) GetByVersions(ctx context.Context, opts *StatisticGetByVersionsOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByVersions")
+
 	path := "/3.0/stats/getbyversions"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -1081,6 +1405,20 @@ func (s *StatisticService) GetByVersions(ctx context.Context, opts *StatisticGet
 	return body.Stats, resp, nil
 }
 
+// GetByVersionsIter returns an iterator over all pages of GetByVersions starting from opts.Page.
+func (s *StatisticService) GetByVersionsIter(ctx context.Context, opts *StatisticGetByVersionsOpts) *StatIter {
+	o := StatisticGetByVersionsOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetByVersions(ctx, &o)
+	})
+}
+
 // StatisticGetByGoalOpts specifies options for GetByGoal.
 type StatisticGetByGoalOpts struct {
 	StatFilter
@@ -1098,6 +1436,8 @@ type statisticGetByGoalResponse struct {
 
 // GetByGoal gets statistics by goal.
 func (s *StatisticService) GetByGoal(ctx context.Context, opts *StatisticGetByGoalOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByGoal")
+
 	path := "/3.0/stats/getbygoal"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -1114,6 +1454,20 @@ func (s *StatisticService) GetByGoal(ctx context.Context, opts *StatisticGetByGo
 	return body.Stats, resp, nil
 }
 
+// GetByGoalIter returns an iterator over all pages of GetByGoal starting from opts.Page.
+func (s *StatisticService) GetByGoalIter(ctx context.Context, opts *StatisticGetByGoalOpts) *StatIter {
+	o := StatisticGetByGoalOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetByGoal(ctx, &o)
+	})
+}
+
 // StatisticGetByCitiesOpts specifies options for GetByCities.
 type StatisticGetByCitiesOpts struct {
 	StatFilter
@@ -1131,6 +1485,8 @@ type statisticGetByCitiesResponse struct {
 
 // GetByCities gets statistics by cities.
 func (s *StatisticService) GetByCities(ctx context.Context, opts *StatisticGetByCitiesOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByCities")
+
 	path := "/3.0/stats/getbycities"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -1147,6 +1503,20 @@ func (s *StatisticService) GetByCities(ctx context.Context, opts *StatisticGetBy
 	return body.Stats, resp, nil
 }
 
+// GetByCitiesIter returns an iterator over all pages of GetByCities starting from opts.Page.
+func (s *StatisticService) GetByCitiesIter(ctx context.Context, opts *StatisticGetByCitiesOpts) *StatIter {
+	o := StatisticGetByCitiesOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetByCities(ctx, &o)
+	})
+}
+
 // StatisticGetByDevicesOpts specifies options for GetByDevices.
 type StatisticGetByDevicesOpts struct {
 	StatFilter
@@ -1164,6 +1534,8 @@ type statisticGetByDevicesResponse struct {
 
 // GetByDevices gets statistics by device.
 func (s *StatisticService) GetByDevices(ctx context.Context, opts *StatisticGetByDevicesOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByDevices")
+
 	path := "/3.0/stats/getbydevices"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -1180,6 +1552,20 @@ func (s *StatisticService) GetByDevices(ctx context.Context, opts *StatisticGetB
 	return body.Stats, resp, nil
 }
 
+// GetByDevicesIter returns an iterator over all pages of GetByDevices starting from opts.Page.
+func (s *StatisticService) GetByDevicesIter(ctx context.Context, opts *StatisticGetByDevicesOpts) *StatIter {
+	o := StatisticGetByDevicesOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetByDevices(ctx, &o)
+	})
+}
+
 // StatisticGetByDeviceModelsOpts specifies options for GetByDeviceModels.
 type StatisticGetByDeviceModelsOpts struct {
 	StatFilter
@@ -1197,6 +1583,8 @@ type statisticGetByDeviceModelsResponse struct {
 
 // GetByDeviceModels gets statistics by device model.
 func (s *StatisticService) GetByDeviceModels(ctx context.Context, opts *StatisticGetByDeviceModelsOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByDeviceModels")
+
 	path := "/3.0/stats/getbydevicemodels"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -1213,6 +1601,20 @@ func (s *StatisticService) GetByDeviceModels(ctx context.Context, opts *Statisti
 	return body.Stats, resp, nil
 }
 
+// GetByDeviceModelsIter returns an iterator over all pages of GetByDeviceModels starting from opts.Page.
+func (s *StatisticService) GetByDeviceModelsIter(ctx context.Context, opts *StatisticGetByDeviceModelsOpts) *StatIter {
+	o := StatisticGetByDeviceModelsOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetByDeviceModels(ctx, &o)
+	})
+}
+
 // StatisticGetByReferralPaymentsOpts specifies options for GetByReferralPayments.
 type StatisticGetByReferralPaymentsOpts struct {
 	DateFrom    string `schema:"date_from"`          // REQUIRED  Date from (Available: DD-MM-YYYY)
@@ -1233,6 +1635,8 @@ type statisticGetByReferralPaymentsResponse struct {
 
 // GetByReferralPayments gets statistics by referral payments.
 func (s *StatisticService) GetByReferralPayments(ctx context.Context, opts *StatisticGetByReferralPaymentsOpts) ([]*RefPayment, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByReferralPayments")
+
 	path := "/3.0/stats/getreferralpayments"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -1249,6 +1653,20 @@ func (s *StatisticService) GetByReferralPayments(ctx context.Context, opts *Stat
 	return body.RefPayments, resp, nil
 }
 
+// GetByReferralPaymentsIter returns an iterator over all pages of GetByReferralPayments starting from opts.Page.
+func (s *StatisticService) GetByReferralPaymentsIter(ctx context.Context, opts *StatisticGetByReferralPaymentsOpts) *RefPaymentIter {
+	o := StatisticGetByReferralPaymentsOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newRefPaymentIter(ctx, o.Page, func(ctx context.Context, page int) ([]*RefPayment, *Response, error) {
+		o.Page = page
+
+		return s.GetByReferralPayments(ctx, &o)
+	})
+}
+
 // StatisticFindSubsOpts specifies options for FindSubs.
 type StatisticFindSubsOpts struct {
 	Sub1  string `schema:"sub1,omitempty"`  // Sub 1
@@ -1268,6 +1686,8 @@ type statisticFindSubsResponse struct {
 // FindSubs gets sub accounts
 // NOTE: Available only for partner API-Key.
 func (s *StatisticService) FindSubs(ctx context.Context, opts *StatisticFindSubsOpts) ([]*Sub, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.FindSubs")
+
 	path := "/3.0/stats/find-subs"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -1284,6 +1704,20 @@ func (s *StatisticService) FindSubs(ctx context.Context, opts *StatisticFindSubs
 	return body.Subs, resp, nil
 }
 
+// FindSubsIter returns an iterator over all pages of FindSubs starting from opts.Page.
+func (s *StatisticService) FindSubsIter(ctx context.Context, opts *StatisticFindSubsOpts) *SubIter {
+	o := StatisticFindSubsOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newSubIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Sub, *Response, error) {
+		o.Page = page
+
+		return s.FindSubs(ctx, &o)
+	})
+}
+
 // StatisticServerPostbacksOpts specifies options for ServerPostbacks.
 type StatisticServerPostbacksOpts struct {
 	DateFrom string   `schema:"date_from"`           // REQUIRED (Available: YYYY-MM-DD)
@@ -1308,6 +1742,8 @@ type statisticServerPostbacksResponse struct {
 // ServerPostbacks gets server postbacks
 // NOTE  Available only for admin API-Key.
 func (s *StatisticService) ServerPostbacks(ctx context.Context, opts *StatisticServerPostbacksOpts) ([]*StatPostback, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.ServerPostbacks")
+
 	path := "/3.0/stats/serverpostbacks"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -1324,6 +1760,20 @@ func (s *StatisticService) ServerPostbacks(ctx context.Context, opts *StatisticS
 	return body.Postbacks, resp, nil
 }
 
+// ServerPostbacksIter returns an iterator over all pages of ServerPostbacks starting from opts.Page.
+func (s *StatisticService) ServerPostbacksIter(ctx context.Context, opts *StatisticServerPostbacksOpts) *StatPostbackIter {
+	o := StatisticServerPostbacksOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatPostbackIter(ctx, o.Page, func(ctx context.Context, page int) ([]*StatPostback, *Response, error) {
+		o.Page = page
+
+		return s.ServerPostbacks(ctx, &o)
+	})
+}
+
 // StatisticAffiliatePostbacksOpts specifies options for AffiliatePostbacks.
 type StatisticAffiliatePostbacksOpts struct {
 	DateFrom string `schema:"date_from"`           // REQUIRED (Available: YYYY-MM-DD)
@@ -1346,6 +1796,8 @@ type statisticAffiliatePostbacksResponse struct {
 // AffiliatePostbacks gets partner postbacks
 // NOTE  Available only for admin API-Key.
 func (s *StatisticService) AffiliatePostbacks(ctx context.Context, opts *StatisticAffiliatePostbacksOpts) ([]*StatPostback, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.AffiliatePostbacks")
+
 	path := "/3.0/stats/affiliatepostbacks"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -1362,6 +1814,20 @@ func (s *StatisticService) AffiliatePostbacks(ctx context.Context, opts *Statist
 	return body.Postbacks, resp, nil
 }
 
+// AffiliatePostbacksIter returns an iterator over all pages of AffiliatePostbacks starting from opts.Page.
+func (s *StatisticService) AffiliatePostbacksIter(ctx context.Context, opts *StatisticAffiliatePostbacksOpts) *StatPostbackIter {
+	o := StatisticAffiliatePostbacksOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatPostbackIter(ctx, o.Page, func(ctx context.Context, page int) ([]*StatPostback, *Response, error) {
+		o.Page = page
+
+		return s.AffiliatePostbacks(ctx, &o)
+	})
+}
+
 // StatisticCapsOpts specifies options for Caps.
 type StatisticCapsOpts struct {
 	OfferID []int `schema:"offer_id"` // REQUIRED  Offers ID’s
@@ -1374,6 +1840,8 @@ type statisticCapsResponse struct {
 
 // Caps gets stats by cap.
 func (s *StatisticService) Caps(ctx context.Context, opts *StatisticCapsOpts) ([]*StatCap, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.Caps")
+
 	path := "/3.1/stats/caps"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -1407,6 +1875,8 @@ type statisticGetByTrafficbackResponse struct {
 
 // GetByTrafficback gets statistics by trafficback.
 func (s *StatisticService) GetByTrafficback(ctx context.Context, opts *StatisticGetByTrafficbackOpts) ([]*Stat, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.GetByTrafficback")
+
 	path := "/3.0/stats/getbytrafficback"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -1423,6 +1893,20 @@ func (s *StatisticService) GetByTrafficback(ctx context.Context, opts *Statistic
 	return body.Stats, resp, nil
 }
 
+// GetByTrafficbackIter returns an iterator over all pages of GetByTrafficback starting from opts.Page.
+func (s *StatisticService) GetByTrafficbackIter(ctx context.Context, opts *StatisticGetByTrafficbackOpts) *StatIter {
+	o := StatisticGetByTrafficbackOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	return newStatIter(ctx, o.Page, func(ctx context.Context, page int) ([]*Stat, *Response, error) {
+		o.Page = page
+
+		return s.GetByTrafficback(ctx, &o)
+	})
+}
+
 // StatisticRetentionRateOpts specifies options for RetentionRate.
 type StatisticRetentionRateOpts struct {
 	DateFrom    string   `schema:"date_from"`              // REQUIRED  Date from (Available: YYYY-MM-DD)
@@ -1441,6 +1925,8 @@ type statisticRetentionRateResponse struct {
 
 // RetentionRate gets stats retention rate.
 func (s *StatisticService) RetentionRate(ctx context.Context, opts *StatisticRetentionRateOpts) ([]*RetentionRate, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.RetentionRate")
+
 	path := "/3.0/stats/retentionrate"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -1476,6 +1962,8 @@ type statisticTimeToActionResponse struct {
 
 // TimeToAction gets Time to action report.
 func (s *StatisticService) TimeToAction(ctx context.Context, opts *StatisticTimeToActionOpts) ([]*TimeToAction, *Response, error) {
+	ctx = withServiceMethod(ctx, "Statistic.TimeToAction")
+
 	path := "/3.0/stats/time-to-action"
 
 	req, err := s.client.NewRequestOpts(ctx, http.MethodGet, path, opts, nil, false)
@@ -0,0 +1,433 @@
+// Package targeting evaluates offer targeting groups locally to tell whether
+// a click would be accepted by an offer.
+//
+// A click is accepted if no targeting group applies to it or if it passes
+// every rule of at least one applicable group. Personal groups, the ones
+// with affiliate IDs, apply only to clicks of those affiliates and replace
+// the general groups for them: if a personal group applies to the click,
+// the general groups are not evaluated.
+package targeting
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/clobucks/go-sdk/affise"
+)
+
+// Click describes a click to evaluate.
+type Click struct {
+	Country     string            // Country ISO code, e.g. US
+	Region      int               // Region code
+	City        int               // City code
+	IP          string            // IPv4 or IPv6 address
+	Browser     string            // Browser name, e.g. Chrome
+	Brand       string            // Device brand, e.g. Apple
+	DeviceType  string            // Device type (mobile, tablet, desktop, ...)
+	Connection  string            // Connection type (wi-fi, cellular)
+	ISP         string            // ISP name
+	OS          string            // OS name, e.g. iOS
+	OSVersion   string            // OS version, e.g. 14.2
+	AffiliateID uint64            // Affiliate ID
+	Subs        map[string]string // Sub values by number, e.g. "1" for sub1
+	Proxy       bool              // The click comes through a proxy
+}
+
+// GroupResult is the evaluation of a targeting group.
+type GroupResult struct {
+	Group   int    // Index of the targeting group
+	Allowed bool   // The click passes every rule of the group
+	Rule    string // Rule denying the click, or the rules matched by an allowed click separated by commas
+	Reason  string // Explanation
+}
+
+// Decision is the evaluation of a click against targeting groups.
+type Decision struct {
+	Allowed bool
+	Group   int    // Group accepting the click, or the first applicable group denying it. -1 if no group applies.
+	Rule    string // Rule of the group, see GroupResult
+	Reason  string // Explanation
+	Groups  []GroupResult
+}
+
+func (d *Decision) String() string {
+	verdict := "denied"
+	if d.Allowed {
+		verdict = "allowed"
+	}
+	if d.Group < 0 {
+		return fmt.Sprintf("%s: %s", verdict, d.Reason)
+	}
+
+	return fmt.Sprintf("%s by group %d: %s", verdict, d.Group, d.Reason)
+}
+
+// EvaluateOffer evaluates the click against the targeting of the offer.
+func EvaluateOffer(offer *affise.Offer, click *Click) *Decision {
+	return Evaluate(offer.Targeting, click)
+}
+
+// Evaluate evaluates the click against the targeting groups.
+func Evaluate(groups []affise.TargetingGroup, click *Click) *Decision {
+	personal := false
+	for i := range groups {
+		personal = personal || containsUint(groups[i].AffiliateID, click.AffiliateID)
+	}
+
+	d := &Decision{Group: -1}
+	for i := range groups {
+		g := &groups[i]
+		if personal && !containsUint(g.AffiliateID, click.AffiliateID) || !personal && len(g.AffiliateID) != 0 {
+			continue
+		}
+
+		r := evaluateGroup(g, click)
+		r.Group = i
+		d.Groups = append(d.Groups, r)
+		if r.Allowed && !d.Allowed {
+			d.Allowed, d.Group, d.Rule, d.Reason = true, i, r.Rule, r.Reason
+		}
+	}
+
+	switch {
+	case len(d.Groups) == 0:
+		d.Allowed = true
+		d.Reason = "no targeting group applies"
+	case !d.Allowed:
+		r := d.Groups[0]
+		d.Group, d.Rule, d.Reason = r.Group, r.Rule, r.Reason
+	}
+
+	return d
+}
+
+// evaluator runs the rules of a group until one denies the click.
+type evaluator struct {
+	matched []string
+	denied  *GroupResult
+}
+
+// check records the rule result. ok is false if the rule denies the click.
+func (e *evaluator) check(rule string, applies, ok bool, format string, args ...interface{}) {
+	if e.denied != nil || !applies {
+		return
+	}
+	if ok {
+		e.matched = append(e.matched, rule)
+
+		return
+	}
+	e.denied = &GroupResult{Rule: rule, Reason: fmt.Sprintf(format, args...)}
+}
+
+func evaluateGroup(g *affise.TargetingGroup, c *Click) GroupResult {
+	e := &evaluator{}
+
+	e.check("country.allow", len(g.CountryAllow) != 0, containsFold(g.CountryAllow, c.Country),
+		"country %q is not in country.allow %v", c.Country, g.CountryAllow)
+	e.check("country.deny", len(g.CountryDeny) != 0, !containsFold(g.CountryDeny, c.Country),
+		"country %q is in country.deny", c.Country)
+
+	regions, ok := lookupInts(g.RegionAllow, c.Country)
+	e.check("region.allow", ok, containsInt(regions, c.Region),
+		"region %d is not in region.allow[%s] %v", c.Region, c.Country, regions)
+	regions, ok = lookupInts(g.RegionDeny, c.Country)
+	e.check("region.deny", ok, !containsInt(regions, c.Region),
+		"region %d is in region.deny[%s]", c.Region, c.Country)
+
+	cities, ok := lookupInts(g.CityAllow, c.Country)
+	e.check("city.allow", ok, containsInt(cities, c.City),
+		"city %d is not in city.allow[%s] %v", c.City, c.Country, cities)
+	cities, ok = lookupInts(g.CityDeny, c.Country)
+	e.check("city.deny", ok, !containsInt(cities, c.City),
+		"city %d is in city.deny[%s]", c.City, c.Country)
+
+	isps, ok := lookupStrings(g.ISPAllow, c.Country)
+	e.check("isp.allow", ok, containsFold(isps, c.ISP),
+		"ISP %q is not in isp.allow[%s] %v", c.ISP, c.Country, isps)
+	isps, ok = lookupStrings(g.ISPDeny, c.Country)
+	e.check("isp.deny", ok, !containsFold(isps, c.ISP),
+		"ISP %q is in isp.deny[%s]", c.ISP, c.Country)
+
+	ip := net.ParseIP(c.IP)
+	allowed, err := matchIP(g.IPAllow, ip)
+	e.check("ip.allow", len(g.IPAllow) != 0, allowed && err == nil,
+		"IP %q is not in ip.allow %v%s", c.IP, g.IPAllow, errSuffix(err))
+	denied, err := matchIP(g.IPDeny, ip)
+	e.check("ip.deny", len(g.IPDeny) != 0, !denied && err == nil,
+		"IP %q is in ip.deny%s", c.IP, errSuffix(err))
+
+	e.check("browser.allow", len(g.BrowserAllow) != 0, containsFold(g.BrowserAllow, c.Browser),
+		"browser %q is not in browser.allow %v", c.Browser, g.BrowserAllow)
+	e.check("browser.deny", len(g.BrowserDeny) != 0, !containsFold(g.BrowserDeny, c.Browser),
+		"browser %q is in browser.deny", c.Browser)
+	e.check("brand.allow", len(g.BrandAllow) != 0, containsFold(g.BrandAllow, c.Brand),
+		"brand %q is not in brand.allow %v", c.Brand, g.BrandAllow)
+	e.check("brand.deny", len(g.BrandDeny) != 0, !containsFold(g.BrandDeny, c.Brand),
+		"brand %q is in brand.deny", c.Brand)
+	e.check("device_type", len(g.DeviceType) != 0, containsFold(g.DeviceType, c.DeviceType),
+		"device type %q is not in device_type %v", c.DeviceType, g.DeviceType)
+	e.check("connection", len(g.Connection) != 0, containsFold(g.Connection, c.Connection),
+		"connection %q is not in connection %v", c.Connection, g.Connection)
+
+	e.check("os.allow", len(g.OSAllow) != 0, matchOS(g.OSAllow, c.OS, c.OSVersion),
+		"OS %q %q does not match os.allow %s", c.OS, c.OSVersion, formatOS(g.OSAllow))
+	e.check("os.deny", len(g.OSDeny) != 0, !matchOS(g.OSDeny, c.OS, c.OSVersion),
+		"OS %q %q matches os.deny %s", c.OS, c.OSVersion, formatOS(g.OSDeny))
+
+	for _, sub := range sortedKeys(g.SubAllow) {
+		e.check("sub.allow", true, contains(g.SubAllow[sub], c.Subs[sub]),
+			"sub%s %q is not in sub.allow[%s] %v", sub, c.Subs[sub], sub, g.SubAllow[sub])
+	}
+	for _, sub := range sortedKeys(g.SubDeny) {
+		e.check("sub.deny", true, !contains(g.SubDeny[sub], c.Subs[sub]),
+			"sub%s %q is in sub.deny[%s]", sub, c.Subs[sub], sub)
+	}
+	for _, key := range sortedGroupKeys(g.SubDenyGroups) {
+		group := g.SubDenyGroups[key]
+		e.check("sub.deny_groups", len(group) != 0, !matchSubs(group, c.Subs),
+			"subs match sub.deny_groups[%s] %v", key, group)
+	}
+
+	e.check("block_proxy", g.BlockProxy != 0, !c.Proxy, "proxy clicks are blocked")
+
+	if e.denied != nil {
+		return *e.denied
+	}
+	if len(e.matched) == 0 {
+		return GroupResult{Allowed: true, Reason: "the group has no restrictions"}
+	}
+
+	return GroupResult{
+		Allowed: true,
+		Rule:    strings.Join(e.matched, ","),
+		Reason:  "passed " + strings.Join(e.matched, ", "),
+	}
+}
+
+// matchIP reports whether the IP is in one of the addresses, ranges like
+// "100.0.0.1-100.0.0.255" or networks like "222.1.1.20/26".
+func matchIP(list []string, ip net.IP) (bool, error) {
+	if len(list) == 0 {
+		return false, nil
+	}
+	if ip == nil {
+		return false, fmt.Errorf("invalid IP")
+	}
+
+	for _, item := range list {
+		item = strings.TrimSpace(item)
+		switch {
+		case strings.Contains(item, "/"):
+			_, network, err := net.ParseCIDR(item)
+			if err != nil {
+				return false, fmt.Errorf("invalid network %q", item)
+			}
+			if network.Contains(ip) {
+				return true, nil
+			}
+		case strings.Contains(item, "-"):
+			parts := strings.SplitN(item, "-", 2)
+			from, to := net.ParseIP(strings.TrimSpace(parts[0])), net.ParseIP(strings.TrimSpace(parts[1]))
+			if from == nil || to == nil {
+				return false, fmt.Errorf("invalid range %q", item)
+			}
+			if bytes.Compare(ip.To16(), from.To16()) >= 0 && bytes.Compare(ip.To16(), to.To16()) <= 0 {
+				return true, nil
+			}
+		default:
+			addr := net.ParseIP(item)
+			if addr == nil {
+				return false, fmt.Errorf("invalid IP %q", item)
+			}
+			if addr.Equal(ip) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func errSuffix(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return ": " + err.Error()
+}
+
+// matchOS reports whether the OS matches one of the items. Items without a
+// comparison or version match any version.
+func matchOS(list []affise.OS, name, version string) bool {
+	for _, os := range list {
+		if !strings.EqualFold(os.Name, name) {
+			continue
+		}
+		if os.Comparison == "" || os.Version == "" {
+			return true
+		}
+
+		n := compareVersions(version, os.Version)
+		switch strings.ToUpper(os.Comparison) {
+		case "LT":
+			if n < 0 {
+				return true
+			}
+		case "LTE":
+			if n <= 0 {
+				return true
+			}
+		case "EQ":
+			if n == 0 {
+				return true
+			}
+		case "GT":
+			if n > 0 {
+				return true
+			}
+		case "GTE":
+			if n >= 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func formatOS(list []affise.OS) string {
+	items := make([]string, 0, len(list))
+	for _, os := range list {
+		items = append(items, strings.TrimSpace(strings.Join([]string{os.Name, os.Comparison, os.Version}, " ")))
+	}
+
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// compareVersions compares dotted versions like "14.2" and "14.10". Missing
+// parts are zero; parts that are not numbers are compared as strings.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		x, y := "0", "0"
+		if i < len(as) && as[i] != "" {
+			x = as[i]
+		}
+		if i < len(bs) && bs[i] != "" {
+			y = bs[i]
+		}
+
+		xn, xErr := strconv.Atoi(x)
+		yn, yErr := strconv.Atoi(y)
+		switch {
+		case xErr == nil && yErr == nil && xn != yn:
+			if xn < yn {
+				return -1
+			}
+
+			return 1
+		case (xErr != nil || yErr != nil) && x != y:
+			return strings.Compare(x, y)
+		}
+	}
+
+	return 0
+}
+
+// matchSubs reports whether every sub of the group has the value. An empty
+// value matches an empty sub.
+func matchSubs(group map[string]string, subs map[string]string) bool {
+	for sub, value := range group {
+		if subs[sub] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// lookupInts returns the list of the country. Keys are matched case-insensitively.
+func lookupInts(m map[string][]int, country string) ([]int, bool) {
+	for k, v := range m {
+		if strings.EqualFold(k, country) {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+func lookupStrings(m map[string][]string, country string) ([]string, bool) {
+	for k, v := range m {
+		if strings.EqualFold(k, country) {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsInt(list []int, n int) bool {
+	for _, v := range list {
+		if v == n {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsUint(list []uint64, n uint64) bool {
+	for _, v := range list {
+		if v == n {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func sortedGroupKeys(m map[string]map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,147 @@
+package targeting_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/clobucks/go-sdk/affise"
+	"github.com/clobucks/go-sdk/affise/targeting"
+)
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	group := affise.TargetingGroup{
+		CountryAllow:  []string{"US", "CA"},
+		RegionAllow:   map[string][]int{"US": {33}},
+		CityDeny:      map[string][]int{"CA": {57}},
+		ISPAllow:      map[string][]string{"US": {"Att"}},
+		ISPDeny:       map[string][]string{"CA": {"Rogers"}},
+		IPDeny:        []string{"10.0.0.1", "100.0.0.1-100.0.0.255", "222.1.1.0/26"},
+		BrowserDeny:   []string{"Edge"},
+		DeviceType:    []string{"mobile", "tablet"},
+		OSAllow:       []affise.OS{{Name: "iOS", Comparison: "GTE", Version: "14"}, {Name: "Android", Comparison: "LT", Version: "12.1"}},
+		OSDeny:        []affise.OS{{Name: "iOS", Comparison: "EQ", Version: "14.1"}},
+		SubAllow:      map[string][]string{"1": {"a", "b"}},
+		SubDenyGroups: map[string]map[string]string{"0": {"2": "x", "3": ""}},
+		BlockProxy:    1,
+	}
+	click := func(f func(c *targeting.Click)) *targeting.Click {
+		c := &targeting.Click{
+			Country:    "US",
+			Region:     33,
+			ISP:        "ATT",
+			IP:         "8.8.8.8",
+			Browser:    "Safari",
+			DeviceType: "mobile",
+			OS:         "iOS",
+			OSVersion:  "14.2",
+			Subs:       map[string]string{"1": "a"},
+		}
+		if f != nil {
+			f(c)
+		}
+
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		click   *targeting.Click
+		allowed bool
+		rule    string
+	}{
+		{"Allowed", click(nil), true, "country.allow,region.allow,isp.allow,ip.deny,browser.deny,device_type,os.allow,os.deny,sub.allow,sub.deny_groups,block_proxy"},
+		{"Country", click(func(c *targeting.Click) { c.Country = "DE" }), false, "country.allow"},
+		{"Region", click(func(c *targeting.Click) { c.Region = 1 }), false, "region.allow"},
+		{"CityOfAnotherCountry", click(func(c *targeting.Click) { c.City = 57 }), true, ""},
+		{"City", click(func(c *targeting.Click) { c.Country, c.City = "CA", 57 }), false, "city.deny"},
+		{"ISP", click(func(c *targeting.Click) { c.ISP = "Verizon" }), false, "isp.allow"},
+		{"ISPDeny", click(func(c *targeting.Click) { c.Country, c.ISP = "CA", "rogers" }), false, "isp.deny"},
+		{"IP", click(func(c *targeting.Click) { c.IP = "10.0.0.1" }), false, "ip.deny"},
+		{"IPRange", click(func(c *targeting.Click) { c.IP = "100.0.0.7" }), false, "ip.deny"},
+		{"CIDR", click(func(c *targeting.Click) { c.IP = "222.1.1.63" }), false, "ip.deny"},
+		{"OutsideCIDR", click(func(c *targeting.Click) { c.IP = "222.1.1.64" }), true, ""},
+		{"Browser", click(func(c *targeting.Click) { c.Browser = "edge" }), false, "browser.deny"},
+		{"DeviceType", click(func(c *targeting.Click) { c.DeviceType = "desktop" }), false, "device_type"},
+		{"OSVersion", click(func(c *targeting.Click) { c.OSVersion = "13.7" }), false, "os.allow"},
+		{"OSLessThan", click(func(c *targeting.Click) { c.OS, c.OSVersion = "android", "12.0.1" }), true, ""},
+		{"OSNotLessThan", click(func(c *targeting.Click) { c.OS, c.OSVersion = "Android", "12.1" }), false, "os.allow"},
+		{"OSDeny", click(func(c *targeting.Click) { c.OSVersion = "14.1" }), false, "os.deny"},
+		{"Sub", click(func(c *targeting.Click) { c.Subs["1"] = "c" }), false, "sub.allow"},
+		{"SubDenyGroup", click(func(c *targeting.Click) { c.Subs["2"] = "x" }), false, "sub.deny_groups"},
+		{"SubDenyGroupNotEmpty", click(func(c *targeting.Click) { c.Subs["2"], c.Subs["3"] = "x", "y" }), true, ""},
+		{"Proxy", click(func(c *targeting.Click) { c.Proxy = true }), false, "block_proxy"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := targeting.Evaluate([]affise.TargetingGroup{group}, tt.click)
+			require.Equal(t, tt.allowed, d.Allowed, d.Reason)
+			require.Equal(t, 0, d.Group)
+			if tt.rule != "" {
+				require.Equal(t, tt.rule, d.Rule)
+			}
+		})
+	}
+}
+
+func TestEvaluate_Groups(t *testing.T) {
+	t.Parallel()
+
+	groups := []affise.TargetingGroup{
+		{CountryAllow: []string{"US"}},
+		{CountryAllow: []string{"DE"}, AffiliateID: []uint64{7}},
+		{CountryAllow: []string{"FR"}, BrowserDeny: []string{"Edge"}},
+	}
+
+	t.Run("AnyGroup", func(t *testing.T) {
+		t.Parallel()
+
+		d := targeting.Evaluate(groups, &targeting.Click{Country: "FR"})
+		require.True(t, d.Allowed)
+		require.Equal(t, 2, d.Group)
+		require.Len(t, d.Groups, 2, "the personal group does not apply")
+		require.Equal(t, "allowed by group 2: passed country.allow, browser.deny", d.String())
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		t.Parallel()
+
+		d := targeting.Evaluate(groups, &targeting.Click{Country: "DE"})
+		require.False(t, d.Allowed)
+		require.Equal(t, 0, d.Group)
+		require.Equal(t, "country.allow", d.Rule)
+		require.Equal(t, `country "DE" is not in country.allow [US]`, d.Reason)
+		require.Equal(t, "country.allow", d.Groups[1].Rule)
+	})
+
+	t.Run("PersonalGroup", func(t *testing.T) {
+		t.Parallel()
+
+		d := targeting.Evaluate(groups, &targeting.Click{Country: "DE", AffiliateID: 7})
+		require.True(t, d.Allowed)
+		require.Equal(t, 1, d.Group)
+	})
+
+	t.Run("PersonalOnly", func(t *testing.T) {
+		t.Parallel()
+
+		d := targeting.Evaluate(groups, &targeting.Click{Country: "US", AffiliateID: 7})
+		require.False(t, d.Allowed, "the general groups are not evaluated")
+		require.Equal(t, 1, d.Group)
+		require.Equal(t, `country "US" is not in country.allow [DE]`, d.Reason)
+		require.Len(t, d.Groups, 1)
+	})
+
+	t.Run("NoTargeting", func(t *testing.T) {
+		t.Parallel()
+
+		d := targeting.EvaluateOffer(&affise.Offer{}, &targeting.Click{Country: "DE"})
+		require.True(t, d.Allowed)
+		require.Equal(t, -1, d.Group)
+	})
+}
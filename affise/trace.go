@@ -0,0 +1,96 @@
+package affise
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Tracer starts a span for every API call. It is designed to be a thin
+// adapter over OpenTelemetry or any other tracing library.
+type Tracer interface {
+	// Start starts a span named like "affise.Statistic.GetByDate". The returned
+	// context carries the span and is used for the HTTP request.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single traced API call.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// Span attribute keys.
+const (
+	AttrHTTPMethod   = "http.method"
+	AttrHTTPStatus   = "http.status_code"
+	AttrPath         = "affise.path"
+	AttrPage         = "affise.page"
+	AttrLimit        = "affise.limit"
+	AttrAttempts     = "affise.attempts"
+	AttrMetaStatus   = "affise.meta.status"
+	AttrMetaMessage  = "affise.meta.message"
+	AttrTotalCount   = "affise.pagination.total_count"
+	AttrResponseSize = "affise.response.bytes"
+)
+
+// spanName returns the span name for the request.
+func spanName(r *http.Request) string {
+	if name := ServiceMethod(r.Context()); name != "" {
+		return "affise." + name
+	}
+
+	return "affise.request"
+}
+
+// startSpan starts a span for the request and returns the request bound to the span context.
+func (c *Client) startSpan(r *http.Request) (*http.Request, Span) {
+	if c.tracer == nil {
+		return r, nil
+	}
+
+	ctx, span := c.tracer.Start(r.Context(), spanName(r))
+	span.SetAttribute(AttrHTTPMethod, r.Method)
+	span.SetAttribute(AttrPath, r.URL.Path)
+
+	query := r.URL.Query()
+	if page := query.Get("page"); page != "" {
+		span.SetAttribute(AttrPage, page)
+	}
+	if limit := query.Get("limit"); limit != "" {
+		span.SetAttribute(AttrLimit, limit)
+	}
+
+	return r.WithContext(ctx), span
+}
+
+// endSpan records the call result and ends the span.
+func endSpan(span Span, cl *CallInfo) {
+	if span == nil {
+		return
+	}
+	defer span.End()
+
+	span.SetAttribute(AttrAttempts, cl.Attempts)
+	span.SetAttribute(AttrResponseSize, cl.Bytes)
+	if cl.Status != 0 {
+		span.SetAttribute(AttrHTTPStatus, cl.Status)
+	}
+	if cl.Meta != nil {
+		span.SetAttribute(AttrMetaStatus, cl.Meta.Status)
+		if cl.Meta.Pagination != nil {
+			span.SetAttribute(AttrTotalCount, cl.Meta.Pagination.TotalCount)
+		}
+	}
+
+	if cl.Err == nil {
+		return
+	}
+
+	var respErr *ResponseErr
+	if errors.As(cl.Err, &respErr) {
+		span.SetAttribute(AttrMetaMessage, respErr.MetaMessage)
+	}
+	span.RecordError(redactError(cl.Err))
+}
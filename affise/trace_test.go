@@ -0,0 +1,114 @@
+package affise_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/clobucks/go-sdk/affise"
+)
+
+type testSpanKey struct{}
+
+type testSpan struct {
+	name  string
+	attrs map[string]interface{}
+	errs  []error
+	ended bool
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) { s.attrs[key] = value }
+func (s *testSpan) RecordError(err error)                      { s.errs = append(s.errs, err) }
+func (s *testSpan) End()                                       { s.ended = true }
+
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (tr *testTracer) Start(ctx context.Context, name string) (context.Context, affise.Span) {
+	span := &testSpan{name: name, attrs: make(map[string]interface{})}
+
+	tr.mu.Lock()
+	tr.spans = append(tr.spans, span)
+	tr.mu.Unlock()
+
+	return context.WithValue(ctx, testSpanKey{}, span), span
+}
+
+func TestClient_WithTracer(t *testing.T) {
+	t.Parallel()
+	t.Run("Span", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		defer env.teardown()
+
+		env.mockHandle(t, "3.0.stats.getbydate@get.json", http.MethodGet, "/3.0/stats/getbydate", http.StatusOK)
+		tracer := &testTracer{}
+
+		var propagated interface{}
+		middleware := func(next affise.RoundTripFunc) affise.RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				propagated = req.Context().Value(testSpanKey{})
+
+				return next(req)
+			}
+		}
+		client, err := affise.NewClient(
+			affise.WithBaseURL(env.Server.URL),
+			affise.WithTracer(tracer),
+			affise.WithMiddleware(middleware),
+		)
+		require.NoError(t, err)
+
+		opts := &affise.StatisticGetByDateOpts{Page: 2, Limit: 1}
+		_, _, err = client.Statistic.GetByDate(env.Ctx, opts)
+		require.NoError(t, err)
+
+		require.Len(t, tracer.spans, 1)
+		span := tracer.spans[0]
+		require.Equal(t, "affise.Statistic.GetByDate", span.name)
+		require.True(t, span.ended)
+		require.Empty(t, span.errs)
+		require.Same(t, span, propagated)
+		require.Equal(t, "/3.0/stats/getbydate", span.attrs[affise.AttrPath])
+		require.Equal(t, "2", span.attrs[affise.AttrPage])
+		require.Equal(t, "1", span.attrs[affise.AttrLimit])
+		require.Equal(t, 1, span.attrs[affise.AttrMetaStatus])
+		require.Equal(t, http.StatusOK, span.attrs[affise.AttrHTTPStatus])
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		defer env.teardown()
+
+		env.Mux.HandleFunc("/3.0/offer/1", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"status":2,"error":"Not found","message":"Offer not found"}`))
+		})
+		tracer := &testTracer{}
+		client, err := affise.NewClient(
+			affise.WithBaseURL(env.Server.URL),
+			affise.WithTracer(tracer),
+		)
+		require.NoError(t, err)
+
+		_, _, err = client.Offer.Get(env.Ctx, 1)
+		require.Error(t, err)
+
+		require.Len(t, tracer.spans, 1)
+		span := tracer.spans[0]
+		require.Equal(t, "affise.Offer.Get", span.name)
+		require.True(t, span.ended)
+		require.Len(t, span.errs, 1)
+		require.True(t, errors.Is(span.errs[0], affise.ErrNotFound))
+		require.Equal(t, 2, span.attrs[affise.AttrMetaStatus])
+		require.Equal(t, "Offer not found", span.attrs[affise.AttrMetaMessage])
+	})
+}
@@ -0,0 +1,281 @@
+// Package tracking builds and parses Affise tracking links like
+// "https://track.example.com/click?pid=7&offer_id=42&sub1=abc".
+package tracking
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/clobucks/go-sdk/affise"
+	"github.com/clobucks/go-sdk/affise/internal/macro"
+)
+
+// Parameters of tracking links.
+const (
+	ParamAffiliateID = "pid"
+	ParamOfferID     = "offer_id"
+	ParamLanding     = "l"
+	ParamAffSub      = "aff_sub"
+	ParamDeepLink    = "url"
+)
+
+// MaxSub is the number of sub parameters sub1..sub8.
+const MaxSub = 8
+
+var (
+	// ErrNoDomain is returned for offers without DomainURL.
+	ErrNoDomain = errors.New("tracking: offer has no tracking domain")
+	// ErrInvalidSub is returned for sub values with characters other than letters, numbers and ,._-{}+=/:~.
+	ErrInvalidSub = errors.New("tracking: invalid sub value")
+	// ErrDeepLinkNotAllowed is returned for deeplinks to offers without AllowDeeplink.
+	ErrDeepLinkNotAllowed = errors.New("tracking: deeplinks are not allowed for the offer")
+	// ErrUnknownLanding is returned for landings not found in the offer.
+	ErrUnknownLanding = errors.New("tracking: unknown landing")
+	// ErrNotTrackingLink is returned by Parse for URLs that are not tracking links.
+	ErrNotTrackingLink = errors.New("tracking: not a tracking link")
+)
+
+// Link is a tracking link.
+type Link struct {
+	HTTPS       bool
+	Domain      string         // Tracking domain, e.g. track.example.com
+	AffiliateID int            // pid
+	OfferID     int            // offer_id
+	LandingID   int            // l
+	Subs        [MaxSub]string // sub1..sub8
+	AffSub      string         // aff_sub
+	DeepLink    string         // url
+	Params      url.Values     // Other parameters
+}
+
+// String returns the link URL. Parameters are written in a fixed order.
+func (l *Link) String() string {
+	scheme := "http"
+	if l.HTTPS {
+		scheme = "https"
+	}
+
+	q := []string{
+		param(ParamAffiliateID, strconv.Itoa(l.AffiliateID)),
+		param(ParamOfferID, strconv.Itoa(l.OfferID)),
+	}
+	if l.LandingID != 0 {
+		q = append(q, param(ParamLanding, strconv.Itoa(l.LandingID)))
+	}
+	for i, sub := range l.Subs {
+		if sub != "" {
+			q = append(q, param(subParam(i+1), sub))
+		}
+	}
+	if l.AffSub != "" {
+		q = append(q, param(ParamAffSub, l.AffSub))
+	}
+	if l.DeepLink != "" {
+		q = append(q, param(ParamDeepLink, l.DeepLink))
+	}
+	if extra := l.Params.Encode(); extra != "" {
+		q = append(q, extra)
+	}
+
+	return fmt.Sprintf("%s://%s/click?%s", scheme, l.Domain, strings.Join(q, "&"))
+}
+
+// Sub returns the value of the sub parameter n (1..8).
+func (l *Link) Sub(n int) string {
+	if n < 1 || n > MaxSub {
+		return ""
+	}
+
+	return l.Subs[n-1]
+}
+
+// Validate checks the sub values.
+func (l *Link) Validate() error {
+	for i, sub := range l.Subs {
+		if err := ValidateSub(sub); err != nil {
+			return fmt.Errorf("%s: %w", subParam(i+1), err)
+		}
+	}
+	if err := ValidateSub(l.AffSub); err != nil {
+		return fmt.Errorf("%s: %w", ParamAffSub, err)
+	}
+
+	return nil
+}
+
+// An Option is used to configure a link built by Build.
+type Option func(*Link) error
+
+// WithSub sets the value of the sub parameter n (1..8). Macros like
+// "{clickid}" are allowed.
+func WithSub(n int, value string) Option {
+	return func(l *Link) error {
+		if n < 1 || n > MaxSub {
+			return fmt.Errorf("%w: sub%d is out of sub1..sub%d", ErrInvalidSub, n, MaxSub)
+		}
+		l.Subs[n-1] = value
+
+		return nil
+	}
+}
+
+// WithAffSub sets the aff_sub parameter.
+func WithAffSub(value string) Option {
+	return func(l *Link) error {
+		l.AffSub = value
+
+		return nil
+	}
+}
+
+// WithDeepLink sets the URL the click is redirected to. The offer must allow deeplinks.
+func WithDeepLink(rawURL string) Option {
+	return func(l *Link) error {
+		u, err := url.Parse(rawURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("tracking: invalid deeplink %q", rawURL)
+		}
+		l.DeepLink = rawURL
+
+		return nil
+	}
+}
+
+// WithLanding selects the landing of the offer by ID.
+func WithLanding(id int) Option {
+	return func(l *Link) error {
+		l.LandingID = id
+
+		return nil
+	}
+}
+
+// WithParam adds another parameter.
+func WithParam(key, value string) Option {
+	return func(l *Link) error {
+		if l.Params == nil {
+			l.Params = url.Values{}
+		}
+		l.Params.Add(key, value)
+
+		return nil
+	}
+}
+
+// Build returns the tracking link of the offer for the affiliate. The offer
+// needs DomainURL; UseHTTPS selects the scheme.
+func Build(offer *affise.Offer, affiliateID int, options ...Option) (*Link, error) {
+	if offer.DomainURL == "" {
+		return nil, fmt.Errorf("%w: offer %d", ErrNoDomain, offer.ID)
+	}
+
+	l := &Link{
+		HTTPS:       offer.UseHTTPS,
+		Domain:      offer.DomainURL,
+		AffiliateID: affiliateID,
+		OfferID:     offer.ID,
+	}
+	for _, option := range options {
+		if err := option(l); err != nil {
+			return nil, err
+		}
+	}
+
+	if l.DeepLink != "" && offer.AllowDeeplink == 0 {
+		return nil, fmt.Errorf("%w: offer %d", ErrDeepLinkNotAllowed, offer.ID)
+	}
+	if l.LandingID != 0 && !hasLanding(offer.Landings, l.LandingID) {
+		return nil, fmt.Errorf("%w: %d of offer %d", ErrUnknownLanding, l.LandingID, offer.ID)
+	}
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Parse decodes the tracking link.
+func Parse(rawURL string) (*Link, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotTrackingLink, err)
+	}
+	if u.Path != "/click" {
+		return nil, fmt.Errorf("%w: path %q", ErrNotTrackingLink, u.Path)
+	}
+
+	q := u.Query()
+	l := &Link{HTTPS: u.Scheme == "https", Domain: u.Host}
+	if l.AffiliateID, err = intParam(q, ParamAffiliateID); err != nil {
+		return nil, err
+	}
+	if l.OfferID, err = intParam(q, ParamOfferID); err != nil {
+		return nil, err
+	}
+	if q.Get(ParamLanding) != "" {
+		if l.LandingID, err = intParam(q, ParamLanding); err != nil {
+			return nil, err
+		}
+	}
+	for i := range l.Subs {
+		l.Subs[i] = q.Get(subParam(i + 1))
+		q.Del(subParam(i + 1))
+	}
+	l.AffSub = q.Get(ParamAffSub)
+	l.DeepLink = q.Get(ParamDeepLink)
+	for _, k := range []string{ParamAffiliateID, ParamOfferID, ParamLanding, ParamAffSub, ParamDeepLink} {
+		q.Del(k)
+	}
+	if len(q) != 0 {
+		l.Params = q
+	}
+
+	return l, nil
+}
+
+// ValidateSub checks that the value has only letters, numbers and the
+// symbols ,._-{}+=/:~ allowed in sub values.
+func ValidateSub(value string) error {
+	for _, r := range value {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune(",._-{}+=/:~", r):
+		default:
+			return fmt.Errorf("%w %q: character %q is not allowed", ErrInvalidSub, value, r)
+		}
+	}
+
+	return nil
+}
+
+func intParam(q url.Values, key string) (int, error) {
+	n, err := strconv.Atoi(q.Get(key))
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid %s %q", ErrNotTrackingLink, key, q.Get(key))
+	}
+
+	return n, nil
+}
+
+func hasLanding(landings []affise.Landing, id int) bool {
+	for _, l := range landings {
+		if l.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func subParam(n int) string {
+	return "sub" + strconv.Itoa(n)
+}
+
+// param encodes the parameter keeping macros like {clickid} readable for
+// traffic sources replacing them.
+func param(key, value string) string {
+	return key + "=" + macro.QueryEscape(value)
+}
@@ -0,0 +1,100 @@
+package tracking_test
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/clobucks/go-sdk/affise"
+	"github.com/clobucks/go-sdk/affise/tracking"
+)
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	offer := &affise.Offer{
+		ID:            902,
+		DomainURL:     "affise.tds",
+		UseHTTPS:      true,
+		AllowDeeplink: 1,
+		Landings:      []affise.Landing{{ID: 3, Title: "Main"}},
+	}
+
+	t.Run("Link", func(t *testing.T) {
+		t.Parallel()
+
+		l, err := tracking.Build(offer, 610,
+			tracking.WithSub(1, "{clickid}"),
+			tracking.WithSub(8, "a.b-c"),
+			tracking.WithAffSub("x+y"),
+			tracking.WithLanding(3),
+			tracking.WithDeepLink("https://shop.example.com/item?id=1"),
+			tracking.WithParam("fbclid", "q w"),
+		)
+		require.NoError(t, err)
+		require.Equal(t, "https://affise.tds/click?pid=610&offer_id=902&l=3&sub1={clickid}&sub8=a.b-c&aff_sub=x%2By"+
+			"&url=https%3A%2F%2Fshop.example.com%2Fitem%3Fid%3D1&fbclid=q+w", l.String())
+
+		parsed, err := tracking.Parse(l.String())
+		require.NoError(t, err)
+		require.Equal(t, l, parsed)
+		require.Equal(t, "{clickid}", parsed.Sub(1))
+	})
+
+	t.Run("Plain", func(t *testing.T) {
+		t.Parallel()
+
+		l, err := tracking.Build(&affise.Offer{ID: 902, DomainURL: "affise.tds"}, 610)
+		require.NoError(t, err)
+		require.Equal(t, "http://affise.tds/click?pid=610&offer_id=902", l.String())
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := tracking.Build(offer, 610, tracking.WithSub(2, "a b"))
+		require.True(t, errors.Is(err, tracking.ErrInvalidSub))
+		require.EqualError(t, err, `sub2: tracking: invalid sub value "a b": character ' ' is not allowed`)
+
+		_, err = tracking.Build(offer, 610, tracking.WithSub(9, "a"))
+		require.True(t, errors.Is(err, tracking.ErrInvalidSub))
+
+		_, err = tracking.Build(offer, 610, tracking.WithLanding(4))
+		require.True(t, errors.Is(err, tracking.ErrUnknownLanding))
+
+		_, err = tracking.Build(&affise.Offer{ID: 1, DomainURL: "affise.tds"}, 610, tracking.WithDeepLink("https://example.com"))
+		require.True(t, errors.Is(err, tracking.ErrDeepLinkNotAllowed))
+
+		_, err = tracking.Build(offer, 610, tracking.WithDeepLink("example.com"))
+		require.Error(t, err)
+
+		_, err = tracking.Build(&affise.Offer{ID: 1}, 610)
+		require.True(t, errors.Is(err, tracking.ErrNoDomain))
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	l, err := tracking.Parse("http://affise.tds/click?pid=610&offer_id=902&sub3=abc&utm_source=x")
+	require.NoError(t, err)
+	require.Equal(t, &tracking.Link{
+		Domain:      "affise.tds",
+		AffiliateID: 610,
+		OfferID:     902,
+		Subs:        [tracking.MaxSub]string{2: "abc"},
+		Params:      url.Values{"utm_source": {"x"}},
+	}, l)
+
+	for _, raw := range []string{
+		"http://affise.tds/other?pid=610&offer_id=902",
+		"http://affise.tds/click?offer_id=902",
+		"http://affise.tds/click?pid=610&offer_id=902&l=main",
+		"%",
+	} {
+		_, err := tracking.Parse(raw)
+		require.True(t, errors.Is(err, tracking.ErrNotTrackingLink), raw)
+	}
+}